@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
+)
+
+type AuctionController interface {
+	FindAuction(ctx *gin.Context)
+	FindAllBid(ctx *gin.Context)
+	PlaceBid(ctx *gin.Context)
+}
+
+type auctionController struct {
+	auction services.AuctionService
+}
+
+func NewAuctionController() (controller AuctionController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &auctionController{
+		auction: service.Auction,
+	}, nil
+}
+
+// FindAuction godoc
+// @Summary 取得拍賣資訊與目前最高出價
+// @Tags auction
+// @produce application/json
+// @Param creationId query string false "search by creationId"
+// @Success 200 {object}  adapter.DataResp{data=services.AuctionDto} "成功後返回的值"
+// @Router /api/auction/findAuction [get]
+func (controller *auctionController) FindAuction(ctx *gin.Context) {
+	creationId := ctx.Query("creationId")
+	auction, err := controller.auction.FindAuction(context.TODO(), creationId)
+	respondWithData(ctx, auction, err)
+}
+
+// FindAllBid godoc
+// @Summary 取得所有出價
+// @Tags auction
+// @produce application/json
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param creationId query string false "search by creationId"
+// @Param bidder query string false "search by bidder"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.BidDto} "成功後返回的值"
+// @Router /api/auction/findAllBid [get]
+func (controller *auctionController) FindAllBid(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.BidSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	filter, err := getBidFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	var bids []services.BidDto
+
+	if pageable.Page < 0 {
+		bids, err = controller.auction.FindAllBidByFilter(context.TODO(), filter)
+	} else {
+		bids, err = controller.auction.FindAllBidByFilterAndPage(context.TODO(), filter, *pageable)
+	}
+	respondWithData(ctx, bids, err)
+}
+
+// PlaceBid godoc
+// @Summary 拍賣出價
+// @Tags auction
+// @produce application/json
+// @Param PlaceBidDto body services.PlaceBidDto true "出價資料"
+// @Success 200 {object}  adapter.DataResp{data=services.BidDto} "成功後返回的值"
+// @Router /api/auction/placeBid [post]
+// @Security JWT
+func (controller *auctionController) PlaceBid(ctx *gin.Context) {
+	bid := services.PlaceBidDto{}
+	if err := ctx.ShouldBindJSON(&bid); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("PlaceBid")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.auction.PlaceBid(transactionCtx, bid)
+	}
+	result, err := txn.With(contextOf(ctx), callback)
+	respondWithData(ctx, result, err)
+}
+
+func getBidFilterFromQuery(ctx *gin.Context) (filter services.BidFilterDto, err error) {
+	if creationId := ctx.Query("creationId"); len(creationId) > 0 {
+		filter.CreationID = &creationId
+	}
+	if bidder := ctx.Query("bidder"); len(bidder) > 0 {
+		filter.Bidder = &bidder
+	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.BidFilterFields)
+	return
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+)
+
+type AuthController interface {
+	Nonce(ctx *gin.Context)
+	Login(ctx *gin.Context)
+	Refresh(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
+}
+
+type authController struct {
+	auth services.AuthService
+}
+
+func NewAuthController() (controller AuthController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &authController{
+		auth: service.Auth,
+	}, nil
+}
+
+// Nonce godoc
+// @Summary 取得錢包登入的簽名訊息
+// @Tags auth
+// @produce application/json
+// @Param account query string true "ether account"
+// @Success 200 {object}  adapter.DataResp{data=services.NonceDto} "成功後返回的值"
+// @Router /api/auth/nonce [get]
+func (controller *authController) Nonce(ctx *gin.Context) {
+	account := ctx.Query("account")
+	nonce, err := controller.auth.IssueNonce(context.TODO(), account)
+	respondWithData(ctx, nonce, err)
+}
+
+// Login godoc
+// @Summary 錢包簽名登入
+// @Tags auth
+// @produce application/json
+// @Param LoginDto body services.LoginDto true "登入資料"
+// @Success 200 {object}  adapter.DataResp{data=services.TokenDto} "成功後返回的值"
+// @Router /api/auth/login [post]
+func (controller *authController) Login(ctx *gin.Context) {
+	login := services.LoginDto{}
+	if err := ctx.ShouldBindJSON(&login); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	token, err := controller.auth.Login(context.TODO(), login)
+	respondWithData(ctx, token, err)
+}
+
+// Refresh godoc
+// @Summary 以refresh token換發新的token
+// @Tags auth
+// @produce application/json
+// @Param RefreshDto body services.RefreshDto true "refresh token"
+// @Success 200 {object}  adapter.DataResp{data=services.TokenDto} "成功後返回的值"
+// @Router /api/auth/refresh [post]
+func (controller *authController) Refresh(ctx *gin.Context) {
+	refresh := services.RefreshDto{}
+	if err := ctx.ShouldBindJSON(&refresh); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	token, err := controller.auth.Refresh(context.TODO(), refresh)
+	respondWithData(ctx, token, err)
+}
+
+// Revoke godoc
+// @Summary 註銷token
+// @Tags auth
+// @produce application/json
+// @Param RevokeDto body services.RevokeDto true "要註銷的token"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/auth/revoke [post]
+func (controller *authController) Revoke(ctx *gin.Context) {
+	revoke := services.RevokeDto{}
+	if err := ctx.ShouldBindJSON(&revoke); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.auth.Revoke(context.TODO(), revoke)
+	respond(ctx, err)
+}
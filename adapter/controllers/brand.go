@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
 	"time"
 )
 
@@ -13,6 +14,10 @@ type BrandController interface {
 	PostBrand(ctx *gin.Context)
 	UpdateBrand(ctx *gin.Context)
 	DeleteBrand(ctx *gin.Context)
+	FindAllMember(ctx *gin.Context)
+	AddMember(ctx *gin.Context)
+	UpdateMember(ctx *gin.Context)
+	RemoveMember(ctx *gin.Context)
 }
 
 type brandController struct {
@@ -36,7 +41,6 @@ func NewBrandController() (controller BrandController, err error) {
 // @Param brandId query string false "search by brandId"
 // @Success 200 {object}  adapter.DataResp{data=services.BrandDto} "成功後返回的值"
 // @Router /api/brand/findBrand [get]
-// @Security JWT
 func (controller *brandController) FindBrand(ctx *gin.Context) {
 	id := ctx.Query("brandId")
 	brand, err := controller.brand.FindBrandById(context.TODO(), id)
@@ -52,14 +56,14 @@ func (controller *brandController) FindBrand(ctx *gin.Context) {
 // @Param name query string false "search by name"
 // @Param page query string false "search by page"
 // @Param size query string false "search by size"
-// @Param sort query string false "search by sort"
-// @Param order query int false "search by order"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
 // @Success 200 {object}  adapter.DataResp{data=[]services.BrandDto} "成功後返回的值"
 // @Router /api/brand/findAllBrand [get]
-// @Security JWT
 func (controller *brandController) FindAllBrand(ctx *gin.Context) {
 
-	pageable, err := getPageFromQuery(ctx)
+	pageable, err := getPageFromQuery(ctx, services.BrandSortFields)
 	if err != nil {
 		respondWithData(ctx, nil, err)
 		return
@@ -94,7 +98,18 @@ func (controller *brandController) PostBrand(ctx *gin.Context) {
 		respondWithData(ctx, nil, err)
 		return
 	}
-	brand, err := controller.brand.PostBrand(context.TODO(), post)
+
+	txn, err := transactions.NewTransaction("PostBrand")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.brand.PostBrand(transactionCtx, post)
+	}
+	brand, err := txn.With(contextOf(ctx), callback)
 	respondWithData(ctx, brand, err)
 }
 
@@ -112,7 +127,18 @@ func (controller *brandController) UpdateBrand(ctx *gin.Context) {
 		respond(ctx, err)
 		return
 	}
-	err := controller.brand.UpdateBrand(context.TODO(), post)
+
+	txn, err := transactions.NewTransaction("UpdateBrand")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.brand.UpdateBrand(transactionCtx, post)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
 	respond(ctx, err)
 }
 
@@ -126,7 +152,119 @@ func (controller *brandController) UpdateBrand(ctx *gin.Context) {
 // @Security JWT
 func (controller *brandController) DeleteBrand(ctx *gin.Context) {
 	id := ctx.Query("brandId")
-	err := controller.brand.DeleteBrand(context.TODO(), id)
+
+	txn, err := transactions.NewTransaction("DeleteBrand")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.brand.DeleteBrand(transactionCtx, id)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
+	respond(ctx, err)
+}
+
+// FindAllMember godoc
+// @Summary 取得品牌成員
+// @Tags brand
+// @produce application/json
+// @Param brandId query string true "search by brandId"
+// @Success 200 {object}  adapter.DataResp{data=[]services.BrandMemberDto} "成功後返回的值"
+// @Router /api/brand/findAllMember [get]
+// @Security JWT
+func (controller *brandController) FindAllMember(ctx *gin.Context) {
+	id := ctx.Query("brandId")
+	members, err := controller.brand.FindAllMember(contextOf(ctx), id)
+	respondWithData(ctx, members, err)
+}
+
+// AddMember godoc
+// @Summary 新增品牌成員
+// @Tags brand
+// @produce application/json
+// @Param BrandMemberDto body services.BrandMemberDto true "成員資料,role為OWNER/ADMIN/EDITOR"
+// @Success 200 {object}  adapter.DataResp{data=services.BrandMemberDto} "成功後返回的值"
+// @Router /api/brand/addMember [post]
+// @Security JWT
+func (controller *brandController) AddMember(ctx *gin.Context) {
+	member := services.BrandMemberDto{}
+	if err := ctx.ShouldBindJSON(&member); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("AddMember")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.brand.AddMember(transactionCtx, member)
+	}
+	result, err := txn.With(contextOf(ctx), callback)
+	respondWithData(ctx, result, err)
+}
+
+// UpdateMember godoc
+// @Summary 更新品牌成員角色
+// @Tags brand
+// @produce application/json
+// @Param BrandMemberDto body services.BrandMemberDto true "成員資料,role為OWNER/ADMIN/EDITOR"
+// @Success 200 {object}  adapter.DataResp{data=services.BrandMemberDto} "成功後返回的值"
+// @Router /api/brand/updateMember [post]
+// @Security JWT
+func (controller *brandController) UpdateMember(ctx *gin.Context) {
+	member := services.BrandMemberDto{}
+	if err := ctx.ShouldBindJSON(&member); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("UpdateMember")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.brand.UpdateMember(transactionCtx, member)
+	}
+	result, err := txn.With(contextOf(ctx), callback)
+	respondWithData(ctx, result, err)
+}
+
+// RemoveMember godoc
+// @Summary 移除品牌成員
+// @Tags brand
+// @produce application/json
+// @Param RemoveBrandMemberDto body services.RemoveBrandMemberDto true "成員資料"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/brand/removeMember [post]
+// @Security JWT
+func (controller *brandController) RemoveMember(ctx *gin.Context) {
+	member := services.RemoveBrandMemberDto{}
+	if err := ctx.ShouldBindJSON(&member); err != nil {
+		respond(ctx, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("RemoveMember")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.brand.RemoveMember(transactionCtx, member)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
 	respond(ctx, err)
 }
 
@@ -150,5 +288,6 @@ func getBrandFilterFromQuery(ctx *gin.Context) (filter services.BrandFilterDto,
 		}
 		filter.CreateBefore = &createBefore
 	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.BrandFilterFields)
 	return
 }
@@ -9,10 +9,13 @@ import (
 type CollectionController interface {
 	FindCollection(ctx *gin.Context)
 	FindAllCollection(ctx *gin.Context)
+	FindAllHolder(ctx *gin.Context)
+	FindHolderSummary(ctx *gin.Context)
 }
 
 type collectController struct {
 	collection services.CollectService
+	holder     services.HolderService
 }
 
 func NewCollectionController() (controller CollectionController, err error) {
@@ -22,6 +25,7 @@ func NewCollectionController() (controller CollectionController, err error) {
 	}
 	return &collectController{
 		collection: service.Collection,
+		holder:     service.Holder,
 	}, nil
 }
 
@@ -48,12 +52,14 @@ func (controller *collectController) FindCollection(ctx *gin.Context) {
 // @Param creationId query string false "search by creationId"
 // @Param page query string false "search by page"
 // @Param size query string false "search by size"
-// @Param sort query string false "search by sort"
-// @Param order query int false "search by order"
-// @Success 200 {object}  adapter.DataResp{data=services.CollectDto} "成功後返回的值"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param cursor query string false "cursor of the next page, send it empty for the first one"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.CollectDto} "成功後返回的值, cursor mode returns utils.Page"
 // @Router /api/collection/findAllCollection [get]
 func (controller *collectController) FindAllCollection(ctx *gin.Context) {
-	pageable, err := getPageFromQuery(ctx)
+	pageable, err := getPageFromQuery(ctx, services.CollectSortFields)
 	if err != nil {
 		respondWithData(ctx, nil, err)
 		return
@@ -66,6 +72,11 @@ func (controller *collectController) FindAllCollection(ctx *gin.Context) {
 	}
 
 	var collections []services.CollectDto
+	if pageable.IsCursor() {
+		collections, next, err := controller.collection.FindAllCollectByFilterAndCursor(context.TODO(), filter, *pageable)
+		respondWithCursor(ctx, pageable, collections, next, err)
+		return
+	}
 	if pageable.Page < 0 {
 		collections, err = controller.collection.FindAllCollectByFilter(context.TODO(), filter)
 		if err != nil {
@@ -82,6 +93,52 @@ func (controller *collectController) FindAllCollection(ctx *gin.Context) {
 	respondWithData(ctx, collections, err)
 }
 
+// FindAllHolder godoc
+// @Summary 取得持有者排行
+// @Tags collection
+// @produce application/json
+// @Param creationId query string false "search by creationId"
+// @Param brandId query string false "search by brandId"
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Success 200 {object}  adapter.DataResp{data=[]services.HolderDto} "成功後返回的值"
+// @Router /api/collection/findAllHolder [get]
+func (controller *collectController) FindAllHolder(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, nil)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	if pageable.Page < 0 || pageable.Size <= 0 {
+		pageable.Page, pageable.Size = 0, 10
+	}
+	holders, err := controller.holder.FindAllHolderByFilterAndPage(context.TODO(), getHolderFilterFromQuery(ctx), *pageable)
+	respondWithData(ctx, holders, err)
+}
+
+// FindHolderSummary godoc
+// @Summary 取得持有者分布
+// @Tags collection
+// @produce application/json
+// @Param creationId query string false "search by creationId"
+// @Param brandId query string false "search by brandId"
+// @Success 200 {object}  adapter.DataResp{data=services.HolderSummaryDto} "成功後返回的值"
+// @Router /api/collection/findHolderSummary [get]
+func (controller *collectController) FindHolderSummary(ctx *gin.Context) {
+	summary, err := controller.holder.FindHolderSummary(context.TODO(), getHolderFilterFromQuery(ctx))
+	respondWithData(ctx, summary, err)
+}
+
+func getHolderFilterFromQuery(ctx *gin.Context) (filter services.HolderFilterDto) {
+	if creationId := ctx.Query("creationId"); len(creationId) > 0 {
+		filter.CreationID = &creationId
+	}
+	if brandId := ctx.Query("brandId"); len(brandId) > 0 {
+		filter.BrandID = &brandId
+	}
+	return
+}
+
 func getCollectionFilterFromQuery(ctx *gin.Context) (filter services.CollectFilterDto, err error) {
 	if owner := ctx.Query("owner"); len(owner) > 0 {
 		filter.Owner = &owner
@@ -89,5 +146,6 @@ func getCollectionFilterFromQuery(ctx *gin.Context) (filter services.CollectFilt
 	if creationId := ctx.Query("creationId"); len(creationId) > 0 {
 		filter.CreationID = &creationId
 	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.CollectFilterFields)
 	return
 }
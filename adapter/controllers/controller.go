@@ -1,13 +1,16 @@
 package controllers
 
 import (
+	"context"
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"nftshopping-store-api/adapter"
 	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/security"
 	"nftshopping-store-api/pkg/utils"
 	"strconv"
+	"strings"
 )
 
 var controllerInstance *controller
@@ -24,16 +27,27 @@ func GetController() (instance *controller, err error) {
 }
 
 type controller struct {
-	User       UserController
-	Creation   CreationController
-	Item       ItemController
-	Collection CollectionController
-	Trade      TradeController
-	Brand      BrandController
-	Stock      StockController
+	Auth           AuthController
+	User           UserController
+	Creation       CreationController
+	Item           ItemController
+	Collection     CollectionController
+	Trade          TradeController
+	Brand          BrandController
+	Stock          StockController
+	Order          OrderController
+	Auction        AuctionController
+	Market         MarketController
+	ServiceAccount ServiceAccountController
+	Policy         PolicyController
+	DeadLetter     DeadLetterController
 }
 
 func newController() (instance *controller, err error) {
+	auth, err := NewAuthController()
+	if err != nil {
+		return
+	}
 	user, err := NewUserController()
 	if err != nil {
 		return
@@ -62,18 +76,51 @@ func newController() (instance *controller, err error) {
 	if err != nil {
 		return
 	}
+	order, err := NewOrderController()
+	if err != nil {
+		return
+	}
+	auction, err := NewAuctionController()
+	if err != nil {
+		return
+	}
+	market, err := NewMarketController()
+	if err != nil {
+		return
+	}
+	serviceAccount, err := NewServiceAccountController()
+	if err != nil {
+		return
+	}
+	policy, err := NewPolicyController()
+	if err != nil {
+		return
+	}
+	deadLetter, err := NewDeadLetterController()
+	if err != nil {
+		return
+	}
 	return &controller{
-		User:       user,
-		Creation:   creation,
-		Item:       item,
-		Collection: collection,
-		Trade:      trade,
-		Brand:      brand,
-		Stock:      stock,
+		Auth:           auth,
+		User:           user,
+		Creation:       creation,
+		Item:           item,
+		Collection:     collection,
+		Trade:          trade,
+		Brand:          brand,
+		Stock:          stock,
+		Order:          order,
+		Auction:        auction,
+		Market:         market,
+		ServiceAccount: serviceAccount,
+		Policy:         policy,
+		DeadLetter:     deadLetter,
 	}, nil
 }
 
-func getPageFromQuery(ctx *gin.Context) (pageable *utils.Pageable, err error) {
+// getPageFromQuery reads sort as "field:asc|desc" terms separated by commas, only the fields of
+// the whitelist are accepted; the legacy order param still applies to a single field without one.
+func getPageFromQuery(ctx *gin.Context, fields utils.SortFields) (pageable *utils.Pageable, err error) {
 	page, err := strconv.Atoi(ctx.DefaultQuery("page", "0"))
 	if err != nil {
 		return
@@ -82,19 +129,52 @@ func getPageFromQuery(ctx *gin.Context) (pageable *utils.Pageable, err error) {
 	if err != nil {
 		return
 	}
-	var sort map[string]int
-	if sortType := ctx.Query("sort"); len(sortType) != 0 {
-		order, err := strconv.Atoi(ctx.DefaultQuery("order", "1"))
-		if err != nil {
-			return nil, err
+	var sort []utils.Sort
+	if expression := ctx.Query("sort"); len(expression) != 0 {
+		if order := ctx.Query("order"); len(order) != 0 && !strings.ContainsAny(expression, ":,") {
+			expression += ":" + order
 		}
-		sort = map[string]int{
-			sortType: order,
+		sort, err = utils.ParseSort(expression, fields)
+		if err != nil {
+			return
 		}
 	}
-	return &utils.Pageable{
+	pageable = &utils.Pageable{
 		Size: size, Page: page, Sort: sort,
-	}, nil
+	}
+	// the presence of cursor, even empty, switches to cursor mode
+	if cursor, isExist := ctx.GetQuery("cursor"); isExist {
+		pageable.Cursor = &cursor
+	}
+	return pageable, nil
+}
+
+// getConditionsFromQuery reads the filter param against the fields a resource can be filtered on.
+func getConditionsFromQuery(ctx *gin.Context, fields utils.FilterFields) (conditions []utils.Condition, err error) {
+	if expression := ctx.Query("filter"); len(expression) != 0 {
+		return utils.ParseFilter(expression, fields)
+	}
+	return
+}
+
+// respondWithCursor answers a list read in cursor mode with the cursor of its next page.
+func respondWithCursor(ctx *gin.Context, pageable *utils.Pageable, content interface{}, next string, err error) {
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	respondWithData(ctx, &utils.Page{Size: pageable.Size, NextCursor: next, Content: content}, nil)
+}
+
+// contextOf carries the authenticated caller of the request into the services.
+func contextOf(ctx *gin.Context) context.Context {
+	c := context.Background()
+	if authentication, isExist := ctx.Get("Authentication"); isExist {
+		if auth, ok := authentication.(security.Authentication); ok {
+			c = security.WithAuthentication(c, auth)
+		}
+	}
+	return c
 }
 
 func respondWithData(ctx *gin.Context, data interface{}, err error) {
@@ -5,6 +5,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
+	"nftshopping-store-api/pkg/utils"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +18,9 @@ type CreationController interface {
 	PostCreation(ctx *gin.Context)
 	DeleteCreation(ctx *gin.Context)
 	UpdateCreation(ctx *gin.Context)
+	UpdateSaleStatus(ctx *gin.Context)
+	SearchCreation(ctx *gin.Context)
+	AutocompleteCreation(ctx *gin.Context)
 }
 
 type creationController struct {
@@ -39,7 +44,6 @@ func NewCreationController() (controller CreationController, err error) {
 // @Param creationId query string false "search by creationId"
 // @Success 200 {object}  adapter.DataResp{data=services.CreationDto} "成功後返回的值"
 // @Router /api/creation/findCreation [get]
-// @Security JWT
 func (controller *creationController) FindCreation(ctx *gin.Context) {
 	creationId := ctx.Query("creationId")
 	creation, err := controller.creation.FindCreationByID(context.TODO(), creationId)
@@ -59,15 +63,17 @@ func (controller *creationController) FindCreation(ctx *gin.Context) {
 // @Param maxPrice query int false "search by maxPrice"
 // @Param minPrice query int false "search by minPrice"
 // @Param creationName query string false "search by creationName"
+// @Param saleStatus query string false "search by saleStatus"
 // @Param creator query string false "search by creator"
 // @Param brandId query string false "search by brandId"
-// @Param sort query string false "search by sort"
-// @Param order query int false "search by order"
-// @Success 200 {object}  adapter.DataResp{data=[]services.CreationDto} "成功後返回的值"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param cursor query string false "cursor of the next page, send it empty for the first one"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.CreationDto} "成功後返回的值, cursor mode returns utils.Page"
 // @Router /api/creation/findAllCreation [get]
-// @Security JWT
 func (controller *creationController) FindAllCreation(ctx *gin.Context) {
-	pageable, err := getPageFromQuery(ctx)
+	pageable, err := getPageFromQuery(ctx, services.CreationSortFields)
 	if err != nil {
 		respondWithData(ctx, nil, err)
 		return
@@ -81,6 +87,11 @@ func (controller *creationController) FindAllCreation(ctx *gin.Context) {
 
 	var creations []services.CreationDto
 
+	if pageable.IsCursor() {
+		creations, next, err := controller.creation.FindAllCreationByFilterAndCursor(context.TODO(), filter, *pageable)
+		respondWithCursor(ctx, pageable, creations, next, err)
+		return
+	}
 	if pageable.Page < 0 {
 		creations, err = controller.creation.FindAllCreationByFilter(context.TODO(), filter)
 	} else {
@@ -103,7 +114,18 @@ func (controller *creationController) PostCreation(ctx *gin.Context) {
 		respondWithData(ctx, nil, err)
 		return
 	}
-	creation, err := controller.creation.PostCreation(context.TODO(), postCreation)
+
+	txn, err := transactions.NewTransaction("PostCreation")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.creation.PostCreation(transactionCtx, postCreation)
+	}
+	creation, err := txn.With(contextOf(ctx), callback)
 	respondWithData(ctx, creation, err)
 }
 
@@ -119,8 +141,20 @@ func (controller *creationController) DeleteCreation(ctx *gin.Context) {
 	id, err := primitive.ObjectIDFromHex(ctx.Query("id"))
 	if err != nil {
 		respond(ctx, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("DeleteCreation")
+	if err != nil {
+		respond(ctx, err)
+		return
 	}
-	err = controller.creation.DeleteCreation(context.TODO(), id)
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.creation.DeleteCreation(transactionCtx, id)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
 	respond(ctx, err)
 }
 
@@ -138,10 +172,114 @@ func (controller *creationController) UpdateCreation(ctx *gin.Context) {
 		respond(ctx, err)
 		return
 	}
-	err := controller.creation.UpdateCreation(context.TODO(), creation)
+
+	txn, err := transactions.NewTransaction("UpdateCreation")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.creation.UpdateCreation(transactionCtx, creation)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
+	respond(ctx, err)
+}
+
+// UpdateSaleStatus godoc
+// @Summary 更新藝術品販售狀態
+// @Tags creation
+// @produce application/json
+// @Param updateSaleStatus body services.UpdateSaleStatusDto true "販售狀態"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/creation/updateSaleStatus [post]
+// @Security JWT
+func (controller *creationController) UpdateSaleStatus(ctx *gin.Context) {
+	saleStatus := services.UpdateSaleStatusDto{}
+	if err := ctx.ShouldBindJSON(&saleStatus); err != nil {
+		respond(ctx, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("UpdateSaleStatus")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.creation.UpdateSaleStatus(transactionCtx, saleStatus)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
 	respond(ctx, err)
 }
 
+// SearchCreation godoc
+// @Summary 搜尋藝術品
+// @Tags creation
+// @produce application/json
+// @Param text query string false "search by words of name, creator or description"
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param creationIds query string false "search by creationIds"
+// @Param properties query string false "search by properties"
+// @Param saleStartBefore query string false "search by saleStartBefore"
+// @Param saleStartAfter query string false "search by saleStartAfter"
+// @Param maxPrice query int false "search by maxPrice"
+// @Param minPrice query int false "search by minPrice"
+// @Param saleStatus query string false "search by saleStatus"
+// @Param creator query string false "search by creator"
+// @Param brandId query string false "search by brandId"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=services.CreationSearchDto} "成功後返回的值"
+// @Router /api/creation/search [get]
+func (controller *creationController) SearchCreation(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.CreationSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	// a search always reads one page, unlike the lists a negative page does not mean all
+	if pageable.Page < 0 || pageable.Size <= 0 {
+		respondWithData(ctx, nil, utils.ErrPageInvalid)
+		return
+	}
+
+	filter, err := getCreationFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	search, err := controller.creation.SearchCreation(context.TODO(), services.CreationSearchFilterDto{
+		CreationFilterDto: filter,
+		Text:              ctx.Query("text"),
+	}, *pageable)
+	respondWithData(ctx, search, err)
+}
+
+// AutocompleteCreation godoc
+// @Summary 藝術品名稱自動完成
+// @Tags creation
+// @produce application/json
+// @Param prefix query string true "beginning of the creation name"
+// @Param limit query int false "max suggestions, up to 20"
+// @Success 200 {object}  adapter.DataResp{data=[]services.CreationSuggestionDto} "成功後返回的值"
+// @Router /api/creation/autocomplete [get]
+func (controller *creationController) AutocompleteCreation(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	suggestions, err := controller.creation.AutocompleteCreation(context.TODO(), ctx.Query("prefix"), limit)
+	respondWithData(ctx, suggestions, err)
+}
+
 func getCreationFilterFromQuery(ctx *gin.Context) (filter services.CreationFilterDto, err error) {
 	if creationIds := ctx.Query("creationIds"); len(creationIds) > 0 {
 		filter.CreationIDs = strings.Split(creationIds, ",")
@@ -154,6 +292,10 @@ func getCreationFilterFromQuery(ctx *gin.Context) (filter services.CreationFilte
 		filter.CreationName = &creationName
 	}
 
+	if saleStatus := ctx.Query("saleStatus"); len(saleStatus) > 0 {
+		filter.SaleStatus = strings.Split(saleStatus, ",")
+	}
+
 	if creator := ctx.Query("creator"); len(creator) > 0 {
 		filter.Creator = &creator
 	}
@@ -193,5 +335,6 @@ func getCreationFilterFromQuery(ctx *gin.Context) (filter services.CreationFilte
 		}
 		filter.MaxPrice = &maxPrice
 	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.CreationFilterFields)
 	return
 }
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
+	"strings"
+)
+
+type DeadLetterController interface {
+	FindDeadLetter(ctx *gin.Context)
+	FindAllDeadLetter(ctx *gin.Context)
+	ReplayDeadLetter(ctx *gin.Context)
+	DiscardDeadLetter(ctx *gin.Context)
+}
+
+type deadLetterController struct {
+	deadLetter services.DeadLetterService
+}
+
+func NewDeadLetterController() (controller DeadLetterController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &deadLetterController{
+		deadLetter: service.DeadLetter,
+	}, nil
+}
+
+// FindDeadLetter godoc
+// @Summary 取得死信資訊
+// @Tags deadLetter
+// @produce application/json
+// @Param deadLetterId query string true "search by deadLetterId"
+// @Success 200 {object}  adapter.DataResp{data=services.DeadLetterDto} "成功後返回的值"
+// @Router /api/deadLetter/findDeadLetter [get]
+// @Security JWT
+func (controller *deadLetterController) FindDeadLetter(ctx *gin.Context) {
+	deadLetterId := ctx.Query("deadLetterId")
+	deadLetter, err := controller.deadLetter.FindDeadLetter(context.TODO(), deadLetterId)
+	respondWithData(ctx, deadLetter, err)
+}
+
+// FindAllDeadLetter godoc
+// @Summary 取得所有死信
+// @Tags deadLetter
+// @produce application/json
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param topic query string false "search by topic"
+// @Param handler query string false "search by handler"
+// @Param status query string false "search by status"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like attempts>=3;status~in~(PENDING,REPLAYED)"
+// @Success 200 {object}  adapter.DataResp{data=[]services.DeadLetterDto} "成功後返回的值"
+// @Router /api/deadLetter/findAllDeadLetter [get]
+// @Security JWT
+func (controller *deadLetterController) FindAllDeadLetter(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.DeadLetterSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	filter, err := getDeadLetterFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	var deadLetters []services.DeadLetterDto
+
+	if pageable.Page < 0 {
+		deadLetters, err = controller.deadLetter.FindAllDeadLetterByFilter(context.TODO(), filter)
+	} else {
+		deadLetters, err = controller.deadLetter.FindAllDeadLetterByFilterAndPage(context.TODO(), filter, *pageable)
+	}
+	respondWithData(ctx, deadLetters, err)
+}
+
+// ReplayDeadLetter godoc
+// @Summary 重送死信到原本的topic
+// @Tags deadLetter
+// @produce application/json
+// @Param SettleDeadLetterDto body services.SettleDeadLetterDto true "死信"
+// @Success 200 {object}  adapter.DataResp{data=services.DeadLetterDto} "成功後返回的值"
+// @Router /api/deadLetter/replayDeadLetter [post]
+// @Security JWT
+func (controller *deadLetterController) ReplayDeadLetter(ctx *gin.Context) {
+	replay := services.SettleDeadLetterDto{}
+	if err := ctx.ShouldBindJSON(&replay); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("ReplayDeadLetter")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.deadLetter.ReplayDeadLetter(transactionCtx, replay)
+	}
+	result, err := txn.With(context.Background(), callback)
+	respondWithData(ctx, result, err)
+}
+
+// DiscardDeadLetter godoc
+// @Summary 捨棄死信
+// @Tags deadLetter
+// @produce application/json
+// @Param SettleDeadLetterDto body services.SettleDeadLetterDto true "死信"
+// @Success 200 {object}  adapter.DataResp{data=services.DeadLetterDto} "成功後返回的值"
+// @Router /api/deadLetter/discardDeadLetter [post]
+// @Security JWT
+func (controller *deadLetterController) DiscardDeadLetter(ctx *gin.Context) {
+	discard := services.SettleDeadLetterDto{}
+	if err := ctx.ShouldBindJSON(&discard); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.deadLetter.DiscardDeadLetter(context.TODO(), discard)
+	respondWithData(ctx, result, err)
+}
+
+func getDeadLetterFilterFromQuery(ctx *gin.Context) (filter services.DeadLetterFilterDto, err error) {
+	if topic := ctx.Query("topic"); len(topic) > 0 {
+		filter.Topic = &topic
+	}
+
+	if handler := ctx.Query("handler"); len(handler) > 0 {
+		filter.Handler = &handler
+	}
+
+	if status := ctx.Query("status"); len(status) > 0 {
+		filter.Status = strings.Split(status, ",")
+	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.DeadLetterFilterFields)
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
 )
 
 type ItemController interface {
@@ -33,17 +34,32 @@ func NewItemController() (controller ItemController, err error) {
 // @Tags item
 // @produce application/json
 // @Param OrderItemDto body services.OrderItemDto true "商品訂購資料"
-// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Success 200 {object}  adapter.DataResp{data=services.OrderDto} "成功後返回的值"
 // @Router /api/item/orderItem [post]
 // @Security JWT
 func (controller *itemController) OrderItem(ctx *gin.Context) {
 	order := services.OrderItemDto{}
 	if err := ctx.ShouldBindJSON(&order); err != nil {
-		respond(ctx, err)
+		respondWithData(ctx, nil, err)
 		return
 	}
-	err := controller.item.OrderItem(context.TODO(), order)
-	respond(ctx, err)
+
+	txn, err := transactions.NewTransaction("OrderItem")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		order, err := controller.item.OrderItem(transactionCtx, order)
+		if err != nil {
+			return nil, err
+		}
+		return order, nil
+	}
+	result, err := txn.With(contextOf(ctx), callback)
+	respondWithData(ctx, result, err)
 }
 
 // DeliverItem godoc
@@ -60,7 +76,22 @@ func (controller *itemController) DeliverItem(ctx *gin.Context) {
 		respondWithData(ctx, nil, err)
 		return
 	}
-	item, err := controller.item.DeliverItem(context.TODO(), delivery)
+
+	txn, err := transactions.NewTransaction("DeliverItem")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		item, err := controller.item.DeliverItem(transactionCtx, delivery)
+		if err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+	item, err := txn.With(context.Background(), callback)
 	respondWithData(ctx, item, err)
 }
 
@@ -87,13 +118,15 @@ func (controller *itemController) FindItem(ctx *gin.Context) {
 // @Param brandOwner query string false "search by brandOwner"
 // @Param page query string false "search by page"
 // @Param size query string false "search by size"
-// @Param sort query string false "search by sort"
-// @Param order query int false "search by order"
-// @Success 200 {object}  adapter.DataResp{data=[]services.ItemDto} "成功後返回的值"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param cursor query string false "cursor of the next page, send it empty for the first one"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.ItemDto} "成功後返回的值, cursor mode returns utils.Page"
 // @Router /api/item/findAllItem [get]
 // @Security JWT
 func (controller *itemController) FindAllItem(ctx *gin.Context) {
-	pageable, err := getPageFromQuery(ctx)
+	pageable, err := getPageFromQuery(ctx, services.ItemSortFields)
 	if err != nil {
 		respondWithData(ctx, nil, err)
 		return
@@ -107,6 +140,11 @@ func (controller *itemController) FindAllItem(ctx *gin.Context) {
 
 	var items []services.ItemDto
 
+	if pageable.IsCursor() {
+		items, next, err := controller.item.FindAllItemByFilterAndCursor(context.TODO(), filter, *pageable)
+		respondWithCursor(ctx, pageable, items, next, err)
+		return
+	}
 	if pageable.Page < 0 {
 		items, err = controller.item.FindAllItemByFilter(context.TODO(), filter)
 	} else {
@@ -135,5 +173,6 @@ func getItemFilterFromQuery(ctx *gin.Context) (filter services.ItemFilterDto, er
 	if brandOwner := ctx.Query("brandOwner"); len(brandOwner) > 0 {
 		filter.BrandOwner = &brandOwner
 	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.ItemFilterFields)
 	return
 }
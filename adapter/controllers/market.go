@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
+	"strconv"
+	"strings"
+)
+
+type MarketController interface {
+	FindListing(ctx *gin.Context)
+	FindAllListing(ctx *gin.Context)
+	CreateListing(ctx *gin.Context)
+	CancelListing(ctx *gin.Context)
+	BuyListing(ctx *gin.Context)
+	FindOffer(ctx *gin.Context)
+	FindAllOffer(ctx *gin.Context)
+	MakeOffer(ctx *gin.Context)
+	AcceptOffer(ctx *gin.Context)
+	RejectOffer(ctx *gin.Context)
+}
+
+type marketController struct {
+	market services.MarketService
+}
+
+func NewMarketController() (controller MarketController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &marketController{
+		market: service.Market,
+	}, nil
+}
+
+// FindListing godoc
+// @Summary 取得上架資訊
+// @Tags market
+// @produce application/json
+// @Param listingId query string false "search by listingId"
+// @Success 200 {object}  adapter.DataResp{data=services.ListingDto} "成功後返回的值"
+// @Router /api/market/findListing [get]
+func (controller *marketController) FindListing(ctx *gin.Context) {
+	listingId := ctx.Query("listingId")
+	listing, err := controller.market.FindListing(context.TODO(), listingId)
+	respondWithData(ctx, listing, err)
+}
+
+// FindAllListing godoc
+// @Summary 取得所有上架商品
+// @Tags market
+// @produce application/json
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param creationIds query string false "search by creationIds"
+// @Param creationName query string false "search by creationName"
+// @Param properties query string false "search by properties"
+// @Param creator query string false "search by creator"
+// @Param brandId query string false "search by brandId"
+// @Param seller query string false "search by seller"
+// @Param status query string false "search by status"
+// @Param maxPrice query int false "search by maxPrice"
+// @Param minPrice query int false "search by minPrice"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.ListingDto} "成功後返回的值"
+// @Router /api/market/findAllListing [get]
+func (controller *marketController) FindAllListing(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.ListingSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	filter, err := getListingFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	var listings []services.ListingDto
+
+	if pageable.Page < 0 {
+		listings, err = controller.market.FindAllListingByFilter(context.TODO(), filter)
+	} else {
+		listings, err = controller.market.FindAllListingByFilterAndPage(context.TODO(), filter, *pageable)
+	}
+	respondWithData(ctx, listings, err)
+}
+
+// CreateListing godoc
+// @Summary 上架商品
+// @Tags market
+// @produce application/json
+// @Param CreateListingDto body services.CreateListingDto true "上架資料"
+// @Success 200 {object}  adapter.DataResp{data=services.ListingDto} "成功後返回的值"
+// @Router /api/market/createListing [post]
+// @Security JWT
+func (controller *marketController) CreateListing(ctx *gin.Context) {
+	listing := services.CreateListingDto{}
+	if err := ctx.ShouldBindJSON(&listing); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.market.CreateListing(contextOf(ctx), listing)
+	respondWithData(ctx, result, err)
+}
+
+// CancelListing godoc
+// @Summary 下架商品
+// @Tags market
+// @produce application/json
+// @Param CancelListingDto body services.CancelListingDto true "下架資料"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/market/cancelListing [post]
+// @Security JWT
+func (controller *marketController) CancelListing(ctx *gin.Context) {
+	listing := services.CancelListingDto{}
+	if err := ctx.ShouldBindJSON(&listing); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.market.CancelListing(contextOf(ctx), listing)
+	respond(ctx, err)
+}
+
+// BuyListing godoc
+// @Summary 購買上架商品
+// @Tags market
+// @produce application/json
+// @Param BuyListingDto body services.BuyListingDto true "購買資料"
+// @Success 200 {object}  adapter.DataResp{data=services.TransactionDto} "成功後返回的值"
+// @Router /api/market/buyListing [post]
+// @Security JWT
+func (controller *marketController) BuyListing(ctx *gin.Context) {
+	buy := services.BuyListingDto{}
+	if err := ctx.ShouldBindJSON(&buy); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("BuyListing")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.market.BuyListing(transactionCtx, buy)
+	}
+	result, err := txn.With(contextOf(ctx), callback)
+	respondWithData(ctx, result, err)
+}
+
+// FindOffer godoc
+// @Summary 取得出價資訊
+// @Tags market
+// @produce application/json
+// @Param offerId query string false "search by offerId"
+// @Success 200 {object}  adapter.DataResp{data=services.OfferDto} "成功後返回的值"
+// @Router /api/market/findOffer [get]
+func (controller *marketController) FindOffer(ctx *gin.Context) {
+	offerId := ctx.Query("offerId")
+	offer, err := controller.market.FindOffer(context.TODO(), offerId)
+	respondWithData(ctx, offer, err)
+}
+
+// FindAllOffer godoc
+// @Summary 取得所有出價
+// @Tags market
+// @produce application/json
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param contract query string false "search by contract"
+// @Param token query string false "search by token"
+// @Param buyer query string false "search by buyer"
+// @Param seller query string false "search by seller"
+// @Param status query string false "search by status"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.OfferDto} "成功後返回的值"
+// @Router /api/market/findAllOffer [get]
+func (controller *marketController) FindAllOffer(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.OfferSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	filter, err := getOfferFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	var offers []services.OfferDto
+
+	if pageable.Page < 0 {
+		offers, err = controller.market.FindAllOfferByFilter(context.TODO(), filter)
+	} else {
+		offers, err = controller.market.FindAllOfferByFilterAndPage(context.TODO(), filter, *pageable)
+	}
+	respondWithData(ctx, offers, err)
+}
+
+// MakeOffer godoc
+// @Summary 對商品出價
+// @Tags market
+// @produce application/json
+// @Param MakeOfferDto body services.MakeOfferDto true "出價資料"
+// @Success 200 {object}  adapter.DataResp{data=services.OfferDto} "成功後返回的值"
+// @Router /api/market/makeOffer [post]
+// @Security JWT
+func (controller *marketController) MakeOffer(ctx *gin.Context) {
+	offer := services.MakeOfferDto{}
+	if err := ctx.ShouldBindJSON(&offer); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.market.MakeOffer(contextOf(ctx), offer)
+	respondWithData(ctx, result, err)
+}
+
+// AcceptOffer godoc
+// @Summary 接受出價
+// @Tags market
+// @produce application/json
+// @Param ReplyOfferDto body services.ReplyOfferDto true "回覆出價資料"
+// @Success 200 {object}  adapter.DataResp{data=services.TransactionDto} "成功後返回的值"
+// @Router /api/market/acceptOffer [post]
+// @Security JWT
+func (controller *marketController) AcceptOffer(ctx *gin.Context) {
+	reply := services.ReplyOfferDto{}
+	if err := ctx.ShouldBindJSON(&reply); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("AcceptOffer")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return controller.market.AcceptOffer(transactionCtx, reply)
+	}
+	result, err := txn.With(contextOf(ctx), callback)
+	respondWithData(ctx, result, err)
+}
+
+// RejectOffer godoc
+// @Summary 拒絕出價
+// @Tags market
+// @produce application/json
+// @Param ReplyOfferDto body services.ReplyOfferDto true "回覆出價資料"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/market/rejectOffer [post]
+// @Security JWT
+func (controller *marketController) RejectOffer(ctx *gin.Context) {
+	reply := services.ReplyOfferDto{}
+	if err := ctx.ShouldBindJSON(&reply); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.market.RejectOffer(contextOf(ctx), reply)
+	respond(ctx, err)
+}
+
+func getListingFilterFromQuery(ctx *gin.Context) (filter services.ListingFilterDto, err error) {
+	if creationIds := ctx.Query("creationIds"); len(creationIds) > 0 {
+		filter.CreationIDs = strings.Split(creationIds, ",")
+	}
+
+	if properties := ctx.Query("properties"); len(properties) > 0 {
+		filter.Properties = strings.Split(properties, ",")
+	}
+
+	if creationName := ctx.Query("creationName"); len(creationName) > 0 {
+		filter.CreationName = &creationName
+	}
+
+	if creator := ctx.Query("creator"); len(creator) > 0 {
+		filter.Creator = &creator
+	}
+
+	if brand := ctx.Query("brandId"); len(brand) > 0 {
+		filter.BrandID = &brand
+	}
+
+	if seller := ctx.Query("seller"); len(seller) > 0 {
+		filter.Seller = &seller
+	}
+
+	if status := ctx.Query("status"); len(status) > 0 {
+		filter.Status = strings.Split(status, ",")
+	}
+
+	if min := ctx.Query("minPrice"); len(min) > 0 {
+		minPrice, err := strconv.Atoi(min)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinPrice = &minPrice
+	}
+
+	if max := ctx.Query("maxPrice"); len(max) > 0 {
+		maxPrice, err := strconv.Atoi(max)
+		if err != nil {
+			return filter, err
+		}
+		filter.MaxPrice = &maxPrice
+	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.ListingFilterFields)
+	return
+}
+
+func getOfferFilterFromQuery(ctx *gin.Context) (filter services.OfferFilterDto, err error) {
+	if contract := ctx.Query("contract"); len(contract) > 0 {
+		filter.Contract = &contract
+	}
+
+	if token := ctx.Query("token"); len(token) > 0 {
+		filter.Token = &token
+	}
+
+	if buyer := ctx.Query("buyer"); len(buyer) > 0 {
+		filter.Buyer = &buyer
+	}
+
+	if seller := ctx.Query("seller"); len(seller) > 0 {
+		filter.Seller = &seller
+	}
+
+	if status := ctx.Query("status"); len(status) > 0 {
+		filter.Status = strings.Split(status, ",")
+	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.OfferFilterFields)
+	return
+}
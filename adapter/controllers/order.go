@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
+	"strings"
+	"time"
+)
+
+type OrderController interface {
+	FindOrder(ctx *gin.Context)
+	FindAllOrder(ctx *gin.Context)
+	FailOrder(ctx *gin.Context)
+}
+
+type orderController struct {
+	order services.OrderService
+}
+
+func NewOrderController() (controller OrderController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &orderController{
+		order: service.Order,
+	}, nil
+}
+
+// FindOrder godoc
+// @Summary 取得訂單資訊
+// @Tags order
+// @produce application/json
+// @Param orderId query string false "search by orderId"
+// @Success 200 {object}  adapter.DataResp{data=services.OrderDto} "成功後返回的值"
+// @Router /api/order/findOrder [get]
+// @Security JWT
+func (controller *orderController) FindOrder(ctx *gin.Context) {
+	orderId := ctx.Query("orderId")
+	order, err := controller.order.FindOrder(contextOf(ctx), orderId)
+	respondWithData(ctx, order, err)
+}
+
+// FindAllOrder godoc
+// @Summary 取得所有訂單資訊
+// @Tags order
+// @produce application/json
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param buyer query string false "search by buyer, only admins and minters search other buyers"
+// @Param creationId query string false "search by creationId"
+// @Param status query string false "search by status"
+// @Param createAfter query string false "search by createAfter"
+// @Param createBefore query string false "search by createBefore"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.OrderDto} "成功後返回的值"
+// @Router /api/order/findAllOrder [get]
+// @Security JWT
+func (controller *orderController) FindAllOrder(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.OrderSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	filter, err := getOrderFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	var orders []services.OrderDto
+
+	if pageable.Page < 0 {
+		orders, err = controller.order.FindAllOrderByFilter(contextOf(ctx), filter)
+	} else {
+		orders, err = controller.order.FindAllOrderByFilterAndPage(contextOf(ctx), filter, *pageable)
+	}
+	respondWithData(ctx, orders, err)
+}
+
+// FailOrder godoc
+// @Summary 訂單失敗
+// @Tags order
+// @produce application/json
+// @Param FailOrderDto body services.FailOrderDto true "訂單失敗資料"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/order/failOrder [post]
+// @Security JWT
+func (controller *orderController) FailOrder(ctx *gin.Context) {
+	fail := services.FailOrderDto{}
+	if err := ctx.ShouldBindJSON(&fail); err != nil {
+		respond(ctx, err)
+		return
+	}
+
+	txn, err := transactions.NewTransaction("FailOrder")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.order.FailOrder(transactionCtx, fail)
+	}
+	_, err = txn.With(context.Background(), callback)
+	respond(ctx, err)
+}
+
+func getOrderFilterFromQuery(ctx *gin.Context) (filter services.OrderFilterDto, err error) {
+	if buyer := ctx.Query("buyer"); len(buyer) > 0 {
+		filter.Buyer = &buyer
+	}
+
+	if creationId := ctx.Query("creationId"); len(creationId) > 0 {
+		filter.CreationID = &creationId
+	}
+
+	if status := ctx.Query("status"); len(status) > 0 {
+		filter.Status = strings.Split(status, ",")
+	}
+
+	if after := ctx.Query("createAfter"); len(after) > 0 {
+		createAfter, err := time.Parse(time.RFC3339, after)
+		if err != nil {
+			return filter, err
+		}
+		filter.CreateAfter = &createAfter
+	}
+
+	if before := ctx.Query("createBefore"); len(before) > 0 {
+		createBefore, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return filter, err
+		}
+		filter.CreateBefore = &createBefore
+	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.OrderFilterFields)
+	return
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+)
+
+type PolicyController interface {
+	FindAllPolicy(ctx *gin.Context)
+	AddPolicy(ctx *gin.Context)
+	RemovePolicy(ctx *gin.Context)
+	FindAllRole(ctx *gin.Context)
+	AddRole(ctx *gin.Context)
+	RemoveRole(ctx *gin.Context)
+}
+
+type policyController struct {
+	policy services.PolicyService
+}
+
+func NewPolicyController() (controller PolicyController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &policyController{
+		policy: service.Policy,
+	}, nil
+}
+
+// FindAllPolicy godoc
+// @Summary 取得所有權限規則
+// @Tags policy
+// @produce application/json
+// @Param subject query string false "search by subject"
+// @Success 200 {object}  adapter.DataResp{data=[]services.PolicyDto} "成功後返回的值"
+// @Router /api/policy/findAllPolicy [get]
+// @Security JWT
+func (controller *policyController) FindAllPolicy(ctx *gin.Context) {
+	var subject *string
+	if s := ctx.Query("subject"); len(s) > 0 {
+		subject = &s
+	}
+	policies, err := controller.policy.FindAllPolicy(context.TODO(), subject)
+	respondWithData(ctx, policies, err)
+}
+
+// AddPolicy godoc
+// @Summary 新增權限規則
+// @Tags policy
+// @produce application/json
+// @Param PolicyDto body services.PolicyDto true "權限規則"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/policy/addPolicy [post]
+// @Security JWT
+func (controller *policyController) AddPolicy(ctx *gin.Context) {
+	policy := services.PolicyDto{}
+	if err := ctx.ShouldBindJSON(&policy); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.policy.AddPolicy(context.TODO(), policy)
+	respond(ctx, err)
+}
+
+// RemovePolicy godoc
+// @Summary 移除權限規則
+// @Tags policy
+// @produce application/json
+// @Param PolicyDto body services.PolicyDto true "權限規則"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/policy/removePolicy [post]
+// @Security JWT
+func (controller *policyController) RemovePolicy(ctx *gin.Context) {
+	policy := services.PolicyDto{}
+	if err := ctx.ShouldBindJSON(&policy); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.policy.RemovePolicy(context.TODO(), policy)
+	respond(ctx, err)
+}
+
+// FindAllRole godoc
+// @Summary 取得所有角色指派
+// @Tags policy
+// @produce application/json
+// @Param user query string false "search by user"
+// @Success 200 {object}  adapter.DataResp{data=[]services.RoleDto} "成功後返回的值"
+// @Router /api/policy/findAllRole [get]
+// @Security JWT
+func (controller *policyController) FindAllRole(ctx *gin.Context) {
+	var user *string
+	if u := ctx.Query("user"); len(u) > 0 {
+		user = &u
+	}
+	roles, err := controller.policy.FindAllRole(context.TODO(), user)
+	respondWithData(ctx, roles, err)
+}
+
+// AddRole godoc
+// @Summary 指派角色
+// @Tags policy
+// @produce application/json
+// @Param RoleDto body services.RoleDto true "角色指派"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/policy/addRole [post]
+// @Security JWT
+func (controller *policyController) AddRole(ctx *gin.Context) {
+	role := services.RoleDto{}
+	if err := ctx.ShouldBindJSON(&role); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.policy.AddRole(context.TODO(), role)
+	respond(ctx, err)
+}
+
+// RemoveRole godoc
+// @Summary 移除角色指派
+// @Tags policy
+// @produce application/json
+// @Param RoleDto body services.RoleDto true "角色指派"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/policy/removeRole [post]
+// @Security JWT
+func (controller *policyController) RemoveRole(ctx *gin.Context) {
+	role := services.RoleDto{}
+	if err := ctx.ShouldBindJSON(&role); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.policy.RemoveRole(context.TODO(), role)
+	respond(ctx, err)
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/business/services"
+)
+
+type ServiceAccountController interface {
+	CreateServiceAccount(ctx *gin.Context)
+	UpdateScopes(ctx *gin.Context)
+	FindAllServiceAccount(ctx *gin.Context)
+	CreateApiKey(ctx *gin.Context)
+	RotateApiKey(ctx *gin.Context)
+	RevokeApiKey(ctx *gin.Context)
+	FindAllApiKey(ctx *gin.Context)
+}
+
+type serviceAccountController struct {
+	serviceAccount services.ServiceAccountService
+}
+
+func NewServiceAccountController() (controller ServiceAccountController, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	return &serviceAccountController{
+		serviceAccount: service.ServiceAccount,
+	}, nil
+}
+
+// CreateServiceAccount godoc
+// @Summary 建立服務帳號
+// @Tags serviceAccount
+// @produce application/json
+// @Param CreateServiceAccountDto body services.CreateServiceAccountDto true "服務帳號資料"
+// @Success 200 {object}  adapter.DataResp{data=services.ServiceAccountDto} "成功後返回的值"
+// @Router /api/serviceAccount/createServiceAccount [post]
+// @Security JWT
+func (controller *serviceAccountController) CreateServiceAccount(ctx *gin.Context) {
+	account := services.CreateServiceAccountDto{}
+	if err := ctx.ShouldBindJSON(&account); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.serviceAccount.CreateServiceAccount(context.TODO(), account)
+	respondWithData(ctx, result, err)
+}
+
+// UpdateScopes godoc
+// @Summary 更新服務帳號權限範圍
+// @Tags serviceAccount
+// @produce application/json
+// @Param UpdateScopesDto body services.UpdateScopesDto true "權限範圍"
+// @Success 200 {object}  adapter.DataResp{data=services.ServiceAccountDto} "成功後返回的值"
+// @Router /api/serviceAccount/updateScopes [post]
+// @Security JWT
+func (controller *serviceAccountController) UpdateScopes(ctx *gin.Context) {
+	scopes := services.UpdateScopesDto{}
+	if err := ctx.ShouldBindJSON(&scopes); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.serviceAccount.UpdateScopes(context.TODO(), scopes)
+	respondWithData(ctx, result, err)
+}
+
+// FindAllServiceAccount godoc
+// @Summary 取得所有服務帳號
+// @Tags serviceAccount
+// @produce application/json
+// @Success 200 {object}  adapter.DataResp{data=[]services.ServiceAccountDto} "成功後返回的值"
+// @Router /api/serviceAccount/findAllServiceAccount [get]
+// @Security JWT
+func (controller *serviceAccountController) FindAllServiceAccount(ctx *gin.Context) {
+	accounts, err := controller.serviceAccount.FindAllServiceAccount(context.TODO())
+	respondWithData(ctx, accounts, err)
+}
+
+// CreateApiKey godoc
+// @Summary 建立API Key,key只會在建立時返回一次
+// @Tags serviceAccount
+// @produce application/json
+// @Param CreateApiKeyDto body services.CreateApiKeyDto true "服務帳號"
+// @Success 200 {object}  adapter.DataResp{data=services.ApiKeyDto} "成功後返回的值"
+// @Router /api/serviceAccount/createApiKey [post]
+// @Security JWT
+func (controller *serviceAccountController) CreateApiKey(ctx *gin.Context) {
+	key := services.CreateApiKeyDto{}
+	if err := ctx.ShouldBindJSON(&key); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.serviceAccount.CreateApiKey(context.TODO(), key)
+	respondWithData(ctx, result, err)
+}
+
+// RotateApiKey godoc
+// @Summary 輪替API Key,舊key在寬限期後失效
+// @Tags serviceAccount
+// @produce application/json
+// @Param RotateApiKeyDto body services.RotateApiKeyDto true "要輪替的key"
+// @Success 200 {object}  adapter.DataResp{data=services.ApiKeyDto} "成功後返回的值"
+// @Router /api/serviceAccount/rotateApiKey [post]
+// @Security JWT
+func (controller *serviceAccountController) RotateApiKey(ctx *gin.Context) {
+	key := services.RotateApiKeyDto{}
+	if err := ctx.ShouldBindJSON(&key); err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	result, err := controller.serviceAccount.RotateApiKey(context.TODO(), key)
+	respondWithData(ctx, result, err)
+}
+
+// RevokeApiKey godoc
+// @Summary 註銷API Key
+// @Tags serviceAccount
+// @produce application/json
+// @Param RevokeApiKeyDto body services.RevokeApiKeyDto true "要註銷的key"
+// @Success 200 {object}  adapter.NonDataResp "成功後返回的值"
+// @Router /api/serviceAccount/revokeApiKey [post]
+// @Security JWT
+func (controller *serviceAccountController) RevokeApiKey(ctx *gin.Context) {
+	key := services.RevokeApiKeyDto{}
+	if err := ctx.ShouldBindJSON(&key); err != nil {
+		respond(ctx, err)
+		return
+	}
+	err := controller.serviceAccount.RevokeApiKey(context.TODO(), key)
+	respond(ctx, err)
+}
+
+// FindAllApiKey godoc
+// @Summary 取得服務帳號的所有API Key
+// @Tags serviceAccount
+// @produce application/json
+// @Param serviceAccountId query string true "search by serviceAccountId"
+// @Success 200 {object}  adapter.DataResp{data=[]services.ApiKeyDto} "成功後返回的值"
+// @Router /api/serviceAccount/findAllApiKey [get]
+// @Security JWT
+func (controller *serviceAccountController) FindAllApiKey(ctx *gin.Context) {
+	serviceAccountId := ctx.Query("serviceAccountId")
+	keys, err := controller.serviceAccount.FindAllApiKey(context.TODO(), serviceAccountId)
+	respondWithData(ctx, keys, err)
+}
@@ -32,11 +32,13 @@ func NewStockController() (controller StockController, err error) {
 // @Param size query string false "search by size"
 // @Param creationId query string false "search by creationId"
 // @Param brandId query string false "search by brandId"
-// @Success 200 {object}  adapter.DataResp{data=[]services.CollectDto} "成功後返回的值"
+// @Param cursor query string false "cursor of the next page, send it empty for the first one"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.CollectDto} "成功後返回的值, cursor mode returns utils.Page"
 // @Router /api/stock/findAllStock [get]
 // @Security JWT
 func (controller *stockController) FindAllStock(ctx *gin.Context) {
-	pageable, err := getPageFromQuery(ctx)
+	pageable, err := getPageFromQuery(ctx, services.CollectSortFields)
 	if err != nil {
 		respondWithData(ctx, nil, err)
 		return
@@ -49,6 +51,11 @@ func (controller *stockController) FindAllStock(ctx *gin.Context) {
 	}
 	var stocks []services.CollectDto
 
+	if pageable.IsCursor() {
+		stocks, next, err := controller.stock.FindAllCollectByFilterAndCursor(context.TODO(), filter, *pageable)
+		respondWithCursor(ctx, pageable, stocks, next, err)
+		return
+	}
 	if pageable.Page < 0 {
 		stocks, err = controller.stock.FindAllCollectByFilter(context.TODO(), filter)
 		if err != nil {
@@ -73,5 +80,6 @@ func getStockFilterFromQuery(ctx *gin.Context) (filter services.CollectFilterDto
 	if creationId := ctx.Query("creationId"); len(creationId) > 0 {
 		filter.CreationID = &creationId
 	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.CollectFilterFields)
 	return
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/transactions"
 	"strconv"
 	"strings"
 	"time"
@@ -13,10 +14,16 @@ type TradeController interface {
 	FindTransaction(ctx *gin.Context)
 	FindAllTransaction(ctx *gin.Context)
 	TradeInCreation(ctx *gin.Context)
+	FindEarningStatement(ctx *gin.Context)
+	FindAllEarning(ctx *gin.Context)
+	FindTradeStats(ctx *gin.Context)
+	FindPriceHistory(ctx *gin.Context)
 }
 
 type tradeController struct {
-	trade services.TradeService
+	trade     services.TradeService
+	fee       services.FeeService
+	analytics services.AnalyticsService
 }
 
 func NewTradeController() (controller TradeController, err error) {
@@ -25,7 +32,9 @@ func NewTradeController() (controller TradeController, err error) {
 		return
 	}
 	return &tradeController{
-		trade: service.Trade,
+		trade:     service.Trade,
+		fee:       service.Fee,
+		analytics: service.Analytics,
 	}, nil
 }
 
@@ -60,13 +69,15 @@ func (controller *tradeController) FindTransaction(ctx *gin.Context) {
 // @Param brandId query string false "search by brandId"
 // @Param buyer query string false "search by buyer"
 // @Param seller query string false "search by seller"
-// @Param sort query string false "search by sort"
-// @Param order query int false "search by order"
-// @Success 200 {object}  adapter.DataResp{data=[]services.TransactionDto} "成功後返回的值"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param cursor query string false "cursor of the next page, send it empty for the first one"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.TransactionDto} "成功後返回的值, cursor mode returns utils.Page"
 // @Router /api/trade/findAllTransaction [get]
 // @Security JWT
 func (controller *tradeController) FindAllTransaction(ctx *gin.Context) {
-	pageable, err := getPageFromQuery(ctx)
+	pageable, err := getPageFromQuery(ctx, services.TransactionSortFields)
 	if err != nil {
 		respondWithData(ctx, nil, err)
 		return
@@ -80,6 +91,11 @@ func (controller *tradeController) FindAllTransaction(ctx *gin.Context) {
 
 	var transactions []services.TransactionDto
 
+	if pageable.IsCursor() {
+		transactions, next, err := controller.trade.FindAllTransactionByFilterAndCursor(context.TODO(), filter, *pageable)
+		respondWithCursor(ctx, pageable, transactions, next, err)
+		return
+	}
 	if pageable.Page < 0 {
 		transactions, err = controller.trade.FindAllTransactionByFilter(context.TODO(), filter)
 	} else {
@@ -102,8 +118,172 @@ func (controller *tradeController) TradeInCreation(ctx *gin.Context) {
 		respondWithData(ctx, nil, err)
 		return
 	}
-	txn, err := controller.trade.TradeInCreation(context.TODO(), trade)
-	respondWithData(ctx, txn, err)
+
+	txn, err := transactions.NewTransaction("TradeInCreation")
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		transaction, err := controller.trade.TradeInCreation(transactionCtx, trade)
+		if err != nil {
+			return nil, err
+		}
+		return transaction, nil
+	}
+	transaction, err := txn.With(context.Background(), callback)
+	respondWithData(ctx, transaction, err)
+}
+
+// FindEarningStatement godoc
+// @Summary 取得收益報表
+// @Tags trade
+// @produce application/json
+// @Param payee query string false "search by payee, the caller or a brand of the caller, the caller by default"
+// @Param payeeType query string false "search by payeeType"
+// @Param earnAfter query string false "search by earnAfter"
+// @Param earnBefore query string false "search by earnBefore"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=services.EarningStatementDto} "成功後返回的值"
+// @Router /api/trade/findEarningStatement [get]
+// @Security JWT
+func (controller *tradeController) FindEarningStatement(ctx *gin.Context) {
+	filter, err := getEarningFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	statement, err := controller.fee.FindEarningStatement(contextOf(ctx), filter)
+	respondWithData(ctx, statement, err)
+}
+
+// FindAllEarning godoc
+// @Summary 取得所有收益明細
+// @Tags trade
+// @produce application/json
+// @Param page query string false "search by page"
+// @Param size query string false "search by size"
+// @Param payee query string false "search by payee, the caller or a brand of the caller, the caller by default"
+// @Param payeeType query string false "search by payeeType"
+// @Param earnAfter query string false "search by earnAfter"
+// @Param earnBefore query string false "search by earnBefore"
+// @Param sort query string false "sort by field:asc or field:desc, separated by comma"
+// @Param order query int false "order of a single sort field without one, deprecated"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.EarningDto} "成功後返回的值"
+// @Router /api/trade/findAllEarning [get]
+// @Security JWT
+func (controller *tradeController) FindAllEarning(ctx *gin.Context) {
+	pageable, err := getPageFromQuery(ctx, services.EarningSortFields)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	filter, err := getEarningFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+
+	var earnings []services.EarningDto
+
+	if pageable.Page < 0 {
+		earnings, err = controller.fee.FindAllEarningByFilter(contextOf(ctx), filter)
+	} else {
+		earnings, err = controller.fee.FindAllEarningByFilterAndPage(contextOf(ctx), filter, *pageable)
+	}
+	respondWithData(ctx, earnings, err)
+}
+
+// FindTradeStats godoc
+// @Summary 取得交易統計
+// @Tags trade
+// @produce application/json
+// @Param groupBy query string false "group by creation or brand"
+// @Param bucket query string false "split by hour, day or week"
+// @Param tradedBefore query string false "search by tradedBefore"
+// @Param tradedAfter query string false "search by tradedAfter"
+// @Param maxPrice query int false "search by maxPrice"
+// @Param minPrice query int false "search by minPrice"
+// @Param creationId query string false "search by creationId"
+// @Param brandId query string false "search by brandId"
+// @Param buyer query string false "search by buyer"
+// @Param seller query string false "search by seller"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Success 200 {object}  adapter.DataResp{data=[]services.TradeStatsDto} "成功後返回的值"
+// @Router /api/trade/stats [get]
+// @Security JWT
+func (controller *tradeController) FindTradeStats(ctx *gin.Context) {
+	filter, err := getTransactionFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	stats, err := controller.analytics.FindTradeStats(context.TODO(), services.TradeStatsFilterDto{
+		TransactionFilterDto: filter,
+		GroupBy:              ctx.Query("groupBy"),
+		Bucket:               ctx.Query("bucket"),
+	})
+	respondWithData(ctx, stats, err)
+}
+
+// FindPriceHistory godoc
+// @Summary 取得成交價格走勢
+// @Tags trade
+// @produce application/json
+// @Param tradedBefore query string false "search by tradedBefore"
+// @Param tradedAfter query string false "search by tradedAfter"
+// @Param creationId query string false "search by creationId"
+// @Param brandId query string false "search by brandId"
+// @Param filter query string false "filter by terms like price>=100;properties~in~(fire,water);brandId!=x"
+// @Param limit query int false "latest trades to return, up to 1000"
+// @Success 200 {object}  adapter.DataResp{data=[]services.PricePointDto} "成功後返回的值"
+// @Router /api/trade/stats/priceHistory [get]
+// @Security JWT
+func (controller *tradeController) FindPriceHistory(ctx *gin.Context) {
+	filter, err := getTransactionFilterFromQuery(ctx)
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "1000"))
+	if err != nil {
+		respondWithData(ctx, nil, err)
+		return
+	}
+	points, err := controller.analytics.FindPriceHistory(context.TODO(), filter, limit)
+	respondWithData(ctx, points, err)
+}
+
+func getEarningFilterFromQuery(ctx *gin.Context) (filter services.EarningFilterDto, err error) {
+	if payee := ctx.Query("payee"); len(payee) > 0 {
+		filter.Payee = &payee
+	}
+
+	if payeeType := ctx.Query("payeeType"); len(payeeType) > 0 {
+		filter.PayeeType = strings.Split(payeeType, ",")
+	}
+
+	if after := ctx.Query("earnAfter"); len(after) > 0 {
+		earnAfter, err := time.Parse(time.RFC3339, after)
+		if err != nil {
+			return filter, err
+		}
+		filter.EarnAfter = &earnAfter
+	}
+
+	if before := ctx.Query("earnBefore"); len(before) > 0 {
+		earnBefore, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return filter, err
+		}
+		filter.EarnBefore = &earnBefore
+	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.EarningFilterFields)
+	return
 }
 
 func getTransactionFilterFromQuery(ctx *gin.Context) (filter services.TransactionFilterDto, err error) {
@@ -162,5 +342,6 @@ func getTransactionFilterFromQuery(ctx *gin.Context) (filter services.Transactio
 		}
 		filter.MaxPrice = &maxPrice
 	}
+	filter.Conditions, err = getConditionsFromQuery(ctx, services.TransactionFilterFields)
 	return
 }
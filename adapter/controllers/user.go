@@ -34,7 +34,6 @@ func NewUserController() (controller UserController, err error) {
 // @Param userName query string false "search by userName"
 // @Success 200 {object}  adapter.DataResp{data=bool} "成功後返回的值"
 // @Router /api/user/exist [get]
-// @Security JWT
 func (controller *userController) Exist(ctx *gin.Context) {
 	userId := ctx.Query("userId")
 	var isExisted bool
@@ -88,7 +87,19 @@ func (controller *userController) Register(ctx *gin.Context) {
 // @Router /api/user/deleteUser [get]
 // @Security JWT
 func (controller *userController) DeleteUser(ctx *gin.Context) {
-	err := controller.user.DeleteUser(context.TODO(), ctx.Query("userId"))
+	userId := ctx.Query("userId")
+
+	txn, err := transactions.NewTransaction("DeleteUser")
+	if err != nil {
+		respond(ctx, err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return nil, controller.user.DeleteUser(transactionCtx, userId)
+	}
+	_, err = txn.With(contextOf(ctx), callback)
 	respond(ctx, err)
 }
 
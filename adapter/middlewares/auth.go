@@ -8,6 +8,7 @@ type AuthMiddleware interface {
 	Authenticate() gin.HandlerFunc
 	Authorize() gin.HandlerFunc
 	Auth() gin.HandlersChain
+	Role(roles ...string) gin.HandlersChain
 }
 
 type authMiddleware struct {
@@ -41,3 +42,7 @@ func (middleware *authMiddleware) Authorize() gin.HandlerFunc {
 func (middleware *authMiddleware) Auth() gin.HandlersChain {
 	return []gin.HandlerFunc{middleware.Authenticate(), middleware.Authorize()}
 }
+
+func (middleware *authMiddleware) Role(roles ...string) gin.HandlersChain {
+	return []gin.HandlerFunc{middleware.Authenticate(), middleware.authorize.HasRole(roles...), middleware.Authorize()}
+}
@@ -2,8 +2,10 @@ package middlewares
 
 import (
 	"context"
+	"github.com/casbin/casbin"
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/casbins"
 	"nftshopping-store-api/pkg/security"
 	"strings"
 )
@@ -13,7 +15,9 @@ type AuthenticateMiddleware interface {
 }
 
 type authenticateMiddleware struct {
-	auth AuthService
+	auth     AuthService
+	apiKey   ApiKeyService
+	enforcer *casbin.SyncedEnforcer
 }
 
 func NewAuthenticateMiddleware() (middleware AuthenticateMiddleware, err error) {
@@ -21,11 +25,31 @@ func NewAuthenticateMiddleware() (middleware AuthenticateMiddleware, err error)
 	if err != nil {
 		return nil, err
 	}
-	return &authenticateMiddleware{auth: service.Auth}, nil
+	enforcer, err := casbins.GetEnforcer()
+	if err != nil {
+		return nil, err
+	}
+	return &authenticateMiddleware{auth: service.Auth, apiKey: service.ServiceAccount, enforcer: enforcer}, nil
 }
 
 func (middleware *authenticateMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// machine clients authenticate with an api key instead of a token
+		if apiKey := c.Request.Header.Get("X-API-Key"); apiKey != "" {
+			account, err := middleware.apiKey.FindAuthByApiKey(context.Background(), apiKey)
+			if err != nil {
+				c.AbortWithStatus(500)
+				return
+			}
+			if account == nil {
+				c.AbortWithStatus(401)
+				return
+			}
+			c.Set("Authentication", middleware.authenticationOf(account))
+			c.Next()
+			return
+		}
+
 		clientToken := c.Request.Header.Get("Authorization")
 		if clientToken == "" {
 			c.AbortWithStatus(401)
@@ -42,6 +66,7 @@ func (middleware *authenticateMiddleware) Authenticate() gin.HandlerFunc {
 		}
 		name, err := security.ExtractUserName(clientToken)
 		if err != nil {
+			c.AbortWithStatus(401)
 			return
 		}
 		user, err := middleware.auth.FindAuthByName(context.Background(), name)
@@ -54,8 +79,9 @@ func (middleware *authenticateMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		isValid, err := security.ValidateToken(clientToken, user)
+		isValid, err := security.ValidateToken(context.Background(), clientToken, user)
 		if err != nil {
+			c.AbortWithStatus(401)
 			return
 		}
 
@@ -64,16 +90,34 @@ func (middleware *authenticateMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		authentication := &authentication{user.GetName(), user.GetAuthorities()}
-		c.Set("Authentication", authentication)
+		c.Set("Authentication", middleware.authenticationOf(user))
 		c.Next()
 	}
 }
 
+// authenticationOf adds the roles assigned to the caller, or inherited by its roles, through casbin.
+func (middleware *authenticateMiddleware) authenticationOf(auth security.Authentication) *authentication {
+	var authorities []string
+	granted := map[string]bool{}
+	for _, authority := range auth.GetAuthorities() {
+		granted[authority] = true
+	}
+	for _, role := range casbins.RolesOf(middleware.enforcer, append([]string{auth.GetName()}, auth.GetAuthorities()...)...) {
+		if role != auth.GetName() || granted[role] {
+			authorities = append(authorities, role)
+		}
+	}
+	return &authentication{auth.GetName(), authorities}
+}
+
 type AuthService interface {
 	FindAuthByName(ctx context.Context, userName string) (security.Authentication, error)
 }
 
+type ApiKeyService interface {
+	FindAuthByApiKey(ctx context.Context, key string) (security.Authentication, error)
+}
+
 type authentication struct {
 	UserName    string
 	Authorities []string
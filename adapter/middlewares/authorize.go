@@ -9,10 +9,11 @@ import (
 
 type AuthorizeMiddleware interface {
 	Authorize() gin.HandlerFunc
+	HasRole(roles ...string) gin.HandlerFunc
 }
 
 type authorizeMiddleware struct {
-	enforcer *casbin.Enforcer
+	enforcer *casbin.SyncedEnforcer
 }
 
 func NewAuthorizeMiddleware() (middleware AuthorizeMiddleware, err error) {
@@ -36,7 +37,9 @@ func (middleware *authorizeMiddleware) Authorize() gin.HandlerFunc {
 			return
 		}
 		isAuthorized := false
-		for _, role := range auth.GetAuthorities() {
+		// a policy may also be granted to the caller directly rather than to one of its roles
+		subjects := append([]string{auth.GetName()}, auth.GetAuthorities()...)
+		for _, role := range subjects {
 			ok, err := middleware.enforcer.EnforceSafe(role, c.Request.URL.Path, c.Request.Method)
 			if err != nil {
 				c.AbortWithStatus(500)
@@ -54,3 +57,28 @@ func (middleware *authorizeMiddleware) Authorize() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// HasRole only lets through an authenticated caller holding at least one of the roles.
+func (middleware *authorizeMiddleware) HasRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authentication, isExist := c.Get("Authentication")
+		if !isExist {
+			c.AbortWithStatus(403)
+			return
+		}
+		auth, ok := authentication.(security.Authentication)
+		if !ok {
+			c.AbortWithStatus(500)
+			return
+		}
+		for _, authority := range auth.GetAuthorities() {
+			for _, role := range roles {
+				if authority == role {
+					c.Next()
+					return
+				}
+			}
+		}
+		c.AbortWithStatus(403)
+	}
+}
@@ -15,6 +15,7 @@ func GetMiddleware() (instance *middleware, err error) {
 
 type middleware struct {
 	Cors CorsMiddleware
+	Auth AuthMiddleware
 }
 
 func NewMiddleware() (instance *middleware, err error) {
@@ -22,7 +23,12 @@ func NewMiddleware() (instance *middleware, err error) {
 	if err != nil {
 		return nil, err
 	}
+	auth, err := NewAuthMiddleware()
+	if err != nil {
+		return nil, err
+	}
 	return &middleware{
 		Cors: cors,
+		Auth: auth,
 	}, nil
 }
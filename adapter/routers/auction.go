@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+)
+
+func InitAuctionRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	public := app.Group("auction")
+	public.GET("/findAuction", controller.Auction.FindAuction)
+	public.GET("/findAllBid", controller.Auction.FindAllBid)
+
+	auction := app.Group("auction", middleware.Auth.Auth()...)
+	auction.POST("/placeBid", controller.Auction.PlaceBid)
+	return
+}
@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+)
+
+func InitAuthRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	public := app.Group("auth")
+	public.GET("/nonce", controller.Auth.Nonce)
+	public.POST("/login", controller.Auth.Login)
+	public.POST("/refresh", controller.Auth.Refresh)
+	public.POST("/revoke", controller.Auth.Revoke)
+	return
+}
@@ -3,6 +3,7 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
 )
 
 func InitBrandRouter(engine *gin.Engine) (err error) {
@@ -10,13 +11,24 @@ func InitBrandRouter(engine *gin.Engine) (err error) {
 	if err != nil {
 		return
 	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
 	app := engine.Group("api")
 
-	brand := app.Group("brand")
-	brand.GET("/findBrand", controller.Brand.FindBrand)
-	brand.GET("/findAllBrand", controller.Brand.FindAllBrand)
+	public := app.Group("brand")
+	public.GET("/findBrand", controller.Brand.FindBrand)
+	public.GET("/findAllBrand", controller.Brand.FindAllBrand)
+
+	// membership of the brand is checked by the brand service
+	brand := app.Group("brand", middleware.Auth.Auth()...)
 	brand.POST("/postBrand", controller.Brand.PostBrand)
 	brand.POST("/updateBrand", controller.Brand.UpdateBrand)
 	brand.DELETE("/deleteBrand", controller.Brand.DeleteBrand)
+	brand.GET("/findAllMember", controller.Brand.FindAllMember)
+	brand.POST("/addMember", controller.Brand.AddMember)
+	brand.POST("/updateMember", controller.Brand.UpdateMember)
+	brand.POST("/removeMember", controller.Brand.RemoveMember)
 	return
 }
@@ -12,8 +12,10 @@ func InitCollectionRouter(engine *gin.Engine) (err error) {
 	}
 	app := engine.Group("api")
 
-	collect := app.Group("collection")
-	collect.GET("/findCollection", controller.Collection.FindCollection)
-	collect.GET("/findAllCollection", controller.Collection.FindAllCollection)
+	public := app.Group("collection")
+	public.GET("/findCollection", controller.Collection.FindCollection)
+	public.GET("/findAllCollection", controller.Collection.FindAllCollection)
+	public.GET("/findAllHolder", controller.Collection.FindAllHolder)
+	public.GET("/findHolderSummary", controller.Collection.FindHolderSummary)
 	return
 }
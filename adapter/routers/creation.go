@@ -3,6 +3,7 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
 )
 
 func InitCreationRouter(engine *gin.Engine) (err error) {
@@ -10,13 +11,23 @@ func InitCreationRouter(engine *gin.Engine) (err error) {
 	if err != nil {
 		return
 	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
 	app := engine.Group("api")
 
-	creation := app.Group("creation")
-	creation.GET("/findCreation", controller.Creation.FindCreation)
-	creation.GET("/findAllCreation", controller.Creation.FindAllCreation)
+	public := app.Group("creation")
+	public.GET("/findCreation", controller.Creation.FindCreation)
+	public.GET("/findAllCreation", controller.Creation.FindAllCreation)
+	public.GET("/search", controller.Creation.SearchCreation)
+	public.GET("/autocomplete", controller.Creation.AutocompleteCreation)
+
+	// membership of the brand is checked by the creation service
+	creation := app.Group("creation", middleware.Auth.Auth()...)
 	creation.POST("/postCreation", controller.Creation.PostCreation)
 	creation.DELETE("/deleteCreation", controller.Creation.DeleteCreation)
 	creation.POST("/updateCreation", controller.Creation.UpdateCreation)
+	creation.POST("/updateSaleStatus", controller.Creation.UpdateSaleStatus)
 	return
 }
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
+)
+
+func InitDeadLetterRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	admin := app.Group("deadLetter", middleware.Auth.Role(security.RoleAdmin)...)
+	admin.GET("/findDeadLetter", controller.DeadLetter.FindDeadLetter)
+	admin.GET("/findAllDeadLetter", controller.DeadLetter.FindAllDeadLetter)
+	admin.POST("/replayDeadLetter", controller.DeadLetter.ReplayDeadLetter)
+	admin.POST("/discardDeadLetter", controller.DeadLetter.DiscardDeadLetter)
+	return
+}
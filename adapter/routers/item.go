@@ -3,6 +3,8 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
 )
 
 func InitItemRouter(engine *gin.Engine) (err error) {
@@ -10,13 +12,21 @@ func InitItemRouter(engine *gin.Engine) (err error) {
 	if err != nil {
 		return
 	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
 	app := engine.Group("api")
 
-	item := app.Group("item")
-	item.GET("/:contract/:token", controller.Item.FindItem)
+	public := app.Group("item")
+	public.GET("/:contract/:token", controller.Item.FindItem)
+	public.GET("/getAmountOfItem", controller.Item.GetAmountOfItem)
+
+	item := app.Group("item", middleware.Auth.Auth()...)
 	item.POST("/orderItem", controller.Item.OrderItem)
-	item.POST("/deliverItem", controller.Item.DeliverItem)
 	item.GET("/findAllItem", controller.Item.FindAllItem)
-	item.GET("/getAmountOfItem", controller.Item.GetAmountOfItem)
+
+	admin := app.Group("item", middleware.Auth.Role(security.RoleAdmin, security.RoleMinter)...)
+	admin.POST("/deliverItem", controller.Item.DeliverItem)
 	return
 }
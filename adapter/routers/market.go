@@ -0,0 +1,34 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+)
+
+func InitMarketRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	public := app.Group("market")
+	public.GET("/findListing", controller.Market.FindListing)
+	public.GET("/findAllListing", controller.Market.FindAllListing)
+	public.GET("/findOffer", controller.Market.FindOffer)
+	public.GET("/findAllOffer", controller.Market.FindAllOffer)
+
+	market := app.Group("market", middleware.Auth.Auth()...)
+	market.POST("/createListing", controller.Market.CreateListing)
+	market.POST("/cancelListing", controller.Market.CancelListing)
+	market.POST("/buyListing", controller.Market.BuyListing)
+	market.POST("/makeOffer", controller.Market.MakeOffer)
+	market.POST("/acceptOffer", controller.Market.AcceptOffer)
+	market.POST("/rejectOffer", controller.Market.RejectOffer)
+	return
+}
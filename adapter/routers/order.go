@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
+)
+
+func InitOrderRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	order := app.Group("order", middleware.Auth.Auth()...)
+	order.GET("/findOrder", controller.Order.FindOrder)
+	order.GET("/findAllOrder", controller.Order.FindAllOrder)
+
+	admin := app.Group("order", middleware.Auth.Role(security.RoleAdmin, security.RoleMinter)...)
+	admin.POST("/failOrder", controller.Order.FailOrder)
+	return
+}
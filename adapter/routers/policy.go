@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
+)
+
+func InitPolicyRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	admin := app.Group("policy", middleware.Auth.Role(security.RoleAdmin)...)
+	admin.GET("/findAllPolicy", controller.Policy.FindAllPolicy)
+	admin.POST("/addPolicy", controller.Policy.AddPolicy)
+	admin.POST("/removePolicy", controller.Policy.RemovePolicy)
+	admin.GET("/findAllRole", controller.Policy.FindAllRole)
+	admin.POST("/addRole", controller.Policy.AddRole)
+	admin.POST("/removeRole", controller.Policy.RemoveRole)
+	return
+}
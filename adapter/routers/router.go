@@ -37,6 +37,10 @@ func newRouter() (router *gin.Engine, err error) {
 
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	err = InitAuthRouter(engine)
+	if err != nil {
+		return
+	}
 	err = InitUserRouter(engine)
 	if err != nil {
 		return
@@ -65,5 +69,29 @@ func newRouter() (router *gin.Engine, err error) {
 	if err != nil {
 		return
 	}
+	err = InitOrderRouter(engine)
+	if err != nil {
+		return
+	}
+	err = InitAuctionRouter(engine)
+	if err != nil {
+		return
+	}
+	err = InitMarketRouter(engine)
+	if err != nil {
+		return
+	}
+	err = InitServiceAccountRouter(engine)
+	if err != nil {
+		return
+	}
+	err = InitPolicyRouter(engine)
+	if err != nil {
+		return
+	}
+	err = InitDeadLetterRouter(engine)
+	if err != nil {
+		return
+	}
 	return engine, nil
 }
@@ -0,0 +1,30 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
+)
+
+func InitServiceAccountRouter(engine *gin.Engine) (err error) {
+	controller, err := controllers.GetController()
+	if err != nil {
+		return
+	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
+	app := engine.Group("api")
+
+	admin := app.Group("serviceAccount", middleware.Auth.Role(security.RoleAdmin)...)
+	admin.POST("/createServiceAccount", controller.ServiceAccount.CreateServiceAccount)
+	admin.POST("/updateScopes", controller.ServiceAccount.UpdateScopes)
+	admin.GET("/findAllServiceAccount", controller.ServiceAccount.FindAllServiceAccount)
+	admin.POST("/createApiKey", controller.ServiceAccount.CreateApiKey)
+	admin.POST("/rotateApiKey", controller.ServiceAccount.RotateApiKey)
+	admin.POST("/revokeApiKey", controller.ServiceAccount.RevokeApiKey)
+	admin.GET("/findAllApiKey", controller.ServiceAccount.FindAllApiKey)
+	return
+}
@@ -3,6 +3,7 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
 )
 
 func InitStockRouter(engine *gin.Engine) (err error) {
@@ -10,9 +11,13 @@ func InitStockRouter(engine *gin.Engine) (err error) {
 	if err != nil {
 		return
 	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
 	app := engine.Group("api")
 
-	stock := app.Group("stock")
+	stock := app.Group("stock", middleware.Auth.Auth()...)
 	stock.GET("/findAllStock", controller.Stock.FindAllStock)
 	return
 }
@@ -3,6 +3,8 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
 )
 
 func InitTradeRouter(engine *gin.Engine) (err error) {
@@ -10,11 +12,21 @@ func InitTradeRouter(engine *gin.Engine) (err error) {
 	if err != nil {
 		return
 	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
 	app := engine.Group("api")
 
-	user := app.Group("trade")
-	user.GET("/findTransaction", controller.Trade.FindTransaction)
-	user.GET("/findAllTransaction", controller.Trade.FindAllTransaction)
-	user.POST("/tradeInCreation", controller.Trade.TradeInCreation)
+	trade := app.Group("trade", middleware.Auth.Auth()...)
+	trade.GET("/findTransaction", controller.Trade.FindTransaction)
+	trade.GET("/findAllTransaction", controller.Trade.FindAllTransaction)
+	trade.GET("/findEarningStatement", controller.Trade.FindEarningStatement)
+	trade.GET("/findAllEarning", controller.Trade.FindAllEarning)
+	trade.GET("/stats", controller.Trade.FindTradeStats)
+	trade.GET("/stats/priceHistory", controller.Trade.FindPriceHistory)
+
+	admin := app.Group("trade", middleware.Auth.Role(security.RoleAdmin)...)
+	admin.POST("/tradeInCreation", controller.Trade.TradeInCreation)
 	return
 }
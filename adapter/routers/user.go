@@ -3,6 +3,8 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"nftshopping-store-api/adapter/controllers"
+	"nftshopping-store-api/adapter/middlewares"
+	"nftshopping-store-api/pkg/security"
 )
 
 func InitUserRouter(engine *gin.Engine) (err error) {
@@ -10,12 +12,20 @@ func InitUserRouter(engine *gin.Engine) (err error) {
 	if err != nil {
 		return
 	}
+	middleware, err := middlewares.GetMiddleware()
+	if err != nil {
+		return
+	}
 	app := engine.Group("api")
 
-	user := app.Group("user")
-	user.GET("/exist", controller.User.Exist)
-	user.POST("/register", controller.User.Register)
+	public := app.Group("user")
+	public.GET("/exist", controller.User.Exist)
+	public.POST("/register", controller.User.Register)
+
+	user := app.Group("user", middleware.Auth.Auth()...)
 	user.GET("/findUser", controller.User.FindUser)
-	user.GET("/deleteUser", controller.User.DeleteUser)
+
+	admin := app.Group("user", middleware.Auth.Role(security.RoleAdmin)...)
+	admin.GET("/deleteUser", controller.User.DeleteUser)
 	return
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/persistence/repositories"
+	"time"
+)
+
+type AnalyticsService interface {
+	FindTradeStats(ctx context.Context, dto TradeStatsFilterDto) (statsDto []TradeStatsDto, err error)
+	FindPriceHistory(ctx context.Context, dto TransactionFilterDto, limit int) (pointsDto []PricePointDto, err error)
+}
+
+type analyticsService struct {
+	creation    CreationService
+	transaction repositories.TransactionDao
+}
+
+func NewAnalyticsService(creation CreationService) (service AnalyticsService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &analyticsService{
+		creation:    creation,
+		transaction: dao.Transaction,
+	}, nil
+}
+
+// FindTradeStats sums up the trades matched by the filter per creation or brand,
+// optionally split into hour, day or week buckets.
+func (service *analyticsService) FindTradeStats(
+	ctx context.Context, dto TradeStatsFilterDto,
+) (statsDto []TradeStatsDto, err error) {
+	group := repositories.TransactionGroup(dto.GroupBy)
+	switch group {
+	case repositories.GroupByAll, repositories.GroupByCreation, repositories.GroupByBrand:
+	default:
+		return nil, NewAnalyticsServiceError(StatsGroupInvalid)
+	}
+	bucket := repositories.TimeBucket(dto.Bucket)
+	switch bucket {
+	case repositories.BucketNone, repositories.BucketHour, repositories.BucketDay, repositories.BucketWeek:
+	default:
+		return nil, NewAnalyticsServiceError(StatsBucketInvalid)
+	}
+	selector, err := selectorOfTransactionFilter(ctx, service.creation, dto.TransactionFilterDto)
+	if err != nil {
+		return
+	}
+	stats, err := service.transaction.SumByGroup(ctx, repositories.SelectorOfTransaction(selector), group, bucket)
+	if err != nil {
+		return
+	}
+	statsDto = []TradeStatsDto{}
+	if err = copier.Copy(&statsDto, &stats); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// maxPricePoint caps how many trades FindPriceHistory returns.
+const maxPricePoint = 1000
+
+// FindPriceHistory returns the latest limit trades matched by the filter, up to maxPricePoint.
+func (service *analyticsService) FindPriceHistory(
+	ctx context.Context, dto TransactionFilterDto, limit int,
+) (pointsDto []PricePointDto, err error) {
+	if limit <= 0 || limit > maxPricePoint {
+		limit = maxPricePoint
+	}
+	selector, err := selectorOfTransactionFilter(ctx, service.creation, dto)
+	if err != nil {
+		return
+	}
+	points, err := service.transaction.FindPriceHistory(ctx, repositories.SelectorOfTransaction(selector), limit)
+	if err != nil {
+		return
+	}
+	pointsDto = []PricePointDto{}
+	if err = copier.Copy(&pointsDto, &points); err != nil {
+		return nil, err
+	}
+	return
+}
+
+type TradeStatsFilterDto struct {
+	TransactionFilterDto
+	GroupBy string `json:"groupBy"`
+	Bucket  string `json:"bucket"`
+}
+
+type TradeStatsDto struct {
+	CreationID    *string    `json:"creationId"`
+	BrandID       *string    `json:"brandId"`
+	Bucket        *time.Time `json:"bucket"`
+	Volume        int        `json:"volume"`
+	TradeCount    int        `json:"tradeCount"`
+	Amount        int        `json:"amount"`
+	FloorPrice    float64    `json:"floorPrice"`
+	CeilingPrice  float64    `json:"ceilingPrice"`
+	AveragePrice  float64    `json:"averagePrice"`
+	UniqueBuyers  int        `json:"uniqueBuyers"`
+	UniqueSellers int        `json:"uniqueSellers"`
+}
+
+type PricePointDto struct {
+	TransactionID string    `json:"transactionId"`
+	CreationID    string    `json:"creationId"`
+	Price         int       `json:"price"`
+	Amount        int       `json:"amount"`
+	UnitPrice     float64   `json:"unitPrice"`
+	TradeAt       time.Time `json:"tradeAt"`
+}
+
+func (dto *PricePointDto) ID(id primitive.ObjectID) {
+	dto.TransactionID = id.Hex()
+}
+
+type AnalyticsServiceError struct {
+	ServiceError
+}
+
+func NewAnalyticsServiceError(e ServiceEvent) error {
+	return &AnalyticsServiceError{ServiceError{ServiceName: "AnalyticsService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
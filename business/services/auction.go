@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type AuctionService interface {
+	FindAuction(ctx context.Context, creationId string) (auctionDto *AuctionDto, err error)
+	FindAllBidByFilter(ctx context.Context, dto BidFilterDto) (bidsDto []BidDto, err error)
+	FindAllBidByFilterAndPage(ctx context.Context, dto BidFilterDto, pageable utils.Pageable) (bidsDto []BidDto, err error)
+	PlaceBid(ctx context.Context, dto PlaceBidDto) (bidDto *BidDto, err error)
+	SettleAuction(ctx context.Context) (amount int, err error)
+}
+
+type auctionService struct {
+	creation         CreationService
+	user             UserService
+	order            OrderService
+	fee              FeeService
+	auction          repositories.AuctionDao
+	bid              repositories.BidDao
+	transaction      repositories.TransactionDao
+	auctionPublisher publishers.AuctionPublisher
+}
+
+func NewAuctionService(
+	creation CreationService, user UserService, order OrderService, fee FeeService,
+) (service AuctionService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
+	return &auctionService{
+		creation:         creation,
+		user:             user,
+		order:            order,
+		fee:              fee,
+		auction:          dao.Auction,
+		bid:              dao.Bid,
+		transaction:      dao.Transaction,
+		auctionPublisher: publisher.Auction,
+	}, nil
+}
+
+func (service *auctionService) FindAuction(ctx context.Context, creationId string) (auctionDto *AuctionDto, err error) {
+	id, err := primitive.ObjectIDFromHex(creationId)
+	if err != nil {
+		return nil, nil
+	}
+	auction, err := service.auction.Find(ctx, id)
+	if err != nil || auction == nil {
+		return
+	}
+	auctionDto = &AuctionDto{}
+	if err = copier.Copy(auctionDto, auction); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *auctionService) FindAllBidByFilter(ctx context.Context, dto BidFilterDto) (bidsDto []BidDto, err error) {
+	selector, err := selectorOfBidFilter(dto)
+	if err != nil {
+		return
+	}
+	bids, err := service.bid.FindAllByFilter(ctx, repositories.SelectorOfBid(selector))
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&bidsDto, &bids); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *auctionService) FindAllBidByFilterAndPage(
+	ctx context.Context, dto BidFilterDto, pageable utils.Pageable,
+) (bidsDto []BidDto, err error) {
+	selector, err := selectorOfBidFilter(dto)
+	if err != nil {
+		return
+	}
+	page, err := service.bid.FindAllByFilterAndPage(ctx, repositories.SelectorOfBid(selector), pageable)
+	if err != nil {
+		return
+	}
+	bids, ok := page.Content.([]repositories.Bid)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	if err = copier.Copy(&bidsDto, &bids); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *auctionService) PlaceBid(ctx context.Context, dto PlaceBidDto) (bidDto *BidDto, err error) {
+	bidder, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(dto.CreationId)
+	if err != nil {
+		return nil, NewAuctionServiceError(AuctionNotFound)
+	}
+	auction, err := service.auction.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if auction == nil {
+		return nil, NewAuctionServiceError(AuctionNotFound)
+	}
+	if auction.Status != repositories.AuctionOpen {
+		return nil, NewAuctionServiceError(AuctionClosed)
+	}
+	creation, err := service.creation.FindCreationByID(ctx, dto.CreationId)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return nil, NewAuctionServiceError(CreationNotFound)
+	}
+	now := time.Now()
+	if creation.SaleStatus != string(repositories.SaleStatusOnSale) ||
+		now.Before(creation.SaleStartAt) || !now.Before(creation.SaleEndAt) {
+		return nil, NewAuctionServiceError(CreationNotOnSale)
+	}
+	isAccepted, err := service.auction.Bid(ctx, id, bidder, dto.Price, now)
+	if err != nil {
+		return
+	}
+	if !isAccepted {
+		return nil, NewAuctionServiceError(BidTooLow)
+	}
+	bid := &repositories.Bid{
+		ID:         primitive.NewObjectID(),
+		CreationID: id,
+		Bidder:     bidder,
+		Price:      dto.Price,
+		BidAt:      now,
+	}
+	err = service.bid.Create(ctx, bid)
+	if err != nil {
+		return
+	}
+	bidDto = &BidDto{}
+	if err = copier.Copy(bidDto, bid); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// SettleAuction closes every open auction whose creation has passed SaleEndAt or was cancelled.
+// The highest bid wins if it reaches the reserve price; everybody else is told they lost.
+func (service *auctionService) SettleAuction(ctx context.Context) (amount int, err error) {
+	selector := repositories.AuctionSelector{
+		Status: []repositories.AuctionStatus{repositories.AuctionOpen},
+	}
+	auctions, err := service.auction.FindAllByFilter(ctx, repositories.SelectorOfAuction(selector))
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for i := range auctions {
+		creation, err := service.creation.FindCreationByID(ctx, auctions[i].ID.Hex())
+		if err != nil {
+			return amount, err
+		}
+		isCancelled := creation != nil && creation.SaleStatus == string(repositories.SaleStatusCancelled)
+		if creation != nil && !isCancelled && now.Before(creation.SaleEndAt) {
+			continue
+		}
+		isSettled, err := service.settle(ctx, &auctions[i], creation, now)
+		if err != nil {
+			return amount, err
+		}
+		if isSettled {
+			amount++
+		}
+	}
+	return
+}
+
+func (service *auctionService) settle(
+	ctx context.Context, auction *repositories.Auction, creation *CreationDto, at time.Time,
+) (isSettled bool, err error) {
+	// a cancelled creation is never sold, whatever the bids
+	isSold := creation != nil && creation.SaleStatus != string(repositories.SaleStatusCancelled) &&
+		auction.BidCount > 0 && auction.HighestPrice >= auction.ReservePrice
+	if !isSold {
+		closed, err := service.auction.Close(ctx, auction.ID, repositories.AuctionUnsold, nil, at)
+		if err != nil || closed == nil {
+			return false, err
+		}
+		return true, notifyAuctionLosers(ctx, service.bid, service.auctionPublisher, closed, "")
+	}
+
+	tnxId := primitive.NewObjectID()
+	closed, err := service.auction.Close(ctx, auction.ID, repositories.AuctionSold, &tnxId, at)
+	if err != nil || closed == nil {
+		return
+	}
+	transaction := &repositories.Transaction{
+		ID:         tnxId,
+		CreationID: creation.CreationID,
+		BrandID:    creation.BrandID,
+		Buyer:      closed.HighestBidder,
+		Seller:     creation.BrandID,
+		Amount:     creation.Amount,
+		Price:      closed.HighestPrice,
+		Items:      []repositories.ItemID{},
+		TradeAt:    at,
+	}
+	err = service.fee.SplitTrade(ctx, transaction)
+	if err != nil {
+		return
+	}
+	err = service.transaction.Create(ctx, transaction)
+	if err != nil {
+		return
+	}
+	_, err = service.order.PlaceAuctionOrder(ctx, AuctionOrderDto{
+		CreationId: creation.CreationID,
+		Buyer:      closed.HighestBidder,
+		Amount:     creation.Amount,
+		Price:      closed.HighestPrice,
+	})
+	if err != nil {
+		return
+	}
+	return true, notifyAuctionLosers(ctx, service.bid, service.auctionPublisher, closed, closed.HighestBidder)
+}
+
+// notifyAuctionLosers tells every bidder of a closed auction but the winner that they lost.
+func notifyAuctionLosers(
+	ctx context.Context, bid repositories.BidDao, publisher publishers.AuctionPublisher,
+	auction *repositories.Auction, winner string,
+) (err error) {
+	bidders, err := bid.FindBidders(ctx, auction.ID)
+	if err != nil {
+		return
+	}
+	for _, bidder := range bidders {
+		if bidder == winner {
+			continue
+		}
+		err = publisher.PublishToAuctionLost(ctx, messages.AuctionLostMessage{
+			CreationId:    auction.ID.Hex(),
+			Bidder:        bidder,
+			HighestPrice:  auction.HighestPrice,
+			IsReserveMiss: auction.Status == repositories.AuctionUnsold,
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func selectorOfBidFilter(dto BidFilterDto) (selector repositories.BidSelector, err error) {
+	selector = repositories.BidSelector{
+		Bidder:     dto.Bidder,
+		Conditions: dto.Conditions,
+	}
+	if dto.CreationID != nil {
+		if creationId, err := primitive.ObjectIDFromHex(*dto.CreationID); err != nil {
+			return selector, err
+		} else {
+			selector.CreationID = &creationId
+		}
+	}
+	return
+}
+
+type AuctionDto struct {
+	CreationID    string    `json:"creationId"`
+	BrandID       string    `json:"brandId"`
+	StartPrice    int       `json:"startPrice"`
+	ReservePrice  int       `json:"reservePrice"`
+	MinIncrement  int       `json:"minIncrement"`
+	HighestPrice  int       `json:"highestPrice"`
+	HighestBidder string    `json:"highestBidder"`
+	BidCount      int       `json:"bidCount"`
+	Status        string    `json:"status"`
+	CloseAt       time.Time `json:"closeAt"`
+}
+
+func (dto *AuctionDto) ID(id primitive.ObjectID) {
+	dto.CreationID = id.Hex()
+}
+
+type BidDto struct {
+	BidID    string    `json:"bidId"`
+	Creation string    `json:"creation"`
+	Bidder   string    `json:"bidder"`
+	Price    int       `json:"price"`
+	BidAt    time.Time `json:"bidAt"`
+}
+
+func (dto *BidDto) ID(id primitive.ObjectID) {
+	dto.BidID = id.Hex()
+}
+
+func (dto *BidDto) CreationID(id primitive.ObjectID) {
+	dto.Creation = id.Hex()
+}
+
+var BidSortFields = repositories.BidSortFields
+
+var BidFilterFields = repositories.BidFilterFields
+
+type BidFilterDto struct {
+	CreationID *string           `json:"creationId"`
+	Bidder     *string           `json:"bidder"`
+	Conditions []utils.Condition `json:"conditions"`
+}
+
+type PlaceBidDto struct {
+	CreationId string `json:"creationId"`
+	Price      int    `json:"price"`
+}
+
+type AuctionServiceError struct {
+	ServiceError
+}
+
+func NewAuctionServiceError(e ServiceEvent) error {
+	return &AuctionServiceError{ServiceError{ServiceName: "AuctionService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
+	"nftshopping-store-api/persistence/repositories"
+	"testing"
+	"time"
+)
+
+// deletedCreationDao finds no creation, as if every one was deleted.
+type deletedCreationDao struct {
+	repositories.CreationDao
+}
+
+func (dao *deletedCreationDao) Find(ctx context.Context, creationId primitive.ObjectID) (*repositories.Creation, error) {
+	return nil, nil
+}
+
+type openAuctionDao struct {
+	repositories.AuctionDao
+	auctions []repositories.Auction
+}
+
+func (dao *openAuctionDao) FindAllByFilter(ctx context.Context, filter repositories.AuctionFilter) ([]repositories.Auction, error) {
+	var auctions []repositories.Auction
+	for _, auction := range dao.auctions {
+		if auction.Status == repositories.AuctionOpen {
+			auctions = append(auctions, auction)
+		}
+	}
+	return auctions, nil
+}
+
+func (dao *openAuctionDao) Close(
+	ctx context.Context, creationId primitive.ObjectID, status repositories.AuctionStatus, tnxId *primitive.ObjectID, at time.Time,
+) (*repositories.Auction, error) {
+	for i := range dao.auctions {
+		if dao.auctions[i].ID == creationId && dao.auctions[i].Status == repositories.AuctionOpen {
+			dao.auctions[i].Status = status
+			dao.auctions[i].TransactionID = tnxId
+			dao.auctions[i].CloseAt = at
+			closed := dao.auctions[i]
+			return &closed, nil
+		}
+	}
+	return nil, nil
+}
+
+type biddersDao struct {
+	repositories.BidDao
+	bidders []string
+}
+
+func (dao *biddersDao) FindBidders(ctx context.Context, creationId primitive.ObjectID) ([]string, error) {
+	return dao.bidders, nil
+}
+
+type lostAuctionPublisher struct {
+	publishers.AuctionPublisher
+	lost []messages.AuctionLostMessage
+}
+
+func (publisher *lostAuctionPublisher) PublishToAuctionLost(ctx context.Context, msg messages.AuctionLostMessage) error {
+	publisher.lost = append(publisher.lost, msg)
+	return nil
+}
+
+func TestSettleAuctionOfDeletedCreation(t *testing.T) {
+	id := primitive.NewObjectID()
+	auction := &openAuctionDao{auctions: []repositories.Auction{{
+		ID:            id,
+		ReservePrice:  100,
+		HighestPrice:  200,
+		HighestBidder: "0xa",
+		BidCount:      2,
+		Status:        repositories.AuctionOpen,
+	}}}
+	publisher := &lostAuctionPublisher{}
+	service := &auctionService{
+		creation:         &creationService{creation: &deletedCreationDao{}},
+		auction:          auction,
+		bid:              &biddersDao{bidders: []string{"0xa", "0xb"}},
+		auctionPublisher: publisher,
+	}
+
+	amount, err := service.SettleAuction(context.Background())
+	if err != nil {
+		t.Fatalf("SettleAuction() error = %v", err)
+	}
+	if amount != 1 {
+		t.Errorf("amount = %d, want 1", amount)
+	}
+	if closed := auction.auctions[0]; closed.Status != repositories.AuctionUnsold || closed.TransactionID != nil {
+		t.Errorf("auction = %+v, want unsold without transaction", closed)
+	}
+	// nobody wins an unsold auction, the highest bidder is told too
+	if len(publisher.lost) != 2 {
+		t.Errorf("lost = %+v, want every bidder", publisher.lost)
+	}
+
+	amount, err = service.SettleAuction(context.Background())
+	if err != nil || amount != 0 {
+		t.Errorf("SettleAuction() = %d, %v, want nothing left to settle", amount, err)
+	}
+}
@@ -2,16 +2,29 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/config"
 	"nftshopping-store-api/pkg/security"
+	"strings"
+	"time"
 )
 
 type AuthService interface {
 	FindAuthByName(ctx context.Context, name string) (authDetail security.Authentication, err error)
+	IssueNonce(ctx context.Context, account string) (nonceDto *NonceDto, err error)
+	Login(ctx context.Context, dto LoginDto) (tokenDto *TokenDto, err error)
+	Refresh(ctx context.Context, dto RefreshDto) (tokenDto *TokenDto, err error)
+	Revoke(ctx context.Context, dto RevokeDto) (err error)
 }
 
 type authService struct {
-	auth repositories.AuthDao
+	auth  repositories.AuthDao
+	user  repositories.UserDao
+	nonce repositories.NonceDao
+	login *config.Login
 }
 
 func NewAuthService() (service AuthService, err error) {
@@ -19,8 +32,15 @@ func NewAuthService() (service AuthService, err error) {
 	if err != nil {
 		return nil, err
 	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
 	return &authService{
-		auth: dao.Auth,
+		auth:  dao.Auth,
+		user:  dao.User,
+		nonce: dao.Nonce,
+		login: c.Login,
 	}, nil
 }
 
@@ -32,6 +52,181 @@ func (service *authService) FindAuthByName(ctx context.Context, name string) (au
 	return
 }
 
+// IssueNonce hands out a single-use nonce together with the sign-in message the wallet has to sign.
+func (service *authService) IssueNonce(ctx context.Context, account string) (nonceDto *NonceDto, err error) {
+	user, err := service.user.FindByAccount(ctx, account)
+	if err != nil {
+		return
+	}
+	if user == nil {
+		return nil, NewAuthServiceError(UserNotFound)
+	}
+	random := make([]byte, 16)
+	if _, err = rand.Read(random); err != nil {
+		return
+	}
+	now := time.Now()
+	nonce := &repositories.Nonce{
+		Nonce:    hex.EncodeToString(random),
+		Account:  account,
+		IssueAt:  now,
+		ExpireAt: now.Add(service.login.NonceTimeout),
+	}
+	nonce.Message = service.signInMessage(nonce)
+	err = service.nonce.Create(ctx, nonce)
+	if err != nil {
+		return
+	}
+	return &NonceDto{
+		Account:  nonce.Account,
+		Nonce:    nonce.Nonce,
+		Message:  nonce.Message,
+		ExpireAt: nonce.ExpireAt,
+	}, nil
+}
+
+// Login consumes the nonce, recovers the signer of its message and issues a token once the
+// signer is the account. The auth record of an account is created on its first login.
+func (service *authService) Login(ctx context.Context, dto LoginDto) (tokenDto *TokenDto, err error) {
+	nonce, err := service.nonce.Consume(ctx, dto.Account, dto.Nonce, time.Now())
+	if err != nil {
+		return
+	}
+	if nonce == nil {
+		return nil, NewAuthServiceError(NonceInvalid)
+	}
+	signer, err := security.RecoverAddress(nonce.Message, dto.Signature)
+	if err != nil {
+		if err == security.ErrSignatureInvalid {
+			return nil, NewAuthServiceError(SignatureInvalid)
+		}
+		return
+	}
+	if !strings.EqualFold(signer, nonce.Account) {
+		return nil, NewAuthServiceError(SignatureInvalid)
+	}
+	auth, err := service.auth.FindByName(ctx, nonce.Account)
+	if err != nil {
+		return
+	}
+	if auth == nil {
+		auth = &repositories.Auth{
+			Name:        nonce.Account,
+			Authorities: []string{security.RoleUser},
+		}
+		err = service.auth.Create(ctx, auth)
+		if err != nil {
+			return
+		}
+	}
+	return service.issueToken(auth)
+}
+
+// Refresh trades a refresh token for a new pair of tokens, the used refresh token is revoked.
+func (service *authService) Refresh(ctx context.Context, dto RefreshDto) (tokenDto *TokenDto, err error) {
+	claim, err := service.parseUnrevoked(ctx, dto.RefreshToken)
+	if err != nil {
+		return
+	}
+	if claim.Type != security.RefreshToken {
+		return nil, NewAuthServiceError(TokenInvalid)
+	}
+	auth, err := service.auth.FindByName(ctx, claim.Subject)
+	if err != nil {
+		return
+	}
+	if auth == nil {
+		return nil, NewAuthServiceError(TokenInvalid)
+	}
+	isRevoked, err := security.RevokeToken(ctx, claim)
+	if err != nil {
+		return
+	}
+	if !isRevoked {
+		return nil, NewAuthServiceError(TokenInvalid)
+	}
+	return service.issueToken(auth)
+}
+
+func (service *authService) Revoke(ctx context.Context, dto RevokeDto) (err error) {
+	claim, err := service.parseUnrevoked(ctx, dto.Token)
+	if err != nil {
+		return
+	}
+	isRevoked, err := security.RevokeToken(ctx, claim)
+	if err != nil {
+		return
+	}
+	if !isRevoked {
+		return NewAuthServiceError(TokenInvalid)
+	}
+	return
+}
+
+func (service *authService) parseUnrevoked(ctx context.Context, token string) (claim *security.Claim, err error) {
+	claim, err = security.ParseToken(token)
+	if err != nil {
+		return nil, NewAuthServiceError(TokenInvalid)
+	}
+	isRevoked, err := security.IsTokenRevoked(ctx, claim)
+	if err != nil {
+		return
+	}
+	if isRevoked {
+		return nil, NewAuthServiceError(TokenInvalid)
+	}
+	return
+}
+
+func (service *authService) issueToken(auth security.Authentication) (tokenDto *TokenDto, err error) {
+	token, err := security.GenerateToken(auth)
+	if err != nil {
+		return
+	}
+	refreshToken, err := security.GenerateRefreshToken(auth)
+	if err != nil {
+		return
+	}
+	return &TokenDto{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// signInMessage follows the layout of EIP-4361 so wallets can show it in a readable way.
+func (service *authService) signInMessage(nonce *repositories.Nonce) string {
+	return fmt.Sprintf(
+		"%s wants you to sign in with your Ethereum account:\n%s\n\n%s\n\n"+
+			"URI: %s\nVersion: 1\nChain ID: %d\nNonce: %s\nIssued At: %s\nExpiration Time: %s",
+		service.login.Domain, nonce.Account, service.login.Statement,
+		service.login.Uri, service.login.ChainId, nonce.Nonce,
+		nonce.IssueAt.UTC().Format(time.RFC3339), nonce.ExpireAt.UTC().Format(time.RFC3339),
+	)
+}
+
+type NonceDto struct {
+	Account  string    `json:"account"`
+	Nonce    string    `json:"nonce"`
+	Message  string    `json:"message"`
+	ExpireAt time.Time `json:"expireAt"`
+}
+
+type LoginDto struct {
+	Account   string `json:"account"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+type TokenDto struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type RefreshDto struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type RevokeDto struct {
+	Token string `json:"token"`
+}
+
 type AuthServiceError struct {
 	ServiceError
 }
@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 	"github.com/jinzhu/copier"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
 	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/security"
 	"nftshopping-store-api/pkg/utils"
 	"time"
 )
@@ -16,10 +19,18 @@ type BrandService interface {
 	PostBrand(ctx context.Context, dto PostBrandDto) (brandsDto *BrandDto, err error)
 	UpdateBrand(ctx context.Context, dto UpdateBrandDto) (err error)
 	DeleteBrand(ctx context.Context, brandId string) (err error)
+	CheckPermission(ctx context.Context, brandId string, roles ...repositories.BrandRole) (err error)
+	FindAllMember(ctx context.Context, brandId string) (membersDto []BrandMemberDto, err error)
+	AddMember(ctx context.Context, dto BrandMemberDto) (memberDto *BrandMemberDto, err error)
+	UpdateMember(ctx context.Context, dto BrandMemberDto) (memberDto *BrandMemberDto, err error)
+	RemoveMember(ctx context.Context, dto RemoveBrandMemberDto) (err error)
 }
 
 type brandService struct {
-	brand repositories.BrandDao
+	brand          repositories.BrandDao
+	member         repositories.BrandMemberDao
+	user           repositories.UserDao
+	brandPublisher publishers.BrandPublisher
 }
 
 func NewBrandService() (service BrandService, err error) {
@@ -27,8 +38,15 @@ func NewBrandService() (service BrandService, err error) {
 	if err != nil {
 		return nil, err
 	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
 	return &brandService{
-		brand: dao.Brand,
+		brand:          dao.Brand,
+		member:         dao.BrandMember,
+		user:           dao.User,
+		brandPublisher: publisher.Brand,
 	}, nil
 }
 
@@ -50,12 +68,7 @@ func (service *brandService) FindBrandById(ctx context.Context, id string) (bran
 }
 
 func (service *brandService) FindAllBrandByFilter(ctx context.Context, dto BrandFilterDto) (brandsDto []BrandDto, err error) {
-	selector := repositories.BrandSelector{
-		Name:         dto.Name,
-		CreateAfter:  dto.CreateAfter,
-		CreateBefore: dto.CreateBefore,
-	}
-	brands, err := service.brand.FindAllByFilter(ctx, repositories.SelectorOfBrand(selector))
+	brands, err := service.brand.FindAllByFilter(ctx, repositories.SelectorOfBrand(selectorOfBrandFilter(dto)))
 	if err != nil {
 		return
 	}
@@ -68,12 +81,7 @@ func (service *brandService) FindAllBrandByFilter(ctx context.Context, dto Brand
 func (service *brandService) FindAllBrandByFilterAndPage(
 	ctx context.Context, dto BrandFilterDto, pageable utils.Pageable,
 ) (brandsDto []BrandDto, err error) {
-	selector := repositories.BrandSelector{
-		Name:         dto.Name,
-		CreateAfter:  dto.CreateAfter,
-		CreateBefore: dto.CreateBefore,
-	}
-	page, err := service.brand.FindAllByFilterAndPage(ctx, repositories.SelectorOfBrand(selector), pageable)
+	page, err := service.brand.FindAllByFilterAndPage(ctx, repositories.SelectorOfBrand(selectorOfBrandFilter(dto)), pageable)
 	if err != nil || page == nil {
 		return
 	}
@@ -88,7 +96,15 @@ func (service *brandService) FindAllBrandByFilterAndPage(
 	return
 }
 
+// PostBrand creates the brand with the caller as its owner.
 func (service *brandService) PostBrand(ctx context.Context, dto PostBrandDto) (brandDto *BrandDto, err error) {
+	auth, ok := security.AuthenticationFrom(ctx)
+	if !ok {
+		return nil, NewBrandServiceError(BrandPermissionDenied)
+	}
+	if !isValidBps(dto.RoyaltyBps) {
+		return nil, NewBrandServiceError(RoyaltyInvalid)
+	}
 	brand := &repositories.Brand{}
 	if err = copier.Copy(brand, &dto); err != nil {
 		return
@@ -99,6 +115,28 @@ func (service *brandService) PostBrand(ctx context.Context, dto PostBrandDto) (b
 	if err != nil {
 		return
 	}
+	err = service.member.Create(ctx, &repositories.BrandMember{
+		BrandID:  brand.ID,
+		Member:   auth.GetName(),
+		Role:     repositories.BrandOwner,
+		CreateAt: brand.CreateAt,
+		UpdateAt: brand.CreateAt,
+	})
+	if err != nil {
+		return
+	}
+	err = service.brandPublisher.PublishToBrandCreated(ctx, messages.BrandCreatedMessage{
+		BrandId:     brand.ID,
+		Name:        brand.Name,
+		Description: brand.Description,
+		ImageUrl:    brand.ImageURL,
+		RoyaltyBps:  brand.RoyaltyBps,
+		Owner:       auth.GetName(),
+		CreateAt:    brand.CreateAt,
+	})
+	if err != nil {
+		return
+	}
 	brandDto = &BrandDto{}
 	err = copier.Copy(brandDto, brand)
 	if err != nil {
@@ -116,6 +154,13 @@ func (service *brandService) UpdateBrand(ctx context.Context, dto UpdateBrandDto
 	if brand == nil {
 		return NewBrandServiceError(BrandNotFound)
 	}
+	err = service.CheckPermission(ctx, brand.ID, repositories.BrandOwner, repositories.BrandAdmin)
+	if err != nil {
+		return
+	}
+	if dto.RoyaltyBps != nil && !isValidBps(*dto.RoyaltyBps) {
+		return NewBrandServiceError(RoyaltyInvalid)
+	}
 	err = copier.Copy(brand, &dto)
 	if err != nil {
 		return
@@ -124,22 +169,264 @@ func (service *brandService) UpdateBrand(ctx context.Context, dto UpdateBrandDto
 	if err != nil {
 		return
 	}
-	return
+	return service.brandPublisher.PublishToBrandUpdated(ctx, messages.BrandUpdatedMessage{
+		BrandId:     brand.ID,
+		Name:        brand.Name,
+		Description: brand.Description,
+		ImageUrl:    brand.ImageURL,
+		RoyaltyBps:  brand.RoyaltyBps,
+		UpdateAt:    time.Now(),
+	})
 }
 
 func (service *brandService) DeleteBrand(ctx context.Context, brandId string) (err error) {
+	err = service.CheckPermission(ctx, brandId, repositories.BrandOwner)
+	if err != nil {
+		return
+	}
 	err = service.brand.Delete(ctx, brandId)
 	if err != nil {
 		return
 	}
+	err = service.member.DeleteByBrand(ctx, brandId)
+	if err != nil {
+		return
+	}
+	return service.brandPublisher.PublishToBrandDeleted(ctx, messages.BrandDeletedMessage{
+		BrandId:  brandId,
+		DeleteAt: time.Now(),
+	})
+}
+
+// CheckPermission makes sure the caller is a member of the brand holding one of the roles,
+// any role will do when none is given. Platform admins are always allowed.
+func (service *brandService) CheckPermission(
+	ctx context.Context, brandId string, roles ...repositories.BrandRole,
+) (err error) {
+	role, err := service.roleOfCaller(ctx, brandId)
+	if err != nil {
+		return
+	}
+	if len(role) == 0 {
+		return NewBrandServiceError(BrandPermissionDenied)
+	}
+	if len(roles) == 0 {
+		return
+	}
+	for _, r := range roles {
+		if role == r {
+			return
+		}
+	}
+	return NewBrandServiceError(BrandPermissionDenied)
+}
+
+func (service *brandService) FindAllMember(ctx context.Context, brandId string) (membersDto []BrandMemberDto, err error) {
+	err = service.CheckPermission(ctx, brandId)
+	if err != nil {
+		return
+	}
+	members, err := service.member.FindAllByBrand(ctx, brandId)
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&membersDto, &members); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *brandService) AddMember(ctx context.Context, dto BrandMemberDto) (memberDto *BrandMemberDto, err error) {
+	role := repositories.BrandRole(dto.Role)
+	if !isBrandRole(role) {
+		return nil, NewBrandServiceError(BrandRoleInvalid)
+	}
+	err = service.checkManage(ctx, dto.BrandID, role)
+	if err != nil {
+		return
+	}
+	if isExisted, err := service.user.ExistByAccount(ctx, dto.Member); err != nil {
+		return nil, err
+	} else {
+		if !isExisted {
+			return nil, NewBrandServiceError(UserNotFound)
+		}
+	}
+	now := time.Now()
+	member := &repositories.BrandMember{
+		BrandID:  dto.BrandID,
+		Member:   dto.Member,
+		Role:     role,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	err = service.member.Create(ctx, member)
+	if err != nil {
+		if err == repositories.BrandMemberDuplicate {
+			return nil, NewBrandServiceError(BrandMemberDuplicate)
+		}
+		return
+	}
+	err = service.brandPublisher.PublishToBrandMemberAdded(ctx, messages.BrandMemberMessage{
+		BrandId:  member.BrandID,
+		Member:   member.Member,
+		To:       string(member.Role),
+		ChangeAt: now,
+	})
+	if err != nil {
+		return
+	}
+	memberDto = &BrandMemberDto{}
+	if err = copier.Copy(memberDto, member); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *brandService) UpdateMember(ctx context.Context, dto BrandMemberDto) (memberDto *BrandMemberDto, err error) {
+	role := repositories.BrandRole(dto.Role)
+	if !isBrandRole(role) {
+		return nil, NewBrandServiceError(BrandRoleInvalid)
+	}
+	member, err := service.member.Find(ctx, dto.BrandID, dto.Member)
+	if err != nil {
+		return
+	}
+	if member == nil {
+		return nil, NewBrandServiceError(BrandMemberNotFound)
+	}
+	// the caller has to be able to manage both the current and the new role
+	err = service.checkManage(ctx, dto.BrandID, member.Role, role)
+	if err != nil {
+		return
+	}
+	if member.Role == repositories.BrandOwner && role != repositories.BrandOwner {
+		if err = service.checkOtherOwner(ctx, dto.BrandID); err != nil {
+			return
+		}
+	}
+	from := member.Role
+	member, err = service.member.UpdateRole(ctx, dto.BrandID, dto.Member, role)
+	if err != nil {
+		return
+	}
+	if member == nil {
+		return nil, NewBrandServiceError(BrandMemberNotFound)
+	}
+	err = service.brandPublisher.PublishToBrandMemberUpdated(ctx, messages.BrandMemberMessage{
+		BrandId:  member.BrandID,
+		Member:   member.Member,
+		From:     string(from),
+		To:       string(member.Role),
+		ChangeAt: member.UpdateAt,
+	})
+	if err != nil {
+		return
+	}
+	memberDto = &BrandMemberDto{}
+	if err = copier.Copy(memberDto, member); err != nil {
+		return nil, err
+	}
 	return
 }
 
+func (service *brandService) RemoveMember(ctx context.Context, dto RemoveBrandMemberDto) (err error) {
+	member, err := service.member.Find(ctx, dto.BrandID, dto.Member)
+	if err != nil {
+		return
+	}
+	if member == nil {
+		return NewBrandServiceError(BrandMemberNotFound)
+	}
+	err = service.checkManage(ctx, dto.BrandID, member.Role)
+	if err != nil {
+		return
+	}
+	if member.Role == repositories.BrandOwner {
+		if err = service.checkOtherOwner(ctx, dto.BrandID); err != nil {
+			return
+		}
+	}
+	err = service.member.Delete(ctx, dto.BrandID, dto.Member)
+	if err != nil {
+		if err == repositories.BrandMemberNotFound {
+			return NewBrandServiceError(BrandMemberNotFound)
+		}
+		return
+	}
+	return service.brandPublisher.PublishToBrandMemberRemoved(ctx, messages.BrandMemberMessage{
+		BrandId:  dto.BrandID,
+		Member:   dto.Member,
+		From:     string(member.Role),
+		ChangeAt: time.Now(),
+	})
+}
+
+// roleOfCaller returns the brand role of the caller, empty if the caller is not a member.
+// Platform admins act as owners of every brand.
+func (service *brandService) roleOfCaller(ctx context.Context, brandId string) (role repositories.BrandRole, err error) {
+	auth, ok := security.AuthenticationFrom(ctx)
+	if !ok {
+		return
+	}
+	if security.HasAuthority(auth, security.RoleAdmin) {
+		return repositories.BrandOwner, nil
+	}
+	member, err := service.member.Find(ctx, brandId, auth.GetName())
+	if err != nil || member == nil {
+		return
+	}
+	return member.Role, nil
+}
+
+// checkManage lets owners manage every role and admins manage admins and editors.
+func (service *brandService) checkManage(ctx context.Context, brandId string, roles ...repositories.BrandRole) (err error) {
+	role, err := service.roleOfCaller(ctx, brandId)
+	if err != nil {
+		return
+	}
+	switch role {
+	case repositories.BrandOwner:
+		return
+	case repositories.BrandAdmin:
+		for _, r := range roles {
+			if r == repositories.BrandOwner {
+				return NewBrandServiceError(BrandPermissionDenied)
+			}
+		}
+		return
+	default:
+		return NewBrandServiceError(BrandPermissionDenied)
+	}
+}
+
+// checkOtherOwner keeps a brand from losing its last owner.
+func (service *brandService) checkOtherOwner(ctx context.Context, brandId string) (err error) {
+	count, err := service.member.CountByRole(ctx, brandId, repositories.BrandOwner)
+	if err != nil {
+		return
+	}
+	if count <= 1 {
+		return NewBrandServiceError(BrandOwnerRequired)
+	}
+	return
+}
+
+func isBrandRole(role repositories.BrandRole) bool {
+	switch role {
+	case repositories.BrandOwner, repositories.BrandAdmin, repositories.BrandEditor:
+		return true
+	default:
+		return false
+	}
+}
+
 type BrandDto struct {
 	BrandID     string    `json:"brandId"`
 	Name        string    `json:"name"`
 	ImageURL    string    `json:"imageUrl"`
 	Description string    `json:"description"`
+	RoyaltyBps  int       `json:"royaltyBps"`
 	CreateAt    time.Time `json:"createAt"`
 }
 
@@ -147,22 +434,51 @@ func (dto *BrandDto) ID(id string) {
 	dto.BrandID = id
 }
 
+func selectorOfBrandFilter(dto BrandFilterDto) repositories.BrandSelector {
+	return repositories.BrandSelector{
+		Name:         dto.Name,
+		CreateAfter:  dto.CreateAfter,
+		CreateBefore: dto.CreateBefore,
+		Conditions:   dto.Conditions,
+	}
+}
+
+var BrandSortFields = repositories.BrandSortFields
+
+var BrandFilterFields = repositories.BrandFilterFields
+
 type BrandFilterDto struct {
-	Name         *string    `json:"name"`
-	CreateAfter  *time.Time `json:"createAfter"`
-	CreateBefore *time.Time `json:"createBefore"`
+	Name         *string           `json:"name"`
+	CreateAfter  *time.Time        `json:"createAfter"`
+	CreateBefore *time.Time        `json:"createBefore"`
+	Conditions   []utils.Condition `json:"conditions"`
 }
 
 type PostBrandDto struct {
 	Name        string `json:"name"`
 	ImageURL    string `json:"imageUrl"`
 	Description string `json:"description"`
+	RoyaltyBps  int    `json:"royaltyBps"`
 }
 
 type UpdateBrandDto struct {
-	BrandID  string `json:"brandId"`
-	Name     string `json:"name"`
-	ImageURL string `json:"imageUrl"`
+	BrandID    string `json:"brandId"`
+	Name       string `json:"name"`
+	ImageURL   string `json:"imageUrl"`
+	RoyaltyBps *int   `json:"royaltyBps"`
+}
+
+type BrandMemberDto struct {
+	BrandID  string    `json:"brandId"`
+	Member   string    `json:"member"`
+	Role     string    `json:"role"`
+	CreateAt time.Time `json:"createAt"`
+	UpdateAt time.Time `json:"updateAt"`
+}
+
+type RemoveBrandMemberDto struct {
+	BrandID string `json:"brandId"`
+	Member  string `json:"member"`
 }
 
 type BrandServiceError struct {
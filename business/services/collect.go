@@ -17,6 +17,9 @@ type CollectService interface {
 	FindAllCollectByFilterAndPage(
 		ctx context.Context, filter CollectFilterDto, pageable utils.Pageable,
 	) (collectionsDto []CollectDto, err error)
+	FindAllCollectByFilterAndCursor(
+		ctx context.Context, filter CollectFilterDto, pageable utils.Pageable,
+	) (collectsDto []CollectDto, next string, err error)
 }
 
 type CollectDto struct {
@@ -30,9 +33,14 @@ func (dto *CollectDto) ID(id repositories.CollectID) {
 	dto.Owner = id.Owner
 }
 
+var CollectSortFields = repositories.CollectSortFields
+
+var CollectFilterFields = repositories.CollectFilterFields
+
 type CollectFilterDto struct {
-	Owner      *string `json:"owner"`
-	CreationID *string `json:"creationId"`
+	Owner      *string           `json:"owner"`
+	CreationID *string           `json:"creationId"`
+	Conditions []utils.Condition `json:"conditions"`
 }
 
 type CollectionService interface {
@@ -93,15 +101,9 @@ func (service *collectionService) FindAllCollect(ctx context.Context) (collectio
 func (service *collectionService) FindAllCollectByFilter(
 	ctx context.Context, filter CollectFilterDto,
 ) (collectionsDto []CollectDto, err error) {
-	selector := repositories.CollectSelector{
-		Owner: filter.Owner,
-	}
-	if filter.CreationID != nil {
-		if creationId, err := primitive.ObjectIDFromHex(*filter.CreationID); err != nil {
-			return nil, err
-		} else {
-			selector.CreationID = &creationId
-		}
+	selector, err := selectorOfCollectFilter(filter)
+	if err != nil {
+		return
 	}
 	collections, err := service.collection.FindAllByFilter(
 		ctx, repositories.SelectorOfCollect(selector),
@@ -118,15 +120,9 @@ func (service *collectionService) FindAllCollectByFilter(
 func (service *collectionService) FindAllCollectByFilterAndPage(
 	ctx context.Context, filter CollectFilterDto, pageable utils.Pageable,
 ) (collectionsDto []CollectDto, err error) {
-	selector := repositories.CollectSelector{
-		Owner: filter.Owner,
-	}
-	if filter.CreationID != nil {
-		if creationId, err := primitive.ObjectIDFromHex(*filter.CreationID); err != nil {
-			return nil, err
-		} else {
-			selector.CreationID = &creationId
-		}
+	selector, err := selectorOfCollectFilter(filter)
+	if err != nil {
+		return
 	}
 	page, err := service.collection.FindAllByFilterAndPage(
 		ctx, repositories.SelectorOfCollect(selector), pageable,
@@ -145,6 +141,42 @@ func (service *collectionService) FindAllCollectByFilterAndPage(
 	return
 }
 
+func (service *collectionService) FindAllCollectByFilterAndCursor(
+	ctx context.Context, filter CollectFilterDto, pageable utils.Pageable,
+) (collectsDto []CollectDto, next string, err error) {
+	selector, err := selectorOfCollectFilter(filter)
+	if err != nil {
+		return
+	}
+	page, err := service.collection.FindAllByFilterAndCursor(ctx, repositories.SelectorOfCollect(selector), pageable)
+	if err != nil {
+		return
+	}
+	collects, ok := page.Content.([]repositories.Collect)
+	if !ok {
+		return nil, "", utils.ErrCovertContent
+	}
+	if err = copier.Copy(&collectsDto, &collects); err != nil {
+		return nil, "", err
+	}
+	return collectsDto, page.NextCursor, nil
+}
+
+func selectorOfCollectFilter(filter CollectFilterDto) (selector repositories.CollectSelector, err error) {
+	selector = repositories.CollectSelector{
+		Owner:      filter.Owner,
+		Conditions: filter.Conditions,
+	}
+	if filter.CreationID != nil {
+		creationId, err := primitive.ObjectIDFromHex(*filter.CreationID)
+		if err != nil {
+			return selector, err
+		}
+		selector.CreationID = &creationId
+	}
+	return
+}
+
 type CollectionServiceError struct {
 	ServiceError
 }
@@ -217,15 +249,9 @@ func (service *stockService) FindAllCollectByFilter(
 func (service *stockService) FindAllCollectByFilterAndPage(
 	ctx context.Context, filter CollectFilterDto, pageable utils.Pageable,
 ) (collectDto []CollectDto, err error) {
-	selector := repositories.CollectSelector{
-		Owner: filter.Owner,
-	}
-	if filter.CreationID != nil {
-		if creationId, err := primitive.ObjectIDFromHex(*filter.CreationID); err != nil {
-			return nil, err
-		} else {
-			selector.CreationID = &creationId
-		}
+	selector, err := selectorOfCollectFilter(filter)
+	if err != nil {
+		return
 	}
 	collect, err := service.stock.FindAllByFilter(
 		ctx, repositories.SelectorOfCollect(selector),
@@ -239,6 +265,27 @@ func (service *stockService) FindAllCollectByFilterAndPage(
 	return
 }
 
+func (service *stockService) FindAllCollectByFilterAndCursor(
+	ctx context.Context, filter CollectFilterDto, pageable utils.Pageable,
+) (collectsDto []CollectDto, next string, err error) {
+	selector, err := selectorOfCollectFilter(filter)
+	if err != nil {
+		return
+	}
+	page, err := service.stock.FindAllByFilterAndCursor(ctx, repositories.SelectorOfCollect(selector), pageable)
+	if err != nil {
+		return
+	}
+	collects, ok := page.Content.([]repositories.Collect)
+	if !ok {
+		return nil, "", utils.ErrCovertContent
+	}
+	if err = copier.Copy(&collectsDto, &collects); err != nil {
+		return nil, "", err
+	}
+	return collectsDto, page.NextCursor, nil
+}
+
 type StockServiceError struct {
 	ServiceError
 }
@@ -6,10 +6,13 @@ import (
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"items"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
 	"nftshopping-store-api/persistence/repositories"
 	"nftshopping-store-api/pkg/caches"
 	"nftshopping-store-api/pkg/nftshopping"
 	"nftshopping-store-api/pkg/utils"
+	"strings"
 	"time"
 )
 
@@ -18,13 +21,26 @@ type CreationService interface {
 	FindCreationByID(ctx context.Context, id string) (creationDto *CreationDto, err error)
 	FindAllCreationByFilter(ctx context.Context, dto CreationFilterDto) (creationsDto []CreationDto, err error)
 	FindAllCreationByFilterAndPage(ctx context.Context, dto CreationFilterDto, pageable utils.Pageable) (creationsDto []CreationDto, err error)
+	FindAllCreationByFilterAndCursor(ctx context.Context, dto CreationFilterDto, pageable utils.Pageable) (creationsDto []CreationDto, next string, err error)
 	PostCreation(ctx context.Context, dto PostCreationDto) (creationDto *CreationDto, err error)
 	DeleteCreation(ctx context.Context, id primitive.ObjectID) (err error)
 	UpdateCreation(ctx context.Context, dto UpdateCreationDto) (err error)
+	ReserveCreation(ctx context.Context, id string, amount int) (creationDto *CreationDto, err error)
+	ReleaseCreation(ctx context.Context, id string, amount int) (err error)
+	UpdateSaleStatus(ctx context.Context, dto UpdateSaleStatusDto) (err error)
+	CheckSoldOut(ctx context.Context, id string) (isSoldOut bool, err error)
+	ScheduleSaleStatus(ctx context.Context) (amount int, err error)
+	SearchCreation(ctx context.Context, dto CreationSearchFilterDto, pageable utils.Pageable) (searchDto *CreationSearchDto, err error)
+	AutocompleteCreation(ctx context.Context, prefix string, limit int) (suggestionsDto []CreationSuggestionDto, err error)
 }
 
 type creationService struct {
 	creation          repositories.CreationDao
+	auction           repositories.AuctionDao
+	bid               repositories.BidDao
+	item              repositories.ItemDao
+	creationPublisher publishers.CreationPublisher
+	auctionPublisher  publishers.AuctionPublisher
 	creationCache     *lru.Cache
 	creationListCache *lru.Cache
 	brand             BrandService
@@ -48,12 +64,21 @@ func NewCreationService(brand BrandService) (service CreationService, err error)
 	if err != nil {
 		return
 	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
 	item, err := nftshopping.GetItem()
 	if err != nil {
 		return
 	}
 	return &creationService{
 		creation:          dao.Creation,
+		auction:           dao.Auction,
+		bid:               dao.Bid,
+		item:              dao.Item,
+		creationPublisher: publisher.Creation,
+		auctionPublisher:  publisher.Auction,
 		creationCache:     creationCache,
 		creationListCache: creationListCache,
 		brand:             brand,
@@ -76,7 +101,7 @@ func (service *creationService) FindCreationByID(ctx context.Context, id string)
 		return nil, nil
 	}
 	creation, err := service.creation.Find(ctx, creationId)
-	if err != nil {
+	if err != nil || creation == nil {
 		return
 	}
 	creationDto = &CreationDto{}
@@ -91,23 +116,9 @@ func (service *creationService) FindAllCreationByFilter(ctx context.Context, dto
 	//if ok{
 	//	return
 	//}
-	var creationIds []primitive.ObjectID
-	for _, id := range dto.CreationIDs {
-		creationId, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return nil, err
-		}
-		creationIds = append(creationIds, creationId)
-	}
-	selector := repositories.CreationSelector{
-		CreationIDs:     creationIds,
-		CreationName:    dto.CreationName,
-		Properties:      dto.Properties,
-		SaleStartAfter:  dto.SaleEndAfter,
-		SaleStartBefore: dto.SaleStartBefore,
-		MaxPrice:        dto.MaxPrice,
-		MinPrice:        dto.MinPrice,
-		BrandID:         dto.BrandID,
+	selector, err := selectorOfCreationFilter(dto)
+	if err != nil {
+		return
 	}
 	creations, err := service.creation.FindAllByFilter(ctx, repositories.SelectorOfCreation(selector))
 	if err != nil {
@@ -125,24 +136,9 @@ func (service *creationService) FindAllCreationByFilterAndPage(ctx context.Conte
 	//if ok{
 	//	return
 	//}
-	var creationIds []primitive.ObjectID
-	for _, id := range dto.CreationIDs {
-		creationId, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return nil, err
-		}
-		creationIds = append(creationIds, creationId)
-	}
-	selector := repositories.CreationSelector{
-		CreationIDs:     creationIds,
-		CreationName:    dto.CreationName,
-		Creator:         dto.Creator,
-		Properties:      dto.Properties,
-		SaleStartAfter:  dto.SaleEndAfter,
-		SaleStartBefore: dto.SaleStartBefore,
-		MaxPrice:        dto.MaxPrice,
-		MinPrice:        dto.MinPrice,
-		BrandID:         dto.BrandID,
+	selector, err := selectorOfCreationFilter(dto)
+	if err != nil {
+		return
 	}
 	page, err := service.creation.FindAllByFilterAndPage(ctx, repositories.SelectorOfCreation(selector), pageable)
 	if err != nil {
@@ -159,6 +155,27 @@ func (service *creationService) FindAllCreationByFilterAndPage(ctx context.Conte
 	return
 }
 
+func (service *creationService) FindAllCreationByFilterAndCursor(
+	ctx context.Context, dto CreationFilterDto, pageable utils.Pageable,
+) (creationsDto []CreationDto, next string, err error) {
+	selector, err := selectorOfCreationFilter(dto)
+	if err != nil {
+		return
+	}
+	page, err := service.creation.FindAllByFilterAndCursor(ctx, repositories.SelectorOfCreation(selector), pageable)
+	if err != nil {
+		return
+	}
+	creations, ok := page.Content.([]repositories.Creation)
+	if !ok {
+		return nil, "", utils.ErrCovertContent
+	}
+	if err = copier.Copy(&creationsDto, &creations); err != nil {
+		return nil, "", err
+	}
+	return creationsDto, page.NextCursor, nil
+}
+
 func (service *creationService) PostCreation(ctx context.Context, dto PostCreationDto) (creationDto *CreationDto, err error) {
 	if isExisted, err := service.brand.Exist(ctx, dto.BrandID); err != nil {
 		return nil, err
@@ -167,18 +184,65 @@ func (service *creationService) PostCreation(ctx context.Context, dto PostCreati
 			return nil, NewBrandServiceError(BrandNotFound)
 		}
 	}
+	err = service.brand.CheckPermission(ctx, dto.BrandID)
+	if err != nil {
+		return
+	}
+	if !isValidBps(dto.RoyaltyBps) {
+		return nil, NewCreationServiceError(RoyaltyInvalid)
+	}
+	if repositories.SaleWay(dto.SaleWay) == repositories.SaleWayAuction && (dto.MinIncrement <= 0 || dto.ReservePrice < 0) {
+		return nil, NewCreationServiceError(AuctionRuleInvalid)
+	}
 	creation := &repositories.Creation{}
 	if err = copier.Copy(creation, &dto); err != nil {
 		return
 	}
 	creation.ID = primitive.NewObjectID()
 	creation.CreateAt = time.Now()
-	creation.SaleStatus = "WAIT_FOR_SALE"
+	creation.SaleStatus = repositories.SaleStatusWaitForSale
 	creation.BrandID = dto.BrandID
+	if len(creation.SaleWay) == 0 {
+		creation.SaleWay = repositories.SaleWayFixedPrice
+	}
 	err = service.creation.Create(ctx, creation)
 	if err != nil {
 		return
 	}
+	if creation.SaleWay == repositories.SaleWayAuction {
+		err = service.auction.Create(ctx, &repositories.Auction{
+			ID:           creation.ID,
+			BrandID:      creation.BrandID,
+			StartPrice:   creation.Price,
+			ReservePrice: dto.ReservePrice,
+			MinIncrement: dto.MinIncrement,
+			Status:       repositories.AuctionOpen,
+			CreateAt:     creation.CreateAt,
+			UpdateAt:     creation.CreateAt,
+		})
+		if err != nil {
+			return
+		}
+	}
+	err = service.creationPublisher.PublishToCreationCreated(ctx, messages.CreationCreatedMessage{
+		CreationId:      creation.ID.Hex(),
+		CreationName:    creation.CreationName,
+		BrandId:         creation.BrandID,
+		Creator:         creation.Creator,
+		Amount:          creation.Amount,
+		Price:           creation.Price,
+		RoyaltyBps:      creation.RoyaltyBps,
+		Properties:      creation.Properties,
+		SaleWay:         string(creation.SaleWay),
+		SaleStatus:      string(creation.SaleStatus),
+		SaleStartAt:     creation.SaleStartAt,
+		SaleEndAt:       creation.SaleEndAt,
+		ContractAddress: creation.ContractAddress,
+		CreateAt:        creation.CreateAt,
+	})
+	if err != nil {
+		return
+	}
 	creationDto = &CreationDto{}
 	err = copier.Copy(creationDto, creation)
 	if err != nil {
@@ -202,13 +266,36 @@ func (service *creationService) PostCreation(ctx context.Context, dto PostCreati
 }
 
 func (service *creationService) DeleteCreation(ctx context.Context, id primitive.ObjectID) (err error) {
+	creation, err := service.creation.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return NewCreationServiceError(CreationNotFound)
+	}
+	err = service.brand.CheckPermission(ctx, creation.BrandID)
+	if err != nil {
+		return
+	}
 	err = service.creation.Delete(ctx, id)
 	if err != nil {
-		if err != repositories.CreationNotFound {
-			return nil
+		return
+	}
+	// the open auction of a deleted creation would never settle, close it as unsold
+	closed, err := service.auction.Close(ctx, id, repositories.AuctionUnsold, nil, time.Now())
+	if err != nil {
+		return
+	}
+	if closed != nil {
+		if err = notifyAuctionLosers(ctx, service.bid, service.auctionPublisher, closed, ""); err != nil {
+			return
 		}
 	}
-	return
+	return service.creationPublisher.PublishToCreationDeleted(ctx, messages.CreationDeletedMessage{
+		CreationId: creation.ID.Hex(),
+		BrandId:    creation.BrandID,
+		DeleteAt:   time.Now(),
+	})
 }
 
 func (service *creationService) getCreationListFromCache(ctx context.Context, dto CreationFilterDto) (creationsDto []CreationDto, ok bool) {
@@ -251,6 +338,13 @@ func (service *creationService) UpdateCreation(ctx context.Context, dto UpdateCr
 	if creation == nil {
 		return NewCreationServiceError(CreationNotFound)
 	}
+	err = service.brand.CheckPermission(ctx, creation.BrandID)
+	if err != nil {
+		return
+	}
+	if dto.RoyaltyBps != nil && !isValidBps(*dto.RoyaltyBps) {
+		return NewCreationServiceError(RoyaltyInvalid)
+	}
 	err = copier.Copy(creation, dto)
 	if err != nil {
 		return
@@ -259,6 +353,344 @@ func (service *creationService) UpdateCreation(ctx context.Context, dto UpdateCr
 	if err != nil {
 		return
 	}
+	return service.creationPublisher.PublishToCreationUpdated(ctx, messages.CreationUpdatedMessage{
+		CreationId:   creation.ID.Hex(),
+		BrandId:      creation.BrandID,
+		CreationName: creation.CreationName,
+		Description:  creation.Description,
+		RoyaltyBps:   creation.RoyaltyBps,
+		SaleStartAt:  creation.SaleStartAt,
+		SaleEndAt:    creation.SaleEndAt,
+		UpdateAt:     time.Now(),
+	})
+}
+
+func (service *creationService) ReserveCreation(
+	ctx context.Context, id string, amount int,
+) (creationDto *CreationDto, err error) {
+	creationId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, NewCreationServiceError(CreationNotFound)
+	}
+	creation, err := service.creation.Find(ctx, creationId)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return nil, NewCreationServiceError(CreationNotFound)
+	}
+	if creation.SaleWay == repositories.SaleWayAuction {
+		return nil, NewCreationServiceError(CreationOnAuction)
+	}
+	now := time.Now()
+	if !isOnSale(creation, now) {
+		return nil, NewCreationServiceError(CreationNotOnSale)
+	}
+	if creation.Reserved+amount > creation.Amount {
+		return nil, NewCreationServiceError(CreationSoldOut)
+	}
+	isReserved, err := service.creation.Reserve(ctx, creationId, amount, now)
+	if err != nil {
+		return
+	}
+	if !isReserved {
+		return nil, NewCreationServiceError(CreationSoldOut)
+	}
+	creation.Reserved += amount
+	creationDto = &CreationDto{}
+	if err = copier.Copy(creationDto, creation); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *creationService) ReleaseCreation(ctx context.Context, id string, amount int) (err error) {
+	creationId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return NewCreationServiceError(CreationNotFound)
+	}
+	err = service.creation.Release(ctx, creationId, amount)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (service *creationService) UpdateSaleStatus(ctx context.Context, dto UpdateSaleStatusDto) (err error) {
+	id, err := primitive.ObjectIDFromHex(dto.CreationID)
+	if err != nil {
+		return NewCreationServiceError(CreationNotFound)
+	}
+	creation, err := service.creation.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return NewCreationServiceError(CreationNotFound)
+	}
+	err = service.brand.CheckPermission(ctx, creation.BrandID)
+	if err != nil {
+		return
+	}
+	isChanged, err := service.changeSaleStatus(ctx, creation, repositories.SaleStatus(dto.SaleStatus))
+	if err != nil {
+		return
+	}
+	if !isChanged {
+		return NewCreationServiceError(SaleStatusInvalid)
+	}
+	return
+}
+
+// CheckSoldOut marks the creation as sold out once every item of it has been minted.
+func (service *creationService) CheckSoldOut(ctx context.Context, id string) (isSoldOut bool, err error) {
+	creationId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, NewCreationServiceError(CreationNotFound)
+	}
+	creation, err := service.creation.Find(ctx, creationId)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return false, NewCreationServiceError(CreationNotFound)
+	}
+	return service.checkSoldOut(ctx, creation)
+}
+
+// ScheduleSaleStatus moves creations along their sale window: waiting creations
+// go on sale once SaleStartAt has passed, creations on sale are marked sold out
+// when fully minted, and anything still waiting or on sale ends after SaleEndAt.
+func (service *creationService) ScheduleSaleStatus(ctx context.Context) (amount int, err error) {
+	now := time.Now()
+	waiting, err := service.creation.FindAllByFilter(ctx, repositories.SelectorOfCreation(repositories.CreationSelector{
+		SaleStatus:      []repositories.SaleStatus{repositories.SaleStatusWaitForSale},
+		SaleStartBefore: &now,
+	}))
+	if err != nil {
+		return
+	}
+	for i := range waiting {
+		if !now.Before(waiting[i].SaleEndAt) {
+			continue
+		}
+		isChanged, err := service.changeSaleStatus(ctx, &waiting[i], repositories.SaleStatusOnSale)
+		if err != nil {
+			return amount, err
+		}
+		if isChanged {
+			amount++
+		}
+	}
+
+	onSale, err := service.creation.FindAllByFilter(ctx, repositories.SelectorOfCreation(repositories.CreationSelector{
+		SaleStatus: []repositories.SaleStatus{repositories.SaleStatusOnSale},
+	}))
+	if err != nil {
+		return
+	}
+	for i := range onSale {
+		isSoldOut, err := service.checkSoldOut(ctx, &onSale[i])
+		if err != nil {
+			return amount, err
+		}
+		if isSoldOut {
+			amount++
+		}
+	}
+
+	ended, err := service.creation.FindAllByFilter(ctx, repositories.SelectorOfCreation(repositories.CreationSelector{
+		SaleStatus:    []repositories.SaleStatus{repositories.SaleStatusWaitForSale, repositories.SaleStatusOnSale},
+		SaleEndBefore: &now,
+	}))
+	if err != nil {
+		return
+	}
+	for i := range ended {
+		isChanged, err := service.changeSaleStatus(ctx, &ended[i], repositories.SaleStatusEnded)
+		if err != nil {
+			return amount, err
+		}
+		if isChanged {
+			amount++
+		}
+	}
+	return
+}
+
+func (service *creationService) checkSoldOut(
+	ctx context.Context, creation *repositories.Creation,
+) (isSoldOut bool, err error) {
+	if creation.SaleStatus != repositories.SaleStatusOnSale {
+		return creation.SaleStatus == repositories.SaleStatusSoldOut, nil
+	}
+	minted, err := service.item.CountByCreation(ctx, creation.ID)
+	if err != nil {
+		return
+	}
+	if minted < int64(creation.Amount) {
+		return false, nil
+	}
+	return service.changeSaleStatus(ctx, creation, repositories.SaleStatusSoldOut)
+}
+
+// changeSaleStatus moves the creation to the given status if the transition is allowed
+// and nobody changed it in between, then announces the change.
+func (service *creationService) changeSaleStatus(
+	ctx context.Context, creation *repositories.Creation, to repositories.SaleStatus,
+) (isChanged bool, err error) {
+	from := creation.SaleStatus
+	if !canChangeSaleStatus(from, to) {
+		return false, NewCreationServiceError(SaleStatusInvalid)
+	}
+	isChanged, err = service.creation.UpdateSaleStatus(ctx, creation.ID, from, to)
+	if err != nil || !isChanged {
+		return
+	}
+	creation.SaleStatus = to
+	err = service.creationPublisher.PublishToSaleStatusChanged(ctx, messages.CreationSaleStatusMessage{
+		CreationId: creation.ID.Hex(),
+		BrandId:    creation.BrandID,
+		From:       string(from),
+		To:         string(to),
+		ChangeAt:   time.Now(),
+	})
+	if err != nil {
+		return
+	}
+	return
+}
+
+// saleStatusTransition lists the statuses a creation may move to from each status.
+// Sold out, ended and cancelled creations are final.
+var saleStatusTransition = map[repositories.SaleStatus][]repositories.SaleStatus{
+	repositories.SaleStatusWaitForSale: {
+		repositories.SaleStatusOnSale, repositories.SaleStatusEnded, repositories.SaleStatusCancelled,
+	},
+	repositories.SaleStatusOnSale: {
+		repositories.SaleStatusSoldOut, repositories.SaleStatusEnded, repositories.SaleStatusCancelled,
+	},
+}
+
+func canChangeSaleStatus(from, to repositories.SaleStatus) bool {
+	for _, status := range saleStatusTransition[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func isOnSale(creation *repositories.Creation, at time.Time) bool {
+	if at.Before(creation.SaleStartAt) || at.After(creation.SaleEndAt) {
+		return false
+	}
+	return creation.SaleStatus == repositories.SaleStatusOnSale
+}
+
+// maxSuggestion caps how many names AutocompleteCreation returns.
+const maxSuggestion = 20
+
+// SearchCreation runs a text search narrowed by the usual creation filter and returns
+// the facet counts of all the matches together with the requested page.
+func (service *creationService) SearchCreation(
+	ctx context.Context, dto CreationSearchFilterDto, pageable utils.Pageable,
+) (searchDto *CreationSearchDto, err error) {
+	selector, err := selectorOfCreationFilter(dto.CreationFilterDto)
+	if err != nil {
+		return
+	}
+	search, err := service.creation.Search(ctx, strings.TrimSpace(dto.Text), repositories.SelectorOfCreation(selector), pageable)
+	if err != nil {
+		return
+	}
+	searchDto = &CreationSearchDto{
+		Total:     search.Total,
+		Creations: []CreationDto{},
+		Facets: CreationFacetsDto{
+			Properties: []FacetCountDto{},
+			Brands:     []FacetCountDto{},
+			SaleStatus: []FacetCountDto{},
+			PriceBands: []PriceBandDto{},
+		},
+	}
+	if err = copier.Copy(&searchDto.Creations, &search.Content); err != nil {
+		return nil, err
+	}
+	if err = copier.Copy(&searchDto.Facets.Properties, &search.Properties); err != nil {
+		return nil, err
+	}
+	if err = copier.Copy(&searchDto.Facets.Brands, &search.Brands); err != nil {
+		return nil, err
+	}
+	if err = copier.Copy(&searchDto.Facets.SaleStatus, &search.SaleStatus); err != nil {
+		return nil, err
+	}
+	counts := map[int]int{}
+	for _, band := range search.PriceBands {
+		counts[band.Min] = band.Count
+	}
+	bands := repositories.CreationPriceBands
+	for i, min := range bands {
+		band := PriceBandDto{Min: min, Count: counts[min]}
+		if i+1 < len(bands) {
+			max := bands[i+1] - 1
+			band.Max = &max
+		}
+		searchDto.Facets.PriceBands = append(searchDto.Facets.PriceBands, band)
+	}
+	return
+}
+
+func (service *creationService) AutocompleteCreation(
+	ctx context.Context, prefix string, limit int,
+) (suggestionsDto []CreationSuggestionDto, err error) {
+	suggestionsDto = []CreationSuggestionDto{}
+	prefix = strings.TrimSpace(prefix)
+	if len(prefix) == 0 {
+		return
+	}
+	if limit <= 0 || limit > maxSuggestion {
+		limit = maxSuggestion
+	}
+	suggestions, err := service.creation.Autocomplete(ctx, prefix, limit)
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&suggestionsDto, &suggestions); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func selectorOfCreationFilter(dto CreationFilterDto) (selector repositories.CreationSelector, err error) {
+	var creationIds []primitive.ObjectID
+	for _, id := range dto.CreationIDs {
+		creationId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return selector, err
+		}
+		creationIds = append(creationIds, creationId)
+	}
+	return repositories.CreationSelector{
+		CreationIDs:     creationIds,
+		CreationName:    dto.CreationName,
+		Creator:         dto.Creator,
+		Properties:      dto.Properties,
+		SaleStartAfter:  dto.SaleEndAfter,
+		SaleStartBefore: dto.SaleStartBefore,
+		MaxPrice:        dto.MaxPrice,
+		MinPrice:        dto.MinPrice,
+		BrandID:         dto.BrandID,
+		SaleStatus:      saleStatusOf(dto.SaleStatus),
+		Conditions:      dto.Conditions,
+	}, nil
+}
+
+func saleStatusOf(status []string) (saleStatus []repositories.SaleStatus) {
+	for _, s := range status {
+		saleStatus = append(saleStatus, repositories.SaleStatus(s))
+	}
 	return
 }
 
@@ -266,10 +698,12 @@ type CreationDto struct {
 	CreationID      string    `json:"creationId"`
 	CreationName    string    `json:"creationName"`
 	Amount          int       `json:"amount"`
+	Reserved        int       `json:"reserved"`
 	SmallImageURL   string    `json:"smallImageUrl"`
 	Creator         string    `json:"creator"`
 	Properties      []string  `json:"properties"`
 	Price           int       `json:"price"`
+	RoyaltyBps      int       `json:"royaltyBps"`
 	BrandID         string    `json:"brandId"`
 	SaleWay         string    `json:"saleWay"`
 	SaleStatus      string    `json:"saleStatus"`
@@ -283,16 +717,62 @@ func (dto *CreationDto) ID(id primitive.ObjectID) {
 	dto.CreationID = id.Hex()
 }
 
+var CreationSortFields = repositories.CreationSortFields
+
+var CreationFilterFields = repositories.CreationFilterFields
+
 type CreationFilterDto struct {
-	CreationIDs     []string   `json:"creationIDs"`
-	CreationName    *string    `json:"creationName"`
-	Properties      []string   `json:"properties"`
-	Creator         *string    `json:"creator"`
-	BrandID         *string    `json:"brandId"`
-	SaleStartBefore *time.Time `json:"saleStartBefore"`
-	SaleEndAfter    *time.Time `json:"saleEndAfter"`
-	MaxPrice        *int       `json:"maxPrice"`
-	MinPrice        *int       `json:"minPrice"`
+	CreationIDs     []string          `json:"creationIDs"`
+	CreationName    *string           `json:"creationName"`
+	Properties      []string          `json:"properties"`
+	Creator         *string           `json:"creator"`
+	BrandID         *string           `json:"brandId"`
+	SaleStartBefore *time.Time        `json:"saleStartBefore"`
+	SaleEndAfter    *time.Time        `json:"saleEndAfter"`
+	MaxPrice        *int              `json:"maxPrice"`
+	MinPrice        *int              `json:"minPrice"`
+	SaleStatus      []string          `json:"saleStatus"`
+	Conditions      []utils.Condition `json:"conditions"`
+}
+
+type CreationSearchFilterDto struct {
+	CreationFilterDto
+	Text string `json:"text"`
+}
+
+type CreationSearchDto struct {
+	Total     int64             `json:"total"`
+	Creations []CreationDto     `json:"creations"`
+	Facets    CreationFacetsDto `json:"facets"`
+}
+
+type CreationFacetsDto struct {
+	Properties []FacetCountDto `json:"properties"`
+	Brands     []FacetCountDto `json:"brands"`
+	SaleStatus []FacetCountDto `json:"saleStatus"`
+	PriceBands []PriceBandDto  `json:"priceBands"`
+}
+
+type FacetCountDto struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PriceBandDto counts the creations priced from Min to Max, Max is nil for the last band.
+type PriceBandDto struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max"`
+	Count int  `json:"count"`
+}
+
+type CreationSuggestionDto struct {
+	CreationID   string `json:"creationId"`
+	CreationName string `json:"creationName"`
+	Creator      string `json:"creator"`
+}
+
+func (dto *CreationSuggestionDto) ID(id primitive.ObjectID) {
+	dto.CreationID = id.Hex()
 }
 
 type PostCreationDto struct {
@@ -301,9 +781,12 @@ type PostCreationDto struct {
 	SmallImageURL   string    `json:"smallImageUrl"`
 	Amount          int       `json:"amount"`
 	Price           int       `bson:"price" json:"price"`
+	RoyaltyBps      int       `json:"royaltyBps"`
 	Properties      []string  `json:"properties"`
 	BrandID         string    `json:"brandId"`
 	SaleWay         string    `bson:"sale_way" json:"saleWay"`
+	ReservePrice    int       `json:"reservePrice"`
+	MinIncrement    int       `json:"minIncrement"`
 	SaleStatus      string    `bson:"sale_status" json:"saleStatus"`
 	SaleStartAt     time.Time `bson:"sale_start_at" json:"saleStartAt"`
 	SaleEndAt       time.Time `bson:"sale_end_at" json:"saleEndAt"`
@@ -317,6 +800,12 @@ type UpdateCreationDto struct {
 	SaleEndAt    time.Time `json:"saleEndAt"`
 	CreationName string    `json:"creationName"`
 	Description  string    `json:"description"`
+	RoyaltyBps   *int      `json:"royaltyBps"`
+}
+
+type UpdateSaleStatusDto struct {
+	CreationID string `json:"creationId"`
+	SaleStatus string `json:"saleStatus"`
 }
 
 type CreationServiceError struct {
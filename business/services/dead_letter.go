@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/publishers"
+	"nftshopping-store-api/event/relays"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type DeadLetterService interface {
+	FindDeadLetter(ctx context.Context, deadLetterId string) (deadLetterDto *DeadLetterDto, err error)
+	FindAllDeadLetterByFilter(ctx context.Context, dto DeadLetterFilterDto) (deadLettersDto []DeadLetterDto, err error)
+	FindAllDeadLetterByFilterAndPage(ctx context.Context, dto DeadLetterFilterDto, pageable utils.Pageable) (deadLettersDto []DeadLetterDto, err error)
+	ReplayDeadLetter(ctx context.Context, dto SettleDeadLetterDto) (deadLetterDto *DeadLetterDto, err error)
+	DiscardDeadLetter(ctx context.Context, dto SettleDeadLetterDto) (deadLetterDto *DeadLetterDto, err error)
+}
+
+type deadLetterService struct {
+	deadLetter          repositories.DeadLetterDao
+	deadLetterPublisher publishers.DeadLetterPublisher
+}
+
+func NewDeadLetterService() (service DeadLetterService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
+	return &deadLetterService{
+		deadLetter:          dao.DeadLetter,
+		deadLetterPublisher: publisher.DeadLetter,
+	}, nil
+}
+
+func (service *deadLetterService) FindDeadLetter(
+	ctx context.Context, deadLetterId string,
+) (deadLetterDto *DeadLetterDto, err error) {
+	id, err := primitive.ObjectIDFromHex(deadLetterId)
+	if err != nil {
+		return nil, NewDeadLetterServiceError(DeadLetterNotFound)
+	}
+	deadLetter, err := service.deadLetter.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if deadLetter == nil {
+		return nil, NewDeadLetterServiceError(DeadLetterNotFound)
+	}
+	deadLetterDto = &DeadLetterDto{}
+	if err = copier.Copy(deadLetterDto, deadLetter); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *deadLetterService) FindAllDeadLetterByFilter(
+	ctx context.Context, dto DeadLetterFilterDto,
+) (deadLettersDto []DeadLetterDto, err error) {
+	deadLetters, err := service.deadLetter.FindAllByFilter(ctx, repositories.SelectorOfDeadLetter(selectorOfDeadLetterFilter(dto)))
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&deadLettersDto, &deadLetters); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *deadLetterService) FindAllDeadLetterByFilterAndPage(
+	ctx context.Context, dto DeadLetterFilterDto, pageable utils.Pageable,
+) (deadLettersDto []DeadLetterDto, err error) {
+	page, err := service.deadLetter.FindAllByFilterAndPage(
+		ctx, repositories.SelectorOfDeadLetter(selectorOfDeadLetterFilter(dto)), pageable,
+	)
+	if err != nil {
+		return
+	}
+	deadLetters, ok := page.Content.([]repositories.DeadLetter)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	if err = copier.Copy(&deadLettersDto, &deadLetters); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ReplayDeadLetter sends a pending dead letter back to the topic it failed on,
+// it goes through the outbox so ctx should carry the transaction.
+func (service *deadLetterService) ReplayDeadLetter(
+	ctx context.Context, dto SettleDeadLetterDto,
+) (deadLetterDto *DeadLetterDto, err error) {
+	deadLetter, err := service.settle(ctx, dto.DeadLetterId, repositories.DeadLetterReplayed)
+	if err != nil {
+		return
+	}
+	err = service.deadLetterPublisher.Replay(
+		ctx,
+		deadLetter.ID.Hex(),
+		deadLetter.Topic,
+		deadLetter.Metadata[relays.MetadataAggregateType],
+		deadLetter.Metadata[relays.MetadataAggregateID],
+		deadLetter.Payload,
+	)
+	if err != nil {
+		return
+	}
+	deadLetterDto = &DeadLetterDto{}
+	if err = copier.Copy(deadLetterDto, deadLetter); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *deadLetterService) DiscardDeadLetter(
+	ctx context.Context, dto SettleDeadLetterDto,
+) (deadLetterDto *DeadLetterDto, err error) {
+	deadLetter, err := service.settle(ctx, dto.DeadLetterId, repositories.DeadLetterDiscarded)
+	if err != nil {
+		return
+	}
+	deadLetterDto = &DeadLetterDto{}
+	if err = copier.Copy(deadLetterDto, deadLetter); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// settle moves a pending dead letter to status, a dead letter is settled only once.
+func (service *deadLetterService) settle(
+	ctx context.Context, deadLetterId string, status repositories.DeadLetterStatus,
+) (deadLetter *repositories.DeadLetter, err error) {
+	id, err := primitive.ObjectIDFromHex(deadLetterId)
+	if err != nil {
+		return nil, NewDeadLetterServiceError(DeadLetterNotFound)
+	}
+	deadLetter, err = service.deadLetter.UpdateStatus(
+		ctx, id, []repositories.DeadLetterStatus{repositories.DeadLetterPending}, status,
+	)
+	if err != nil || deadLetter != nil {
+		return
+	}
+	deadLetter, err = service.deadLetter.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if deadLetter == nil {
+		return nil, NewDeadLetterServiceError(DeadLetterNotFound)
+	}
+	return nil, NewDeadLetterServiceError(DeadLetterSettled)
+}
+
+func selectorOfDeadLetterFilter(dto DeadLetterFilterDto) (selector repositories.DeadLetterSelector) {
+	selector = repositories.DeadLetterSelector{
+		Topic:      dto.Topic,
+		Handler:    dto.Handler,
+		Conditions: dto.Conditions,
+	}
+	for _, status := range dto.Status {
+		selector.Status = append(selector.Status, repositories.DeadLetterStatus(status))
+	}
+	return
+}
+
+type DeadLetterDto struct {
+	DeadLetterID string            `json:"deadLetterId"`
+	UUID         string            `json:"uuid"`
+	Topic        string            `json:"topic"`
+	Handler      string            `json:"handler"`
+	Subscriber   string            `json:"subscriber"`
+	Body         string            `json:"body"`
+	Metadata     map[string]string `json:"metadata"`
+	Error        string            `json:"error"`
+	Attempts     int               `json:"attempts"`
+	Status       string            `json:"status"`
+	CreateAt     time.Time         `json:"createAt"`
+	UpdateAt     time.Time         `json:"updateAt"`
+}
+
+func (dto *DeadLetterDto) ID(id primitive.ObjectID) {
+	dto.DeadLetterID = id.Hex()
+}
+
+// Payload keeps the payload as text, the one failed to decode may not be valid json.
+func (dto *DeadLetterDto) Payload(payload []byte) {
+	dto.Body = string(payload)
+}
+
+var DeadLetterSortFields = repositories.DeadLetterSortFields
+
+var DeadLetterFilterFields = repositories.DeadLetterFilterFields
+
+type DeadLetterFilterDto struct {
+	Topic      *string           `json:"topic"`
+	Handler    *string           `json:"handler"`
+	Status     []string          `json:"status"`
+	Conditions []utils.Condition `json:"conditions"`
+}
+
+type SettleDeadLetterDto struct {
+	DeadLetterId string `json:"deadLetterId"`
+}
+
+type DeadLetterServiceError struct {
+	ServiceError
+}
+
+func NewDeadLetterServiceError(e ServiceEvent) error {
+	return &DeadLetterServiceError{ServiceError{ServiceName: "DeadLetterService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
@@ -31,16 +31,64 @@ type Event struct {
 }
 
 const (
-	UserNotFound           ServiceEvent = 201
-	UserRegistered         ServiceEvent = 202
-	UserNameBeenRegistered ServiceEvent = 203
-	PasswordWrong          ServiceEvent = 204
-	CreationNotFound       ServiceEvent = 301
-	BrandNotFound          ServiceEvent = 401
-	BrandHaveCreation      ServiceEvent = 402
-	StockExisted           ServiceEvent = 501
-	StockNotFound          ServiceEvent = 502
-	ContractDuplicate      ServiceEvent = 601
+	UserNotFound            ServiceEvent = 201
+	UserRegistered          ServiceEvent = 202
+	UserNameBeenRegistered  ServiceEvent = 203
+	PasswordWrong           ServiceEvent = 204
+	UserUnauthenticated     ServiceEvent = 205
+	CreationNotFound        ServiceEvent = 301
+	CreationNotOnSale       ServiceEvent = 302
+	CreationSoldOut         ServiceEvent = 303
+	SaleStatusInvalid       ServiceEvent = 304
+	CreationOnAuction       ServiceEvent = 305
+	BrandNotFound           ServiceEvent = 401
+	BrandHaveCreation       ServiceEvent = 402
+	BrandPermissionDenied   ServiceEvent = 403
+	BrandMemberNotFound     ServiceEvent = 404
+	BrandMemberDuplicate    ServiceEvent = 405
+	BrandOwnerRequired      ServiceEvent = 406
+	BrandRoleInvalid        ServiceEvent = 407
+	StockExisted            ServiceEvent = 501
+	StockNotFound           ServiceEvent = 502
+	ContractDuplicate       ServiceEvent = 601
+	TradeAmountInvalid      ServiceEvent = 701
+	CollectionNotEnough     ServiceEvent = 702
+	ItemTransferFailed      ServiceEvent = 703
+	TradePriceInvalid       ServiceEvent = 704
+	ItemNotOwned            ServiceEvent = 705
+	SelfTrade               ServiceEvent = 706
+	RoyaltyInvalid          ServiceEvent = 707
+	OrderNotFound           ServiceEvent = 801
+	OrderAmountInvalid      ServiceEvent = 802
+	OrderNotPending         ServiceEvent = 803
+	AuctionNotFound         ServiceEvent = 901
+	AuctionClosed           ServiceEvent = 902
+	BidTooLow               ServiceEvent = 903
+	AuctionRuleInvalid      ServiceEvent = 904
+	ListingNotFound         ServiceEvent = 1001
+	ListingNotActive        ServiceEvent = 1002
+	OfferNotFound           ServiceEvent = 1003
+	OfferNotPending         ServiceEvent = 1004
+	ItemListed              ServiceEvent = 1005
+	OfferExpireInvalid      ServiceEvent = 1006
+	NonceInvalid            ServiceEvent = 1101
+	SignatureInvalid        ServiceEvent = 1102
+	TokenInvalid            ServiceEvent = 1103
+	ServiceAccountNotFound  ServiceEvent = 1201
+	ServiceAccountDuplicate ServiceEvent = 1202
+	ApiKeyNotFound          ServiceEvent = 1203
+	ApiKeyRevoked           ServiceEvent = 1204
+	ScopeInvalid            ServiceEvent = 1205
+	PolicyNotFound          ServiceEvent = 1301
+	PolicyExisted           ServiceEvent = 1302
+	RoleAssignmentNotFound  ServiceEvent = 1303
+	RoleAssignmentExisted   ServiceEvent = 1304
+	PolicyInvalid           ServiceEvent = 1305
+	StatsGroupInvalid       ServiceEvent = 1401
+	StatsBucketInvalid      ServiceEvent = 1402
+	HolderFilterRequired    ServiceEvent = 1501
+	DeadLetterNotFound      ServiceEvent = 1601
+	DeadLetterSettled       ServiceEvent = 1602
 )
 
 func (e ServiceEvent) GetEvent() *Event {
@@ -53,18 +101,114 @@ func (e ServiceEvent) GetEvent() *Event {
 		return &Event{int(e), "user name have been registered"}
 	case PasswordWrong:
 		return &Event{int(e), "password is wrong"}
+	case UserUnauthenticated:
+		return &Event{int(e), "user is not authenticated"}
 	case CreationNotFound:
 		return &Event{int(e), "creation not found"}
+	case CreationNotOnSale:
+		return &Event{int(e), "creation is not on sale"}
+	case CreationSoldOut:
+		return &Event{int(e), "creation is sold out"}
+	case SaleStatusInvalid:
+		return &Event{int(e), "sale status can not be changed"}
+	case CreationOnAuction:
+		return &Event{int(e), "creation is sold by auction"}
 	case BrandNotFound:
 		return &Event{int(e), "brand not found"}
 	case BrandHaveCreation:
 		return &Event{int(e), "brand have creation"}
+	case BrandPermissionDenied:
+		return &Event{int(e), "permission denied on brand"}
+	case BrandMemberNotFound:
+		return &Event{int(e), "brand member not found"}
+	case BrandMemberDuplicate:
+		return &Event{int(e), "brand member is duplicate"}
+	case BrandOwnerRequired:
+		return &Event{int(e), "brand must have an owner"}
+	case BrandRoleInvalid:
+		return &Event{int(e), "brand role is invalid"}
 	case StockExisted:
 		return &Event{int(e), "stock has existed"}
 	case StockNotFound:
 		return &Event{int(e), "stock not found"}
 	case ContractDuplicate:
 		return &Event{int(e), "contract is duplicate"}
+	case TradeAmountInvalid:
+		return &Event{int(e), "trade amount is invalid"}
+	case CollectionNotEnough:
+		return &Event{int(e), "collection is not enough"}
+	case ItemTransferFailed:
+		return &Event{int(e), "fail to transfer item"}
+	case TradePriceInvalid:
+		return &Event{int(e), "trade price is invalid"}
+	case ItemNotOwned:
+		return &Event{int(e), "item is not owned by seller"}
+	case SelfTrade:
+		return &Event{int(e), "buyer and seller are the same"}
+	case RoyaltyInvalid:
+		return &Event{int(e), "royalty is invalid"}
+	case OrderNotFound:
+		return &Event{int(e), "order not found"}
+	case OrderAmountInvalid:
+		return &Event{int(e), "order amount is invalid"}
+	case OrderNotPending:
+		return &Event{int(e), "order is not in progress"}
+	case AuctionNotFound:
+		return &Event{int(e), "auction not found"}
+	case AuctionClosed:
+		return &Event{int(e), "auction is closed"}
+	case BidTooLow:
+		return &Event{int(e), "bid price is too low"}
+	case AuctionRuleInvalid:
+		return &Event{int(e), "auction min increment must be positive and reserve price not negative"}
+	case ListingNotFound:
+		return &Event{int(e), "listing not found"}
+	case ListingNotActive:
+		return &Event{int(e), "listing is not active"}
+	case OfferNotFound:
+		return &Event{int(e), "offer not found"}
+	case OfferNotPending:
+		return &Event{int(e), "offer is not pending"}
+	case ItemListed:
+		return &Event{int(e), "item has been listed"}
+	case OfferExpireInvalid:
+		return &Event{int(e), "offer expire time is invalid"}
+	case NonceInvalid:
+		return &Event{int(e), "nonce is invalid or expired"}
+	case SignatureInvalid:
+		return &Event{int(e), "signature is invalid"}
+	case TokenInvalid:
+		return &Event{int(e), "token is invalid or revoked"}
+	case ServiceAccountNotFound:
+		return &Event{int(e), "service account not found"}
+	case ServiceAccountDuplicate:
+		return &Event{int(e), "service account is duplicate"}
+	case ApiKeyNotFound:
+		return &Event{int(e), "api key not found"}
+	case ApiKeyRevoked:
+		return &Event{int(e), "api key is revoked or expired"}
+	case ScopeInvalid:
+		return &Event{int(e), "scope is not a known role"}
+	case PolicyNotFound:
+		return &Event{int(e), "policy not found"}
+	case PolicyExisted:
+		return &Event{int(e), "policy has existed"}
+	case RoleAssignmentNotFound:
+		return &Event{int(e), "role assignment not found"}
+	case RoleAssignmentExisted:
+		return &Event{int(e), "role assignment has existed"}
+	case PolicyInvalid:
+		return &Event{int(e), "policy is invalid"}
+	case StatsGroupInvalid:
+		return &Event{int(e), "stats group is invalid"}
+	case StatsBucketInvalid:
+		return &Event{int(e), "stats bucket is invalid"}
+	case HolderFilterRequired:
+		return &Event{int(e), "creation or brand is required"}
+	case DeadLetterNotFound:
+		return &Event{int(e), "dead letter not found"}
+	case DeadLetterSettled:
+		return &Event{int(e), "dead letter has been replayed or discarded"}
 	default:
 		return &Event{int(e), "unknown"}
 	}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/security"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+// maxBps is 100% in basis points.
+const maxBps = 10000
+
+type FeeService interface {
+	SplitTrade(ctx context.Context, transaction *repositories.Transaction) (err error)
+	FindEarningStatement(ctx context.Context, dto EarningFilterDto) (statementDto *EarningStatementDto, err error)
+	FindAllEarningByFilter(ctx context.Context, dto EarningFilterDto) (earningsDto []EarningDto, err error)
+	FindAllEarningByFilterAndPage(ctx context.Context, dto EarningFilterDto, pageable utils.Pageable) (earningsDto []EarningDto, err error)
+}
+
+type feeService struct {
+	creation        CreationService
+	brand           BrandService
+	user            UserService
+	earning         repositories.EarningDao
+	platformBps     int
+	platformAccount string
+}
+
+func NewFeeService(creation CreationService, brand BrandService, user UserService) (service FeeService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &feeService{
+		creation:        creation,
+		brand:           brand,
+		user:            user,
+		earning:         dao.Earning,
+		platformBps:     c.Fee.PlatformBps,
+		platformAccount: c.Fee.PlatformAccount,
+	}, nil
+}
+
+// SplitTrade fills in the fee breakdown of a trade about to be saved and books every
+// payee's share. Royalties are only owed on resales; when the brand itself is the
+// seller it keeps everything but the platform fee.
+func (service *feeService) SplitTrade(ctx context.Context, transaction *repositories.Transaction) (err error) {
+	creation, err := service.creation.FindCreationByID(ctx, transaction.CreationID)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return NewFeeServiceError(CreationNotFound)
+	}
+	fee := repositories.Fee{
+		Creator:     creation.Creator,
+		PlatformFee: bpsOf(transaction.Price, service.platformBps),
+	}
+	if transaction.Seller != transaction.BrandID {
+		brand, err := service.brand.FindBrandById(ctx, transaction.BrandID)
+		if err != nil {
+			return err
+		}
+		if brand == nil {
+			return NewFeeServiceError(BrandNotFound)
+		}
+		if service.platformBps+creation.RoyaltyBps+brand.RoyaltyBps > maxBps {
+			return NewFeeServiceError(RoyaltyInvalid)
+		}
+		fee.CreatorRoyalty = bpsOf(transaction.Price, creation.RoyaltyBps)
+		fee.BrandRoyalty = bpsOf(transaction.Price, brand.RoyaltyBps)
+	}
+	fee.SellerProceeds = transaction.Price - fee.PlatformFee - fee.CreatorRoyalty - fee.BrandRoyalty
+	transaction.Fee = fee
+
+	var earnings []repositories.Earning
+	shares := []struct {
+		payee     string
+		payeeType repositories.PayeeType
+		amount    int
+	}{
+		{transaction.Seller, repositories.PayeeSeller, fee.SellerProceeds},
+		{fee.Creator, repositories.PayeeCreator, fee.CreatorRoyalty},
+		{transaction.BrandID, repositories.PayeeBrand, fee.BrandRoyalty},
+		{service.platformAccount, repositories.PayeePlatform, fee.PlatformFee},
+	}
+	for _, share := range shares {
+		if share.amount <= 0 {
+			continue
+		}
+		earnings = append(earnings, repositories.Earning{
+			ID:            primitive.NewObjectID(),
+			Payee:         share.payee,
+			PayeeType:     share.payeeType,
+			TransactionID: transaction.ID,
+			CreationID:    transaction.CreationID,
+			Amount:        share.amount,
+			EarnAt:        transaction.TradeAt,
+		})
+	}
+	err = service.earning.CreateMany(ctx, earnings)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (service *feeService) FindEarningStatement(
+	ctx context.Context, dto EarningFilterDto,
+) (statementDto *EarningStatementDto, err error) {
+	dto, err = service.scopeEarningFilter(ctx, dto)
+	if err != nil {
+		return
+	}
+	sums, err := service.earning.SumByPayeeType(ctx, repositories.SelectorOfEarning(selectorOfEarningFilter(dto)))
+	if err != nil {
+		return
+	}
+	statementDto = &EarningStatementDto{
+		Payee:      dto.Payee,
+		EarnAfter:  dto.EarnAfter,
+		EarnBefore: dto.EarnBefore,
+	}
+	if err = copier.Copy(&statementDto.Summary, &sums); err != nil {
+		return nil, err
+	}
+	for _, sum := range sums {
+		statementDto.Total += sum.Amount
+		statementDto.Count += sum.Count
+	}
+	return
+}
+
+func (service *feeService) FindAllEarningByFilter(
+	ctx context.Context, dto EarningFilterDto,
+) (earningsDto []EarningDto, err error) {
+	dto, err = service.scopeEarningFilter(ctx, dto)
+	if err != nil {
+		return
+	}
+	earnings, err := service.earning.FindAllByFilter(ctx, repositories.SelectorOfEarning(selectorOfEarningFilter(dto)))
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&earningsDto, &earnings); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *feeService) FindAllEarningByFilterAndPage(
+	ctx context.Context, dto EarningFilterDto, pageable utils.Pageable,
+) (earningsDto []EarningDto, err error) {
+	dto, err = service.scopeEarningFilter(ctx, dto)
+	if err != nil {
+		return
+	}
+	page, err := service.earning.FindAllByFilterAndPage(
+		ctx, repositories.SelectorOfEarning(selectorOfEarningFilter(dto)), pageable,
+	)
+	if err != nil {
+		return
+	}
+	earnings, ok := page.Content.([]repositories.Earning)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	if err = copier.Copy(&earningsDto, &earnings); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// scopeEarningFilter keeps the caller to their own earnings, or to the ones of a brand
+// they are a member of. Platform admins read everybody's.
+func (service *feeService) scopeEarningFilter(ctx context.Context, dto EarningFilterDto) (EarningFilterDto, error) {
+	auth, ok := security.AuthenticationFrom(ctx)
+	if !ok {
+		return dto, NewUserServiceError(UserUnauthenticated)
+	}
+	if security.HasAuthority(auth, security.RoleAdmin) {
+		return dto, nil
+	}
+	if dto.Payee != nil && *dto.Payee == auth.GetName() {
+		return dto, nil
+	}
+	userId, err := actorOf(ctx, service.user)
+	if err != nil {
+		return dto, err
+	}
+	if dto.Payee == nil || *dto.Payee == userId {
+		dto.Payee = &userId
+		return dto, nil
+	}
+	if err = service.brand.CheckPermission(ctx, *dto.Payee); err != nil {
+		return dto, err
+	}
+	dto.PayeeType = []string{string(repositories.PayeeBrand)}
+	return dto, nil
+}
+
+func bpsOf(price, bps int) int {
+	return price * bps / maxBps
+}
+
+func isValidBps(bps int) bool {
+	return bps >= 0 && bps <= maxBps
+}
+
+func selectorOfEarningFilter(dto EarningFilterDto) (selector repositories.EarningSelector) {
+	selector = repositories.EarningSelector{
+		Payee:      dto.Payee,
+		EarnAfter:  dto.EarnAfter,
+		EarnBefore: dto.EarnBefore,
+		Conditions: dto.Conditions,
+	}
+	for _, payeeType := range dto.PayeeType {
+		selector.PayeeType = append(selector.PayeeType, repositories.PayeeType(payeeType))
+	}
+	return
+}
+
+type FeeDto struct {
+	SellerProceeds int    `json:"sellerProceeds"`
+	Creator        string `json:"creator"`
+	CreatorRoyalty int    `json:"creatorRoyalty"`
+	BrandRoyalty   int    `json:"brandRoyalty"`
+	PlatformFee    int    `json:"platformFee"`
+}
+
+type EarningDto struct {
+	EarningID   string    `json:"earningId"`
+	Payee       string    `json:"payee"`
+	PayeeType   string    `json:"payeeType"`
+	Transaction string    `json:"transaction"`
+	CreationID  string    `json:"creationId"`
+	Amount      int       `json:"amount"`
+	EarnAt      time.Time `json:"earnAt"`
+}
+
+func (dto *EarningDto) ID(id primitive.ObjectID) {
+	dto.EarningID = id.Hex()
+}
+
+func (dto *EarningDto) TransactionID(id primitive.ObjectID) {
+	dto.Transaction = id.Hex()
+}
+
+type EarningSumDto struct {
+	PayeeType string `json:"payeeType"`
+	Amount    int    `json:"amount"`
+	Count     int    `json:"count"`
+}
+
+type EarningStatementDto struct {
+	Payee      *string         `json:"payee"`
+	EarnAfter  *time.Time      `json:"earnAfter"`
+	EarnBefore *time.Time      `json:"earnBefore"`
+	Total      int             `json:"total"`
+	Count      int             `json:"count"`
+	Summary    []EarningSumDto `json:"summary"`
+}
+
+var EarningSortFields = repositories.EarningSortFields
+
+var EarningFilterFields = repositories.EarningFilterFields
+
+type EarningFilterDto struct {
+	Payee      *string           `json:"payee"`
+	PayeeType  []string          `json:"payeeType"`
+	EarnAfter  *time.Time        `json:"earnAfter"`
+	EarnBefore *time.Time        `json:"earnBefore"`
+	Conditions []utils.Condition `json:"conditions"`
+}
+
+type FeeServiceError struct {
+	ServiceError
+}
+
+func NewFeeServiceError(e ServiceEvent) error {
+	return &FeeServiceError{ServiceError{ServiceName: "FeeService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
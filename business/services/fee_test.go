@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/persistence/repositories"
+	"testing"
+)
+
+func TestSplitTradeOfDeletedCreation(t *testing.T) {
+	service := &feeService{
+		creation: &creationService{creation: &deletedCreationDao{}},
+	}
+	err := service.SplitTrade(context.Background(), &repositories.Transaction{
+		ID:         primitive.NewObjectID(),
+		CreationID: primitive.NewObjectID().Hex(),
+		Price:      100,
+	})
+	if e, ok := err.(*FeeServiceError); !ok || e.Code != CreationNotFound.GetEvent().Code {
+		t.Fatalf("SplitTrade() error = %v, want %v", err, NewFeeServiceError(CreationNotFound))
+	}
+}
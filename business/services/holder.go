@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/caches"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type HolderService interface {
+	FindAllHolderByFilterAndPage(ctx context.Context, dto HolderFilterDto, pageable utils.Pageable) (holdersDto []HolderDto, err error)
+	FindHolderSummary(ctx context.Context, dto HolderFilterDto) (summaryDto *HolderSummaryDto, err error)
+}
+
+type holderService struct {
+	holder          repositories.HolderDao
+	holderCache     *lru.Cache
+	cacheExpiration time.Duration
+}
+
+func NewHolderService() (service HolderService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	cacheManager, err := caches.GetCacheManager()
+	if err != nil {
+		return
+	}
+	holderCache, err := cacheManager.GetCache("holder")
+	if err != nil {
+		return
+	}
+	return &holderService{
+		holder:          dao.Holder,
+		holderCache:     holderCache,
+		cacheExpiration: c.Holder.CacheExpiration,
+	}, nil
+}
+
+// FindAllHolderByFilterAndPage ranks the holders of a creation or brand, the first holder of page 0 is rank 1.
+func (service *holderService) FindAllHolderByFilterAndPage(
+	ctx context.Context, dto HolderFilterDto, pageable utils.Pageable,
+) (holdersDto []HolderDto, err error) {
+	if cached, ok := service.getFromCache("page", dto, pageable); ok {
+		if holdersDto, ok = cached.([]HolderDto); ok {
+			return
+		}
+	}
+	selector, err := selectorOfHolderFilter(dto)
+	if err != nil {
+		return
+	}
+	page, err := service.holder.FindAllByFilterAndPage(ctx, repositories.SelectorOfHolder(selector), pageable)
+	if err != nil {
+		return
+	}
+	holders, ok := page.Content.([]repositories.Holder)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	holdersDto = []HolderDto{}
+	if err = copier.Copy(&holdersDto, &holders); err != nil {
+		return nil, err
+	}
+	for i := range holdersDto {
+		holdersDto[i].Rank = pageable.Size*pageable.Page + i + 1
+	}
+	service.addToCache(holdersDto, "page", dto, pageable)
+	return
+}
+
+func (service *holderService) FindHolderSummary(
+	ctx context.Context, dto HolderFilterDto,
+) (summaryDto *HolderSummaryDto, err error) {
+	if cached, ok := service.getFromCache("summary", dto); ok {
+		if summaryDto, ok = cached.(*HolderSummaryDto); ok {
+			return
+		}
+	}
+	selector, err := selectorOfHolderFilter(dto)
+	if err != nil {
+		return
+	}
+	summary, err := service.holder.Summarize(ctx, repositories.SelectorOfHolder(selector))
+	if err != nil {
+		return
+	}
+	summaryDto = &HolderSummaryDto{
+		CreationID:   dto.CreationID,
+		BrandID:      dto.BrandID,
+		Holders:      summary.Holders,
+		Amount:       summary.Amount,
+		Distribution: []HolderBucketDto{},
+	}
+	// every bucket is listed, even the empty ones, so charts keep a fixed x axis
+	counts := map[int]repositories.HolderBucket{}
+	for _, bucket := range summary.Distribution {
+		counts[bucket.Min] = bucket
+	}
+	boundaries := repositories.HolderBoundaries
+	for i, min := range boundaries {
+		bucket := HolderBucketDto{
+			Min:     min,
+			Holders: counts[min].Holders,
+			Amount:  counts[min].Amount,
+		}
+		if i+1 < len(boundaries) {
+			max := boundaries[i+1] - 1
+			bucket.Max = &max
+		}
+		summaryDto.Distribution = append(summaryDto.Distribution, bucket)
+	}
+	service.addToCache(summaryDto, "summary", dto)
+	return
+}
+
+type holderCacheEntry struct {
+	value    interface{}
+	expireAt time.Time
+}
+
+func (service *holderService) getFromCache(components ...interface{}) (value interface{}, ok bool) {
+	key, err := generateKeyOfCache(components...)
+	if err != nil {
+		return nil, false
+	}
+	val, ok := service.holderCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry, ok := val.(holderCacheEntry)
+	if !ok || time.Now().After(entry.expireAt) {
+		service.holderCache.Remove(key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (service *holderService) addToCache(value interface{}, components ...interface{}) {
+	key, err := generateKeyOfCache(components...)
+	if err != nil {
+		return
+	}
+	service.holderCache.Add(key, holderCacheEntry{value, time.Now().Add(service.cacheExpiration)})
+}
+
+func selectorOfHolderFilter(dto HolderFilterDto) (selector repositories.HolderSelector, err error) {
+	if dto.CreationID == nil && dto.BrandID == nil {
+		return selector, NewHolderServiceError(HolderFilterRequired)
+	}
+	selector.BrandID = dto.BrandID
+	if dto.CreationID != nil {
+		creationId, err := primitive.ObjectIDFromHex(*dto.CreationID)
+		if err != nil {
+			return selector, NewHolderServiceError(CreationNotFound)
+		}
+		selector.CreationID = &creationId
+	}
+	return
+}
+
+type HolderDto struct {
+	Rank      int    `json:"rank"`
+	Owner     string `json:"owner"`
+	Amount    int    `json:"amount"`
+	Creations int    `json:"creations"`
+}
+
+// HolderBucketDto counts the holders holding from Min to Max items, Max is nil for the last bucket.
+type HolderBucketDto struct {
+	Min     int  `json:"min"`
+	Max     *int `json:"max"`
+	Holders int  `json:"holders"`
+	Amount  int  `json:"amount"`
+}
+
+type HolderSummaryDto struct {
+	CreationID   *string           `json:"creationId"`
+	BrandID      *string           `json:"brandId"`
+	Holders      int               `json:"holders"`
+	Amount       int               `json:"amount"`
+	Distribution []HolderBucketDto `json:"distribution"`
+}
+
+type HolderFilterDto struct {
+	CreationID *string `json:"creationId"`
+	BrandID    *string `json:"brandId"`
+}
+
+type HolderServiceError struct {
+	ServiceError
+}
+
+func NewHolderServiceError(e ServiceEvent) error {
+	return &HolderServiceError{ServiceError{ServiceName: "HolderService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
@@ -13,25 +13,27 @@ import (
 )
 
 type ItemService interface {
-	OrderItem(ctx context.Context, dto OrderItemDto) (err error)
+	OrderItem(ctx context.Context, dto OrderItemDto) (orderDto *OrderDto, err error)
 	DeliverItem(ctx context.Context, dto DeliverItemDto) (itemDto *ItemDto, err error)
 	FindItem(ctx context.Context, contract, token string) (itemDto *ItemDto, err error)
 	GetAmountOfItemByBrand(ctx context.Context, brandId string) (amount int64, err error)
 	FindAllItemByFilter(ctx context.Context, filter ItemFilterDto) (itemDto []ItemDto, err error)
 	FindAllItemByFilterAndPage(ctx context.Context, filter ItemFilterDto, pageable utils.Pageable) (itemDto []ItemDto, err error)
+	FindAllItemByFilterAndCursor(ctx context.Context, filter ItemFilterDto, pageable utils.Pageable) (itemDto []ItemDto, next string, err error)
 }
 
 type itemService struct {
 	creation      CreationService
 	brand         BrandService
 	user          UserService
+	order         OrderService
 	item          repositories.ItemDao
 	factory       items.FactoryService
 	itemPublisher publishers.ItemPublisher
 }
 
 func NewItemService(
-	creation CreationService, brand BrandService, user UserService,
+	creation CreationService, brand BrandService, user UserService, order OrderService,
 ) (service ItemService, err error) {
 	repository, err := repositories.GetRepository()
 	if err != nil {
@@ -49,6 +51,7 @@ func NewItemService(
 		brand:         brand,
 		creation:      creation,
 		user:          user,
+		order:         order,
 		item:          repository.Item,
 		factory:       item.Factory,
 		itemPublisher: publisher.Item,
@@ -91,9 +94,7 @@ func (service *itemService) GetAmountOfItemByBrand(ctx context.Context, brandId
 func (service *itemService) FindAllItemByFilter(
 	ctx context.Context, dto ItemFilterDto,
 ) (itemsDto []ItemDto, err error) {
-	selector := repositories.ItemSelector{
-		Owner: dto.Owner,
-	}
+	selector := selectorOfItemFilter(dto)
 	items, err := service.item.FindAllByFilter(ctx, repositories.SelectorOfItem(selector))
 	if err != nil {
 		return
@@ -107,9 +108,7 @@ func (service *itemService) FindAllItemByFilter(
 func (service *itemService) FindAllItemByFilterAndPage(
 	ctx context.Context, dto ItemFilterDto, pageable utils.Pageable,
 ) (itemsDto []ItemDto, err error) {
-	selector := repositories.ItemSelector{
-		Owner: dto.Owner,
-	}
+	selector := selectorOfItemFilter(dto)
 	page, err := service.item.FindAllByFilterAndPage(ctx, repositories.SelectorOfItem(selector), pageable)
 	if err != nil {
 		return
@@ -124,27 +123,26 @@ func (service *itemService) FindAllItemByFilterAndPage(
 	return
 }
 
-func (service *itemService) OrderItem(ctx context.Context, dto OrderItemDto) (err error) {
-	creation, err := service.creation.FindCreationByID(ctx, dto.CreationId)
+func (service *itemService) FindAllItemByFilterAndCursor(
+	ctx context.Context, dto ItemFilterDto, pageable utils.Pageable,
+) (itemsDto []ItemDto, next string, err error) {
+	selector := selectorOfItemFilter(dto)
+	page, err := service.item.FindAllByFilterAndCursor(ctx, repositories.SelectorOfItem(selector), pageable)
 	if err != nil {
-		return err
+		return
 	}
-	if creation == nil {
-		return NewItemServiceError(CreationNotFound)
-	}
-	//err = service.factory.OrderItem(items.OrderItemRequest{
-	//	ProductId: creation.CreationID,
-	//	Contract:  creation.ContractAddress,
-	//	Amount:    dto.Amount,
-	//})
-	//if err != nil {
-	//	return
-	//}
-	err = service.itemPublisher.PublishToOrderItem(messages.OrderItemMessage{
-		Contract:   creation.ContractAddress,
-		CreationId: creation.CreationID,
-		Amount:     dto.Amount,
-	})
+	items, ok := page.Content.([]repositories.Item)
+	if !ok {
+		return nil, "", utils.ErrCovertContent
+	}
+	if err = copier.Copy(&itemsDto, &items); err != nil {
+		return nil, "", err
+	}
+	return itemsDto, page.NextCursor, nil
+}
+
+func (service *itemService) OrderItem(ctx context.Context, dto OrderItemDto) (orderDto *OrderDto, err error) {
+	orderDto, err = service.order.PlaceOrder(ctx, dto)
 	if err != nil {
 		return
 	}
@@ -172,10 +170,35 @@ func (service *itemService) DeliverItem(
 		}, CreationID: id,
 		BrandOwner: creation.BrandID,
 	}
+	if len(dto.OrderId) > 0 {
+		order, err := service.order.DeliverOrder(ctx, DeliverOrderDto{
+			OrderId:    dto.OrderId,
+			CreationId: dto.CreationId,
+			Contract:   dto.Contract,
+			Token:      dto.Token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		item.Owner = order.Buyer
+	}
 	err = service.item.Create(ctx, item)
 	if err != nil {
 		return
 	}
+	err = service.itemPublisher.PublishToItemDelivered(ctx, messages.ItemDeliveredMessage{
+		OrderId:    dto.OrderId,
+		CreationId: dto.CreationId,
+		Contract:   dto.Contract,
+		Token:      dto.Token,
+		Owner:      item.Owner,
+	})
+	if err != nil {
+		return
+	}
+	if _, err = service.creation.CheckSoldOut(ctx, dto.CreationId); err != nil {
+		return
+	}
 	itemDto = &ItemDto{}
 	if err = copier.Copy(itemDto, item); err != nil {
 		return nil, err
@@ -206,14 +229,27 @@ type OrderItemDto struct {
 }
 
 type DeliverItemDto struct {
+	OrderId    string `json:"orderId"`
 	CreationId string `json:"creationId"`
 	Contract   string `json:"contract"`
 	Token      string `json:"token"`
 }
 
+func selectorOfItemFilter(dto ItemFilterDto) repositories.ItemSelector {
+	return repositories.ItemSelector{
+		Owner:      dto.Owner,
+		Conditions: dto.Conditions,
+	}
+}
+
+var ItemSortFields = repositories.ItemSortFields
+
+var ItemFilterFields = repositories.ItemFilterFields
+
 type ItemFilterDto struct {
-	Owner      *string `json:"owner"`
-	BrandOwner *string `json:"brandOwner"`
+	Owner      *string           `json:"owner"`
+	BrandOwner *string           `json:"brandOwner"`
+	Conditions []utils.Condition `json:"conditions"`
 }
 
 type ItemServiceError struct {
@@ -0,0 +1,585 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type MarketService interface {
+	FindListing(ctx context.Context, listingId string) (listingDto *ListingDto, err error)
+	FindAllListingByFilter(ctx context.Context, dto ListingFilterDto) (listingsDto []ListingDto, err error)
+	FindAllListingByFilterAndPage(ctx context.Context, dto ListingFilterDto, pageable utils.Pageable) (listingsDto []ListingDto, err error)
+	CreateListing(ctx context.Context, dto CreateListingDto) (listingDto *ListingDto, err error)
+	CancelListing(ctx context.Context, dto CancelListingDto) (err error)
+	BuyListing(ctx context.Context, dto BuyListingDto) (txn *TransactionDto, err error)
+	FindOffer(ctx context.Context, offerId string) (offerDto *OfferDto, err error)
+	FindAllOfferByFilter(ctx context.Context, dto OfferFilterDto) (offersDto []OfferDto, err error)
+	FindAllOfferByFilterAndPage(ctx context.Context, dto OfferFilterDto, pageable utils.Pageable) (offersDto []OfferDto, err error)
+	MakeOffer(ctx context.Context, dto MakeOfferDto) (offerDto *OfferDto, err error)
+	AcceptOffer(ctx context.Context, dto ReplyOfferDto) (txn *TransactionDto, err error)
+	RejectOffer(ctx context.Context, dto ReplyOfferDto) (err error)
+	ExpireOffer(ctx context.Context) (amount int64, err error)
+}
+
+type marketService struct {
+	creation     CreationService
+	user         UserService
+	trade        TradeService
+	item         repositories.ItemDao
+	listing      repositories.ListingDao
+	offer        repositories.OfferDao
+	offerTimeout time.Duration
+}
+
+func NewMarketService(creation CreationService, user UserService, trade TradeService) (service MarketService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &marketService{
+		creation:     creation,
+		user:         user,
+		trade:        trade,
+		item:         dao.Item,
+		listing:      dao.Listing,
+		offer:        dao.Offer,
+		offerTimeout: c.Market.OfferTimeout,
+	}, nil
+}
+
+func (service *marketService) FindListing(ctx context.Context, listingId string) (listingDto *ListingDto, err error) {
+	id, err := primitive.ObjectIDFromHex(listingId)
+	if err != nil {
+		return nil, nil
+	}
+	listing, err := service.listing.Find(ctx, id)
+	if err != nil || listing == nil {
+		return
+	}
+	listingDto = &ListingDto{}
+	if err = copier.Copy(listingDto, listing); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) FindAllListingByFilter(
+	ctx context.Context, dto ListingFilterDto,
+) (listingsDto []ListingDto, err error) {
+	selector, err := service.selectorOfListingFilter(ctx, dto)
+	if err != nil {
+		return
+	}
+	listings, err := service.listing.FindAllByFilter(ctx, repositories.SelectorOfListing(selector))
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&listingsDto, &listings); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) FindAllListingByFilterAndPage(
+	ctx context.Context, dto ListingFilterDto, pageable utils.Pageable,
+) (listingsDto []ListingDto, err error) {
+	selector, err := service.selectorOfListingFilter(ctx, dto)
+	if err != nil {
+		return
+	}
+	page, err := service.listing.FindAllByFilterAndPage(ctx, repositories.SelectorOfListing(selector), pageable)
+	if err != nil {
+		return
+	}
+	listings, ok := page.Content.([]repositories.Listing)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	if err = copier.Copy(&listingsDto, &listings); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) CreateListing(
+	ctx context.Context, dto CreateListingDto,
+) (listingDto *ListingDto, err error) {
+	if dto.Price <= 0 {
+		return nil, NewMarketServiceError(TradePriceInvalid)
+	}
+	seller, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	item, err := service.item.Find(ctx, &repositories.ItemID{
+		Contract: dto.Contract,
+		Token:    dto.Token,
+	})
+	if err != nil {
+		return
+	}
+	if item == nil || len(item.Owner) == 0 || item.Owner != seller {
+		return nil, NewMarketServiceError(ItemNotOwned)
+	}
+	now := time.Now()
+	listing := &repositories.Listing{
+		ID:         primitive.NewObjectID(),
+		Item:       item.ID,
+		CreationID: item.CreationID,
+		BrandID:    item.BrandOwner,
+		Seller:     seller,
+		Price:      dto.Price,
+		Status:     repositories.ListingActive,
+		CreateAt:   now,
+		UpdateAt:   now,
+	}
+	err = service.listing.Create(ctx, listing)
+	if err != nil {
+		if err == repositories.ListingDuplicate {
+			return nil, NewMarketServiceError(ItemListed)
+		}
+		return
+	}
+	listingDto = &ListingDto{}
+	if err = copier.Copy(listingDto, listing); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) CancelListing(ctx context.Context, dto CancelListingDto) (err error) {
+	seller, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(dto.ListingId)
+	if err != nil {
+		return NewMarketServiceError(ListingNotFound)
+	}
+	listing, err := service.listing.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if listing == nil || listing.Seller != seller {
+		return NewMarketServiceError(ListingNotFound)
+	}
+	listing, err = service.listing.UpdateStatus(ctx, id, repositories.ListingActive, repositories.ListingCancelled, nil)
+	if err != nil {
+		return
+	}
+	if listing == nil {
+		return NewMarketServiceError(ListingNotActive)
+	}
+	return
+}
+
+// BuyListing settles an active listing into the transaction history.
+// It must run inside a mongo session.
+func (service *marketService) BuyListing(ctx context.Context, dto BuyListingDto) (txn *TransactionDto, err error) {
+	buyer, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(dto.ListingId)
+	if err != nil {
+		return nil, NewMarketServiceError(ListingNotFound)
+	}
+	listing, err := service.listing.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if listing == nil {
+		return nil, NewMarketServiceError(ListingNotFound)
+	}
+	if listing.Status != repositories.ListingActive {
+		return nil, NewMarketServiceError(ListingNotActive)
+	}
+	txn, err = service.trade.TradeInItem(ctx, TradeInItemDto{
+		Contract: listing.Item.Contract,
+		Token:    listing.Item.Token,
+		Buyer:    buyer,
+		Seller:   listing.Seller,
+		Price:    listing.Price,
+	})
+	if err != nil {
+		return
+	}
+	tnxId, err := primitive.ObjectIDFromHex(txn.TransactionID)
+	if err != nil {
+		return
+	}
+	listing, err = service.listing.UpdateStatus(ctx, id, repositories.ListingActive, repositories.ListingSold, &tnxId)
+	if err != nil {
+		return
+	}
+	if listing == nil {
+		return nil, NewMarketServiceError(ListingNotActive)
+	}
+	err = service.offer.RejectByItem(ctx, listing.Item)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (service *marketService) FindOffer(ctx context.Context, offerId string) (offerDto *OfferDto, err error) {
+	id, err := primitive.ObjectIDFromHex(offerId)
+	if err != nil {
+		return nil, nil
+	}
+	offer, err := service.offer.Find(ctx, id)
+	if err != nil || offer == nil {
+		return
+	}
+	offerDto = &OfferDto{}
+	if err = copier.Copy(offerDto, offer); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) FindAllOfferByFilter(
+	ctx context.Context, dto OfferFilterDto,
+) (offersDto []OfferDto, err error) {
+	offers, err := service.offer.FindAllByFilter(ctx, repositories.SelectorOfOffer(selectorOfOfferFilter(dto)))
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&offersDto, &offers); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) FindAllOfferByFilterAndPage(
+	ctx context.Context, dto OfferFilterDto, pageable utils.Pageable,
+) (offersDto []OfferDto, err error) {
+	page, err := service.offer.FindAllByFilterAndPage(
+		ctx, repositories.SelectorOfOffer(selectorOfOfferFilter(dto)), pageable,
+	)
+	if err != nil {
+		return
+	}
+	offers, ok := page.Content.([]repositories.Offer)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	if err = copier.Copy(&offersDto, &offers); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *marketService) MakeOffer(ctx context.Context, dto MakeOfferDto) (offerDto *OfferDto, err error) {
+	if dto.Price <= 0 {
+		return nil, NewMarketServiceError(TradePriceInvalid)
+	}
+	buyer, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	item, err := service.item.Find(ctx, &repositories.ItemID{
+		Contract: dto.Contract,
+		Token:    dto.Token,
+	})
+	if err != nil {
+		return
+	}
+	if item == nil || len(item.Owner) == 0 {
+		return nil, NewMarketServiceError(ItemNotOwned)
+	}
+	if item.Owner == buyer {
+		return nil, NewMarketServiceError(SelfTrade)
+	}
+	now := time.Now()
+	expireAt := now.Add(service.offerTimeout)
+	if dto.ExpireAt != nil {
+		expireAt = *dto.ExpireAt
+	}
+	if !expireAt.After(now) {
+		return nil, NewMarketServiceError(OfferExpireInvalid)
+	}
+	offer := &repositories.Offer{
+		ID:         primitive.NewObjectID(),
+		Item:       item.ID,
+		CreationID: item.CreationID,
+		BrandID:    item.BrandOwner,
+		Buyer:      buyer,
+		Seller:     item.Owner,
+		Price:      dto.Price,
+		Status:     repositories.OfferPending,
+		CreateAt:   now,
+		UpdateAt:   now,
+		ExpireAt:   expireAt,
+	}
+	err = service.offer.Create(ctx, offer)
+	if err != nil {
+		return
+	}
+	offerDto = &OfferDto{}
+	if err = copier.Copy(offerDto, offer); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// AcceptOffer sells the item to the bidder at the offered price. Any listing of the item
+// is cancelled and the other pending offers on it are rejected. It must run inside a mongo session.
+func (service *marketService) AcceptOffer(ctx context.Context, dto ReplyOfferDto) (txn *TransactionDto, err error) {
+	offer, err := service.findPendingOffer(ctx, dto)
+	if err != nil {
+		return
+	}
+	txn, err = service.trade.TradeInItem(ctx, TradeInItemDto{
+		Contract: offer.Item.Contract,
+		Token:    offer.Item.Token,
+		Buyer:    offer.Buyer,
+		Seller:   offer.Seller,
+		Price:    offer.Price,
+	})
+	if err != nil {
+		return
+	}
+	tnxId, err := primitive.ObjectIDFromHex(txn.TransactionID)
+	if err != nil {
+		return
+	}
+	offer, err = service.offer.UpdateStatus(ctx, offer.ID, repositories.OfferPending, repositories.OfferAccepted, &tnxId)
+	if err != nil {
+		return
+	}
+	if offer == nil {
+		return nil, NewMarketServiceError(OfferNotPending)
+	}
+	err = service.listing.CancelByItem(ctx, offer.Item)
+	if err != nil {
+		return
+	}
+	err = service.offer.RejectByItem(ctx, offer.Item)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (service *marketService) RejectOffer(ctx context.Context, dto ReplyOfferDto) (err error) {
+	offer, err := service.findPendingOffer(ctx, dto)
+	if err != nil {
+		return
+	}
+	offer, err = service.offer.UpdateStatus(ctx, offer.ID, repositories.OfferPending, repositories.OfferRejected, nil)
+	if err != nil {
+		return
+	}
+	if offer == nil {
+		return NewMarketServiceError(OfferNotPending)
+	}
+	return
+}
+
+func (service *marketService) ExpireOffer(ctx context.Context) (amount int64, err error) {
+	amount, err = service.offer.Expire(ctx, time.Now())
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (service *marketService) findPendingOffer(
+	ctx context.Context, dto ReplyOfferDto,
+) (offer *repositories.Offer, err error) {
+	seller, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(dto.OfferId)
+	if err != nil {
+		return nil, NewMarketServiceError(OfferNotFound)
+	}
+	offer, err = service.offer.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if offer == nil || offer.Seller != seller {
+		return nil, NewMarketServiceError(OfferNotFound)
+	}
+	if offer.Status != repositories.OfferPending || !time.Now().Before(offer.ExpireAt) {
+		return nil, NewMarketServiceError(OfferNotPending)
+	}
+	return
+}
+
+// selectorOfListingFilter narrows the listings down to the creations matching the
+// creation part of the filter, the same way creations are searched.
+func (service *marketService) selectorOfListingFilter(
+	ctx context.Context, dto ListingFilterDto,
+) (selector repositories.ListingSelector, err error) {
+	selector = repositories.ListingSelector{
+		BrandID:    dto.BrandID,
+		Seller:     dto.Seller,
+		MaxPrice:   dto.MaxPrice,
+		MinPrice:   dto.MinPrice,
+		Status:     []repositories.ListingStatus{repositories.ListingActive},
+		Conditions: dto.Conditions,
+	}
+	if len(dto.Status) > 0 {
+		selector.Status = nil
+		for _, status := range dto.Status {
+			selector.Status = append(selector.Status, repositories.ListingStatus(status))
+		}
+	}
+	if len(dto.CreationIDs) == 0 && dto.CreationName == nil && dto.Creator == nil && len(dto.Properties) == 0 {
+		return
+	}
+	creations, err := service.creation.FindAllCreationByFilter(ctx, CreationFilterDto{
+		CreationIDs:  dto.CreationIDs,
+		CreationName: dto.CreationName,
+		Properties:   dto.Properties,
+		Creator:      dto.Creator,
+		BrandID:      dto.BrandID,
+	})
+	if err != nil {
+		return
+	}
+	selector.CreationIDs = []primitive.ObjectID{}
+	for _, creation := range creations {
+		creationId, err := primitive.ObjectIDFromHex(creation.CreationID)
+		if err != nil {
+			return selector, err
+		}
+		selector.CreationIDs = append(selector.CreationIDs, creationId)
+	}
+	return
+}
+
+func selectorOfOfferFilter(dto OfferFilterDto) (selector repositories.OfferSelector) {
+	selector = repositories.OfferSelector{
+		Buyer:      dto.Buyer,
+		Seller:     dto.Seller,
+		Conditions: dto.Conditions,
+	}
+	if dto.Contract != nil && dto.Token != nil {
+		selector.Item = &repositories.ItemID{
+			Contract: *dto.Contract,
+			Token:    *dto.Token,
+		}
+	}
+	for _, status := range dto.Status {
+		selector.Status = append(selector.Status, repositories.OfferStatus(status))
+	}
+	return
+}
+
+type ListingDto struct {
+	ListingID string    `json:"listingId"`
+	Item      ItemDto   `json:"item"`
+	Creation  string    `json:"creation"`
+	BrandID   string    `json:"brandId"`
+	Seller    string    `json:"seller"`
+	Price     int       `json:"price"`
+	Status    string    `json:"status"`
+	CreateAt  time.Time `json:"createAt"`
+	UpdateAt  time.Time `json:"updateAt"`
+}
+
+func (dto *ListingDto) ID(id primitive.ObjectID) {
+	dto.ListingID = id.Hex()
+}
+
+func (dto *ListingDto) CreationID(id primitive.ObjectID) {
+	dto.Creation = id.Hex()
+}
+
+var ListingSortFields = repositories.ListingSortFields
+
+var ListingFilterFields = repositories.ListingFilterFields
+
+type ListingFilterDto struct {
+	CreationIDs  []string          `json:"creationIDs"`
+	CreationName *string           `json:"creationName"`
+	Properties   []string          `json:"properties"`
+	Creator      *string           `json:"creator"`
+	BrandID      *string           `json:"brandId"`
+	Seller       *string           `json:"seller"`
+	Status       []string          `json:"status"`
+	MaxPrice     *int              `json:"maxPrice"`
+	MinPrice     *int              `json:"minPrice"`
+	Conditions   []utils.Condition `json:"conditions"`
+}
+
+type CreateListingDto struct {
+	Contract string `json:"contract"`
+	Token    string `json:"token"`
+	Price    int    `json:"price"`
+}
+
+type CancelListingDto struct {
+	ListingId string `json:"listingId"`
+}
+
+type BuyListingDto struct {
+	ListingId string `json:"listingId"`
+}
+
+type OfferDto struct {
+	OfferID  string    `json:"offerId"`
+	Item     ItemDto   `json:"item"`
+	Creation string    `json:"creation"`
+	BrandID  string    `json:"brandId"`
+	Buyer    string    `json:"buyer"`
+	Seller   string    `json:"seller"`
+	Price    int       `json:"price"`
+	Status   string    `json:"status"`
+	CreateAt time.Time `json:"createAt"`
+	UpdateAt time.Time `json:"updateAt"`
+	ExpireAt time.Time `json:"expireAt"`
+}
+
+func (dto *OfferDto) ID(id primitive.ObjectID) {
+	dto.OfferID = id.Hex()
+}
+
+func (dto *OfferDto) CreationID(id primitive.ObjectID) {
+	dto.Creation = id.Hex()
+}
+
+var OfferSortFields = repositories.OfferSortFields
+
+var OfferFilterFields = repositories.OfferFilterFields
+
+type OfferFilterDto struct {
+	Contract   *string           `json:"contract"`
+	Token      *string           `json:"token"`
+	Buyer      *string           `json:"buyer"`
+	Seller     *string           `json:"seller"`
+	Status     []string          `json:"status"`
+	Conditions []utils.Condition `json:"conditions"`
+}
+
+type MakeOfferDto struct {
+	Contract string     `json:"contract"`
+	Token    string     `json:"token"`
+	Price    int        `json:"price"`
+	ExpireAt *time.Time `json:"expireAt"`
+}
+
+type ReplyOfferDto struct {
+	OfferId string `json:"offerId"`
+}
+
+type MarketServiceError struct {
+	ServiceError
+}
+
+func NewMarketServiceError(e ServiceEvent) error {
+	return &MarketServiceError{ServiceError{ServiceName: "MarketService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/security"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type OrderService interface {
+	FindOrder(ctx context.Context, orderId string) (orderDto *OrderDto, err error)
+	FindAllOrderByFilter(ctx context.Context, dto OrderFilterDto) (ordersDto []OrderDto, err error)
+	FindAllOrderByFilterAndPage(ctx context.Context, dto OrderFilterDto, pageable utils.Pageable) (ordersDto []OrderDto, err error)
+	PlaceOrder(ctx context.Context, dto OrderItemDto) (orderDto *OrderDto, err error)
+	PlaceAuctionOrder(ctx context.Context, dto AuctionOrderDto) (orderDto *OrderDto, err error)
+	MintOrder(ctx context.Context, orderId string) (err error)
+	DeliverOrder(ctx context.Context, dto DeliverOrderDto) (orderDto *OrderDto, err error)
+	FailOrder(ctx context.Context, dto FailOrderDto) (err error)
+	ExpireOrder(ctx context.Context) (amount int, err error)
+}
+
+type orderService struct {
+	creation      CreationService
+	user          UserService
+	order         repositories.OrderDao
+	itemPublisher publishers.ItemPublisher
+	timeout       time.Duration
+}
+
+func NewOrderService(creation CreationService, user UserService) (service OrderService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &orderService{
+		creation:      creation,
+		user:          user,
+		order:         dao.Order,
+		itemPublisher: publisher.Item,
+		timeout:       c.Order.Timeout,
+	}, nil
+}
+
+func (service *orderService) FindOrder(ctx context.Context, orderId string) (orderDto *OrderDto, err error) {
+	id, err := primitive.ObjectIDFromHex(orderId)
+	if err != nil {
+		return nil, nil
+	}
+	buyer, err := service.buyerOfCaller(ctx)
+	if err != nil {
+		return
+	}
+	order, err := service.order.Find(ctx, id)
+	if err != nil || order == nil {
+		return
+	}
+	// the order of somebody else is not found for the caller
+	if buyer != nil && order.Buyer != *buyer {
+		return nil, nil
+	}
+	orderDto = &OrderDto{}
+	if err = copier.Copy(orderDto, order); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *orderService) FindAllOrderByFilter(
+	ctx context.Context, dto OrderFilterDto,
+) (ordersDto []OrderDto, err error) {
+	if dto.Buyer, err = service.scopeBuyer(ctx, dto.Buyer); err != nil {
+		return
+	}
+	selector, err := selectorOfOrderFilter(dto)
+	if err != nil {
+		return
+	}
+	orders, err := service.order.FindAllByFilter(ctx, repositories.SelectorOfOrder(selector))
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&ordersDto, &orders); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *orderService) FindAllOrderByFilterAndPage(
+	ctx context.Context, dto OrderFilterDto, pageable utils.Pageable,
+) (ordersDto []OrderDto, err error) {
+	if dto.Buyer, err = service.scopeBuyer(ctx, dto.Buyer); err != nil {
+		return
+	}
+	selector, err := selectorOfOrderFilter(dto)
+	if err != nil {
+		return
+	}
+	page, err := service.order.FindAllByFilterAndPage(ctx, repositories.SelectorOfOrder(selector), pageable)
+	if err != nil {
+		return
+	}
+	orders, ok := page.Content.([]repositories.Order)
+	if !ok {
+		return nil, utils.ErrCovertContent
+	}
+	if err = copier.Copy(&ordersDto, &orders); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// buyerOfCaller returns the buyer the caller is kept to, nil for admins and minters
+// who see the orders of everybody.
+func (service *orderService) buyerOfCaller(ctx context.Context) (buyer *string, err error) {
+	auth, ok := security.AuthenticationFrom(ctx)
+	if !ok {
+		return nil, NewUserServiceError(UserUnauthenticated)
+	}
+	if security.HasAuthority(auth, security.RoleAdmin) || security.HasAuthority(auth, security.RoleMinter) {
+		return nil, nil
+	}
+	userId, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	return &userId, nil
+}
+
+// scopeBuyer replaces the buyer asked for with the caller unless the caller sees every order.
+func (service *orderService) scopeBuyer(ctx context.Context, asked *string) (buyer *string, err error) {
+	buyer, err = service.buyerOfCaller(ctx)
+	if err != nil || buyer != nil {
+		return
+	}
+	return asked, nil
+}
+
+func (service *orderService) PlaceOrder(ctx context.Context, dto OrderItemDto) (orderDto *OrderDto, err error) {
+	if dto.Amount <= 0 {
+		return nil, NewOrderServiceError(OrderAmountInvalid)
+	}
+	buyer, err := actorOf(ctx, service.user)
+	if err != nil {
+		return
+	}
+	creation, err := service.creation.ReserveCreation(ctx, dto.CreationId, dto.Amount)
+	if err != nil {
+		return
+	}
+	return service.createOrder(ctx, creation, buyer, dto.Amount, creation.Price*dto.Amount)
+}
+
+// PlaceAuctionOrder mints the lot of a closed auction for its winner. The auction
+// already holds the whole edition, so no stock is reserved here.
+func (service *orderService) PlaceAuctionOrder(
+	ctx context.Context, dto AuctionOrderDto,
+) (orderDto *OrderDto, err error) {
+	if dto.Amount <= 0 {
+		return nil, NewOrderServiceError(OrderAmountInvalid)
+	}
+	creation, err := service.creation.FindCreationByID(ctx, dto.CreationId)
+	if err != nil {
+		return
+	}
+	if creation == nil {
+		return nil, NewOrderServiceError(CreationNotFound)
+	}
+	return service.createOrder(ctx, creation, dto.Buyer, dto.Amount, dto.Price)
+}
+
+func (service *orderService) createOrder(
+	ctx context.Context, creation *CreationDto, buyer string, amount, price int,
+) (orderDto *OrderDto, err error) {
+	creationId, err := primitive.ObjectIDFromHex(creation.CreationID)
+	if err != nil {
+		return nil, NewOrderServiceError(CreationNotFound)
+	}
+	now := time.Now()
+	order := &repositories.Order{
+		ID:         primitive.NewObjectID(),
+		CreationID: creationId,
+		BrandID:    creation.BrandID,
+		Buyer:      buyer,
+		Amount:     amount,
+		Price:      price,
+		Items:      []repositories.ItemID{},
+		Status:     repositories.OrderPending,
+		CreateAt:   now,
+		UpdateAt:   now,
+		ExpireAt:   now.Add(service.timeout),
+	}
+	err = service.order.Create(ctx, order)
+	if err != nil {
+		return
+	}
+	err = service.itemPublisher.PublishToOrderItem(ctx, messages.OrderItemMessage{
+		OrderId:    order.ID.Hex(),
+		Contract:   creation.ContractAddress,
+		CreationId: creation.CreationID,
+		Amount:     amount,
+	})
+	if err != nil {
+		return
+	}
+	orderDto = &OrderDto{}
+	if err = copier.Copy(orderDto, order); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *orderService) MintOrder(ctx context.Context, orderId string) (err error) {
+	id, err := primitive.ObjectIDFromHex(orderId)
+	if err != nil {
+		return NewOrderServiceError(OrderNotFound)
+	}
+	order, err := service.order.UpdateStatus(
+		ctx, id, []repositories.OrderStatus{repositories.OrderPending}, repositories.OrderMinting,
+	)
+	if err != nil {
+		return
+	}
+	if order == nil {
+		return NewOrderServiceError(OrderNotPending)
+	}
+	return
+}
+
+func (service *orderService) DeliverOrder(ctx context.Context, dto DeliverOrderDto) (orderDto *OrderDto, err error) {
+	id, err := primitive.ObjectIDFromHex(dto.OrderId)
+	if err != nil {
+		return nil, NewOrderServiceError(OrderNotFound)
+	}
+	order, err := service.order.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if order == nil || order.CreationID.Hex() != dto.CreationId {
+		return nil, NewOrderServiceError(OrderNotFound)
+	}
+	order, err = service.order.Deliver(ctx, id, repositories.ItemID{
+		Contract: dto.Contract,
+		Token:    dto.Token,
+	})
+	if err != nil {
+		return
+	}
+	if order == nil {
+		return nil, NewOrderServiceError(OrderNotPending)
+	}
+	if order.Delivered >= order.Amount {
+		order, err = service.order.UpdateStatus(
+			ctx, id, repositories.OrderStatusOfDeliverable, repositories.OrderDelivered,
+		)
+		if err != nil {
+			return
+		}
+		if order == nil {
+			return nil, NewOrderServiceError(OrderNotPending)
+		}
+	}
+	orderDto = &OrderDto{}
+	if err = copier.Copy(orderDto, order); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *orderService) FailOrder(ctx context.Context, dto FailOrderDto) (err error) {
+	id, err := primitive.ObjectIDFromHex(dto.OrderId)
+	if err != nil {
+		return NewOrderServiceError(OrderNotFound)
+	}
+	order, err := service.failOrder(ctx, id, dto.Reason)
+	if err != nil {
+		return
+	}
+	if order == nil {
+		return NewOrderServiceError(OrderNotPending)
+	}
+	return
+}
+
+// ExpireOrder fails every order the factory did not finish delivering
+// before it expired and gives back the undelivered reservation.
+func (service *orderService) ExpireOrder(ctx context.Context) (amount int, err error) {
+	now := time.Now()
+	selector := repositories.OrderSelector{
+		Status:       repositories.OrderStatusOfDeliverable,
+		ExpireBefore: &now,
+	}
+	orders, err := service.order.FindAllByFilter(ctx, repositories.SelectorOfOrder(selector))
+	if err != nil {
+		return
+	}
+	for _, order := range orders {
+		failed, err := service.failOrder(ctx, order.ID, "order expired")
+		if err != nil {
+			return amount, err
+		}
+		if failed != nil {
+			amount++
+		}
+	}
+	return
+}
+
+func (service *orderService) failOrder(
+	ctx context.Context, id primitive.ObjectID, reason string,
+) (order *repositories.Order, err error) {
+	order, err = service.order.Fail(ctx, id, reason)
+	if err != nil || order == nil {
+		return
+	}
+	err = service.creation.ReleaseCreation(ctx, order.CreationID.Hex(), order.Amount-order.Delivered)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func selectorOfOrderFilter(dto OrderFilterDto) (selector repositories.OrderSelector, err error) {
+	selector = repositories.OrderSelector{
+		Buyer:        dto.Buyer,
+		CreateAfter:  dto.CreateAfter,
+		CreateBefore: dto.CreateBefore,
+		Conditions:   dto.Conditions,
+	}
+	if dto.CreationID != nil {
+		if creationId, err := primitive.ObjectIDFromHex(*dto.CreationID); err != nil {
+			return selector, err
+		} else {
+			selector.CreationID = &creationId
+		}
+	}
+	for _, status := range dto.Status {
+		selector.Status = append(selector.Status, repositories.OrderStatus(status))
+	}
+	return
+}
+
+type OrderDto struct {
+	OrderID   string    `json:"orderId"`
+	Creation  string    `json:"creation"`
+	BrandID   string    `json:"brandId"`
+	Buyer     string    `json:"buyer"`
+	Amount    int       `json:"amount"`
+	Delivered int       `json:"delivered"`
+	Price     int       `json:"price"`
+	Items     []ItemDto `json:"items"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	CreateAt  time.Time `json:"createAt"`
+	UpdateAt  time.Time `json:"updateAt"`
+	ExpireAt  time.Time `json:"expireAt"`
+}
+
+func (dto *OrderDto) ID(id primitive.ObjectID) {
+	dto.OrderID = id.Hex()
+}
+
+func (dto *OrderDto) CreationID(id primitive.ObjectID) {
+	dto.Creation = id.Hex()
+}
+
+var OrderSortFields = repositories.OrderSortFields
+
+var OrderFilterFields = repositories.OrderFilterFields
+
+type OrderFilterDto struct {
+	Buyer        *string           `json:"buyer"`
+	CreationID   *string           `json:"creationId"`
+	Status       []string          `json:"status"`
+	CreateAfter  *time.Time        `json:"createAfter"`
+	CreateBefore *time.Time        `json:"createBefore"`
+	Conditions   []utils.Condition `json:"conditions"`
+}
+
+type DeliverOrderDto struct {
+	OrderId    string `json:"orderId"`
+	CreationId string `json:"creationId"`
+	Contract   string `json:"contract"`
+	Token      string `json:"token"`
+}
+
+type AuctionOrderDto struct {
+	CreationId string `json:"creationId"`
+	Buyer      string `json:"buyer"`
+	Amount     int    `json:"amount"`
+	Price      int    `json:"price"`
+}
+
+type FailOrderDto struct {
+	OrderId string `json:"orderId"`
+	Reason  string `json:"reason"`
+}
+
+type OrderServiceError struct {
+	ServiceError
+}
+
+func NewOrderServiceError(e ServiceEvent) error {
+	return &OrderServiceError{ServiceError{ServiceName: "OrderService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"github.com/casbin/casbin"
+	"nftshopping-store-api/pkg/casbins"
+)
+
+type PolicyService interface {
+	FindAllPolicy(ctx context.Context, subject *string) (policiesDto []PolicyDto, err error)
+	AddPolicy(ctx context.Context, dto PolicyDto) (err error)
+	RemovePolicy(ctx context.Context, dto PolicyDto) (err error)
+	FindAllRole(ctx context.Context, user *string) (rolesDto []RoleDto, err error)
+	AddRole(ctx context.Context, dto RoleDto) (err error)
+	RemoveRole(ctx context.Context, dto RoleDto) (err error)
+}
+
+// policyService edits the casbin policy, every change is saved to mongo and
+// the other instances are told to reload it by the enforcer's watcher.
+type policyService struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+func NewPolicyService() (service PolicyService, err error) {
+	enforcer, err := casbins.GetEnforcer()
+	if err != nil {
+		return nil, err
+	}
+	return &policyService{
+		enforcer: enforcer,
+	}, nil
+}
+
+func (service *policyService) FindAllPolicy(ctx context.Context, subject *string) (policiesDto []PolicyDto, err error) {
+	var policies [][]string
+	if subject != nil {
+		policies = service.enforcer.GetFilteredPolicy(0, *subject)
+	} else {
+		policies = service.enforcer.GetPolicy()
+	}
+	policiesDto = []PolicyDto{}
+	for _, policy := range policies {
+		if len(policy) < 3 {
+			continue
+		}
+		policiesDto = append(policiesDto, PolicyDto{Subject: policy[0], Object: policy[1], Action: policy[2]})
+	}
+	return
+}
+
+func (service *policyService) AddPolicy(ctx context.Context, dto PolicyDto) (err error) {
+	if len(dto.Subject) == 0 || len(dto.Object) == 0 || len(dto.Action) == 0 {
+		return NewPolicyServiceError(PolicyInvalid)
+	}
+	isAdded, err := casbins.Safe(service.enforcer, func() bool {
+		return service.enforcer.AddPolicy(dto.Subject, dto.Object, dto.Action)
+	})
+	if err != nil {
+		return
+	}
+	if !isAdded {
+		return NewPolicyServiceError(PolicyExisted)
+	}
+	return
+}
+
+func (service *policyService) RemovePolicy(ctx context.Context, dto PolicyDto) (err error) {
+	isRemoved, err := casbins.Safe(service.enforcer, func() bool {
+		return service.enforcer.RemovePolicy(dto.Subject, dto.Object, dto.Action)
+	})
+	if err != nil {
+		return
+	}
+	if !isRemoved {
+		return NewPolicyServiceError(PolicyNotFound)
+	}
+	return
+}
+
+func (service *policyService) FindAllRole(ctx context.Context, user *string) (rolesDto []RoleDto, err error) {
+	var roles [][]string
+	if user != nil {
+		roles = service.enforcer.GetFilteredGroupingPolicy(0, *user)
+	} else {
+		roles = service.enforcer.GetGroupingPolicy()
+	}
+	rolesDto = []RoleDto{}
+	for _, role := range roles {
+		if len(role) < 2 {
+			continue
+		}
+		rolesDto = append(rolesDto, RoleDto{User: role[0], Role: role[1]})
+	}
+	return
+}
+
+func (service *policyService) AddRole(ctx context.Context, dto RoleDto) (err error) {
+	if len(dto.User) == 0 || len(dto.Role) == 0 || dto.User == dto.Role {
+		return NewPolicyServiceError(PolicyInvalid)
+	}
+	isAdded, err := casbins.Safe(service.enforcer, func() bool {
+		return service.enforcer.AddGroupingPolicy(dto.User, dto.Role)
+	})
+	if err != nil {
+		return
+	}
+	if !isAdded {
+		return NewPolicyServiceError(RoleAssignmentExisted)
+	}
+	return
+}
+
+func (service *policyService) RemoveRole(ctx context.Context, dto RoleDto) (err error) {
+	isRemoved, err := casbins.Safe(service.enforcer, func() bool {
+		return service.enforcer.RemoveGroupingPolicy(dto.User, dto.Role)
+	})
+	if err != nil {
+		return
+	}
+	if !isRemoved {
+		return NewPolicyServiceError(RoleAssignmentNotFound)
+	}
+	return
+}
+
+// PolicyDto allows Subject, a user or a role, to call Object, an api path that may end with *, by Action, an http method or *.
+type PolicyDto struct {
+	Subject string `json:"subject"`
+	Object  string `json:"object"`
+	Action  string `json:"action"`
+}
+
+// RoleDto makes User, a user or a role, inherit every policy of Role.
+type RoleDto struct {
+	User string `json:"user"`
+	Role string `json:"role"`
+}
+
+type PolicyServiceError struct {
+	ServiceError
+}
+
+func NewPolicyServiceError(e ServiceEvent) error {
+	return &PolicyServiceError{ServiceError{ServiceName: "PolicyService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
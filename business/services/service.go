@@ -16,14 +16,23 @@ func GetService() (instance *service, err error) {
 }
 
 type service struct {
-	Auth       AuthService
-	User       UserService
-	Creation   CreationService
-	Item       ItemService
-	Collection CollectionService
-	Trade      TradeService
-	Brand      BrandService
-	Stock      StockService
+	Auth           AuthService
+	User           UserService
+	Creation       CreationService
+	Item           ItemService
+	Collection     CollectionService
+	Trade          TradeService
+	Brand          BrandService
+	Stock          StockService
+	Order          OrderService
+	Auction        AuctionService
+	Market         MarketService
+	Fee            FeeService
+	ServiceAccount ServiceAccountService
+	Policy         PolicyService
+	Analytics      AnalyticsService
+	Holder         HolderService
+	DeadLetter     DeadLetterService
 }
 
 func newService() (instance *service, err error) {
@@ -43,7 +52,19 @@ func newService() (instance *service, err error) {
 	if err != nil {
 		return
 	}
-	item, err := NewItemService(creation, brand, user)
+	fee, err := NewFeeService(creation, brand, user)
+	if err != nil {
+		return
+	}
+	order, err := NewOrderService(creation, user)
+	if err != nil {
+		return
+	}
+	auction, err := NewAuctionService(creation, user, order, fee)
+	if err != nil {
+		return
+	}
+	item, err := NewItemService(creation, brand, user, order)
 	if err != nil {
 		return
 	}
@@ -51,7 +72,7 @@ func newService() (instance *service, err error) {
 	if err != nil {
 		return
 	}
-	trade, err := NewTradeService(user, creation)
+	trade, err := NewTradeService(user, creation, fee)
 	if err != nil {
 		return
 	}
@@ -59,16 +80,49 @@ func newService() (instance *service, err error) {
 	if err != nil {
 		return
 	}
+	market, err := NewMarketService(creation, user, trade)
+	if err != nil {
+		return
+	}
+	serviceAccount, err := NewServiceAccountService()
+	if err != nil {
+		return
+	}
+	policy, err := NewPolicyService()
+	if err != nil {
+		return
+	}
+	analytics, err := NewAnalyticsService(creation)
+	if err != nil {
+		return
+	}
+	holder, err := NewHolderService()
+	if err != nil {
+		return
+	}
+	deadLetter, err := NewDeadLetterService()
+	if err != nil {
+		return
+	}
 
 	return &service{
-		Auth:       auth,
-		User:       user,
-		Creation:   creation,
-		Item:       item,
-		Collection: collection,
-		Trade:      trade,
-		Brand:      brand,
-		Stock:      stock,
+		Auth:           auth,
+		User:           user,
+		Creation:       creation,
+		Item:           item,
+		Collection:     collection,
+		Trade:          trade,
+		Brand:          brand,
+		Stock:          stock,
+		Order:          order,
+		Auction:        auction,
+		Market:         market,
+		Fee:            fee,
+		ServiceAccount: serviceAccount,
+		Policy:         policy,
+		Analytics:      analytics,
+		Holder:         holder,
+		DeadLetter:     deadLetter,
 	}, nil
 }
 
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/casbins"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/security"
+	"time"
+)
+
+type ServiceAccountService interface {
+	CreateServiceAccount(ctx context.Context, dto CreateServiceAccountDto) (accountDto *ServiceAccountDto, err error)
+	UpdateScopes(ctx context.Context, dto UpdateScopesDto) (accountDto *ServiceAccountDto, err error)
+	FindAllServiceAccount(ctx context.Context) (accountsDto []ServiceAccountDto, err error)
+	CreateApiKey(ctx context.Context, dto CreateApiKeyDto) (keyDto *ApiKeyDto, err error)
+	RotateApiKey(ctx context.Context, dto RotateApiKeyDto) (keyDto *ApiKeyDto, err error)
+	RevokeApiKey(ctx context.Context, dto RevokeApiKeyDto) (err error)
+	FindAllApiKey(ctx context.Context, serviceAccountId string) (keysDto []ApiKeyDto, err error)
+	FindAuthByApiKey(ctx context.Context, key string) (auth security.Authentication, err error)
+}
+
+type serviceAccountService struct {
+	account     repositories.ServiceAccountDao
+	apiKey      repositories.ApiKeyDao
+	rotateGrace time.Duration
+}
+
+func NewServiceAccountService() (service ServiceAccountService, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return nil, err
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &serviceAccountService{
+		account:     dao.ServiceAccount,
+		apiKey:      dao.ApiKey,
+		rotateGrace: c.ServiceAccount.RotateGrace,
+	}, nil
+}
+
+func (service *serviceAccountService) CreateServiceAccount(
+	ctx context.Context, dto CreateServiceAccountDto,
+) (accountDto *ServiceAccountDto, err error) {
+	if err = validateScopes(dto.Scopes); err != nil {
+		return
+	}
+	now := time.Now()
+	account := &repositories.ServiceAccount{
+		ID:       primitive.NewObjectID(),
+		Name:     dto.Name,
+		Scopes:   dto.Scopes,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	err = service.account.Create(ctx, account)
+	if err != nil {
+		if err == repositories.ServiceAccountDuplicate {
+			return nil, NewServiceAccountServiceError(ServiceAccountDuplicate)
+		}
+		return
+	}
+	accountDto = &ServiceAccountDto{}
+	if err = copier.Copy(accountDto, account); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *serviceAccountService) UpdateScopes(
+	ctx context.Context, dto UpdateScopesDto,
+) (accountDto *ServiceAccountDto, err error) {
+	if err = validateScopes(dto.Scopes); err != nil {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(dto.ServiceAccountID)
+	if err != nil {
+		return nil, NewServiceAccountServiceError(ServiceAccountNotFound)
+	}
+	account, err := service.account.UpdateScopes(ctx, id, dto.Scopes)
+	if err != nil {
+		return
+	}
+	if account == nil {
+		return nil, NewServiceAccountServiceError(ServiceAccountNotFound)
+	}
+	accountDto = &ServiceAccountDto{}
+	if err = copier.Copy(accountDto, account); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *serviceAccountService) FindAllServiceAccount(ctx context.Context) (accountsDto []ServiceAccountDto, err error) {
+	accounts, err := service.account.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&accountsDto, &accounts); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (service *serviceAccountService) CreateApiKey(ctx context.Context, dto CreateApiKeyDto) (keyDto *ApiKeyDto, err error) {
+	id, err := primitive.ObjectIDFromHex(dto.ServiceAccountID)
+	if err != nil {
+		return nil, NewServiceAccountServiceError(ServiceAccountNotFound)
+	}
+	account, err := service.account.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if account == nil {
+		return nil, NewServiceAccountServiceError(ServiceAccountNotFound)
+	}
+	return service.createApiKey(ctx, account.ID)
+}
+
+// RotateApiKey issues a new key for the same service account, the old key keeps working
+// for the rotate grace so the client can switch over without downtime.
+func (service *serviceAccountService) RotateApiKey(ctx context.Context, dto RotateApiKeyDto) (keyDto *ApiKeyDto, err error) {
+	id, err := primitive.ObjectIDFromHex(dto.KeyID)
+	if err != nil {
+		return nil, NewServiceAccountServiceError(ApiKeyNotFound)
+	}
+	now := time.Now()
+	old, err := service.apiKey.Find(ctx, id)
+	if err != nil {
+		return
+	}
+	if old == nil {
+		return nil, NewServiceAccountServiceError(ApiKeyNotFound)
+	}
+	if !old.IsUsable(now) {
+		return nil, NewServiceAccountServiceError(ApiKeyRevoked)
+	}
+	expired, err := service.apiKey.Expire(ctx, old.ID, now.Add(service.rotateGrace))
+	if err != nil {
+		return
+	}
+	// another rotation or a revoke got to the key first
+	if expired == nil {
+		return nil, NewServiceAccountServiceError(ApiKeyRevoked)
+	}
+	return service.createApiKey(ctx, old.ServiceAccountID)
+}
+
+func (service *serviceAccountService) RevokeApiKey(ctx context.Context, dto RevokeApiKeyDto) (err error) {
+	id, err := primitive.ObjectIDFromHex(dto.KeyID)
+	if err != nil {
+		return NewServiceAccountServiceError(ApiKeyNotFound)
+	}
+	key, err := service.apiKey.Revoke(ctx, id, time.Now())
+	if err != nil {
+		return
+	}
+	if key == nil {
+		return NewServiceAccountServiceError(ApiKeyRevoked)
+	}
+	return
+}
+
+func (service *serviceAccountService) FindAllApiKey(ctx context.Context, serviceAccountId string) (keysDto []ApiKeyDto, err error) {
+	id, err := primitive.ObjectIDFromHex(serviceAccountId)
+	if err != nil {
+		return nil, NewServiceAccountServiceError(ServiceAccountNotFound)
+	}
+	keys, err := service.apiKey.FindAllByServiceAccount(ctx, id)
+	if err != nil {
+		return
+	}
+	if err = copier.Copy(&keysDto, &keys); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// FindAuthByApiKey resolves the service account of a usable api key, nil means the key is not accepted.
+func (service *serviceAccountService) FindAuthByApiKey(ctx context.Context, key string) (auth security.Authentication, err error) {
+	keyId, secret, ok := security.SplitApiKey(key)
+	if !ok {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(keyId)
+	if err != nil {
+		return nil, nil
+	}
+	apiKey, err := service.apiKey.Find(ctx, id)
+	if err != nil || apiKey == nil {
+		return
+	}
+	if !apiKey.IsUsable(time.Now()) || !security.MatchApiKeySecret(secret, apiKey.Hash) {
+		return nil, nil
+	}
+	account, err := service.account.Find(ctx, apiKey.ServiceAccountID)
+	if err != nil || account == nil {
+		return
+	}
+	return account, nil
+}
+
+func (service *serviceAccountService) createApiKey(ctx context.Context, accountId primitive.ObjectID) (keyDto *ApiKeyDto, err error) {
+	id := primitive.NewObjectID()
+	key, hash, err := security.GenerateApiKey(id.Hex())
+	if err != nil {
+		return
+	}
+	apiKey := &repositories.ApiKey{
+		ID:               id,
+		ServiceAccountID: accountId,
+		Hash:             hash,
+		Status:           repositories.ApiKeyActive,
+		CreateAt:         time.Now(),
+	}
+	err = service.apiKey.Create(ctx, apiKey)
+	if err != nil {
+		return
+	}
+	keyDto = &ApiKeyDto{}
+	if err = copier.Copy(keyDto, apiKey); err != nil {
+		return nil, err
+	}
+	keyDto.Key = key
+	return
+}
+
+// validateScopes only accepts scopes that are roles known to casbin.
+func validateScopes(scopes []string) (err error) {
+	enforcer, err := casbins.GetEnforcer()
+	if err != nil {
+		return
+	}
+	roles := map[string]bool{}
+	for _, role := range append(enforcer.GetAllSubjects(), enforcer.GetAllRoles()...) {
+		roles[role] = true
+	}
+	for _, scope := range scopes {
+		if !roles[scope] {
+			return NewServiceAccountServiceError(ScopeInvalid)
+		}
+	}
+	return
+}
+
+type ServiceAccountDto struct {
+	ServiceAccountID string    `json:"serviceAccountId"`
+	Name             string    `json:"name"`
+	Scopes           []string  `json:"scopes"`
+	CreateAt         time.Time `json:"createAt"`
+	UpdateAt         time.Time `json:"updateAt"`
+}
+
+func (dto *ServiceAccountDto) ID(id primitive.ObjectID) {
+	dto.ServiceAccountID = id.Hex()
+}
+
+type ApiKeyDto struct {
+	KeyID          string     `json:"keyId"`
+	ServiceAccount string     `json:"serviceAccount"`
+	Key            string     `json:"key,omitempty"`
+	Status         string     `json:"status"`
+	CreateAt       time.Time  `json:"createAt"`
+	ExpireAt       *time.Time `json:"expireAt"`
+	RevokeAt       *time.Time `json:"revokeAt"`
+}
+
+func (dto *ApiKeyDto) ID(id primitive.ObjectID) {
+	dto.KeyID = id.Hex()
+}
+
+func (dto *ApiKeyDto) ServiceAccountID(id primitive.ObjectID) {
+	dto.ServiceAccount = id.Hex()
+}
+
+type CreateServiceAccountDto struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type UpdateScopesDto struct {
+	ServiceAccountID string   `json:"serviceAccountId"`
+	Scopes           []string `json:"scopes"`
+}
+
+type CreateApiKeyDto struct {
+	ServiceAccountID string `json:"serviceAccountId"`
+}
+
+type RotateApiKeyDto struct {
+	KeyID string `json:"keyId"`
+}
+
+type RevokeApiKeyDto struct {
+	KeyID string `json:"keyId"`
+}
+
+type ServiceAccountServiceError struct {
+	ServiceError
+}
+
+func NewServiceAccountServiceError(e ServiceEvent) error {
+	return &ServiceAccountServiceError{ServiceError{ServiceName: "ServiceAccountService", Code: e.GetEvent().Code, Msg: e.GetEvent().Msg, Err: nil}}
+}
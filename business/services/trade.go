@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
 	"nftshopping-store-api/persistence/repositories"
 	"nftshopping-store-api/pkg/utils"
 	"time"
@@ -17,24 +19,44 @@ type TradeService interface {
 	FindAllTransactionByFilterAndPage(
 		ctx context.Context, dto TransactionFilterDto, pageable utils.Pageable,
 	) (transactionsDto []TransactionDto, err error)
+	FindAllTransactionByFilterAndCursor(
+		ctx context.Context, dto TransactionFilterDto, pageable utils.Pageable,
+	) (transactionsDto []TransactionDto, next string, err error)
 	TradeInCreation(ctx context.Context, dto TradeInCreationDto) (txn *TransactionDto, err error)
+	TradeInItem(ctx context.Context, dto TradeInItemDto) (txn *TransactionDto, err error)
 }
 
 type tradeService struct {
-	user        UserService
-	creation    CreationService
-	transaction repositories.TransactionDao
+	user           UserService
+	creation       CreationService
+	transaction    repositories.TransactionDao
+	collection     repositories.CollectionDao
+	item           repositories.ItemDao
+	listing        repositories.ListingDao
+	offer          repositories.OfferDao
+	fee            FeeService
+	tradePublisher publishers.TradePublisher
 }
 
-func NewTradeService(user UserService, creation CreationService) (service TradeService, err error) {
+func NewTradeService(user UserService, creation CreationService, fee FeeService) (service TradeService, err error) {
 	dao, err := repositories.GetRepository()
 	if err != nil {
 		return nil, err
 	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
 	return &tradeService{
-		transaction: dao.Transaction,
-		user:        user,
-		creation:    creation,
+		transaction:    dao.Transaction,
+		collection:     dao.Collection,
+		item:           dao.Item,
+		listing:        dao.Listing,
+		offer:          dao.Offer,
+		user:           user,
+		creation:       creation,
+		fee:            fee,
+		tradePublisher: publisher.Trade,
 	}, nil
 }
 
@@ -57,8 +79,7 @@ func (service *tradeService) FindTransaction(ctx context.Context, tnxId string)
 func (service *tradeService) FindAllTransactionByFilter(
 	ctx context.Context, dto TransactionFilterDto,
 ) (transactionsDto []TransactionDto, err error) {
-	selector := repositories.TransactionSelector{}
-	err = copier.Copy(&selector, &dto)
+	selector, err := selectorOfTransactionFilter(ctx, service.creation, dto)
 	if err != nil {
 		return
 	}
@@ -76,8 +97,7 @@ func (service *tradeService) FindAllTransactionByFilter(
 func (service *tradeService) FindAllTransactionByFilterAndPage(
 	ctx context.Context, dto TransactionFilterDto, pageable utils.Pageable,
 ) (transactionsDto []TransactionDto, err error) {
-	selector := repositories.TransactionSelector{}
-	err = copier.Copy(&selector, &dto)
+	selector, err := selectorOfTransactionFilter(ctx, service.creation, dto)
 	if err != nil {
 		return
 	}
@@ -95,9 +115,60 @@ func (service *tradeService) FindAllTransactionByFilterAndPage(
 	return
 }
 
+func (service *tradeService) FindAllTransactionByFilterAndCursor(
+	ctx context.Context, dto TransactionFilterDto, pageable utils.Pageable,
+) (transactionsDto []TransactionDto, next string, err error) {
+	selector, err := selectorOfTransactionFilter(ctx, service.creation, dto)
+	if err != nil {
+		return
+	}
+	page, err := service.transaction.FindAllByFilterAndCursor(ctx, repositories.SelectorOfTransaction(selector), pageable)
+	if err != nil {
+		return
+	}
+	transactions, ok := page.Content.([]repositories.Transaction)
+	if !ok {
+		return nil, "", utils.ErrCovertContent
+	}
+	if err = copier.Copy(&transactionsDto, &transactions); err != nil {
+		return nil, "", err
+	}
+	return transactionsDto, page.NextCursor, nil
+}
+
+// selectorOfTransactionFilter resolves the creation name and properties of the filter into
+// the creations they match, since transactions only keep the creation id.
+func selectorOfTransactionFilter(
+	ctx context.Context, creation CreationService, dto TransactionFilterDto,
+) (selector repositories.TransactionSelector, err error) {
+	if err = copier.Copy(&selector, &dto); err != nil {
+		return
+	}
+	selector.Conditions = dto.Conditions
+	if dto.CreationName == nil && len(dto.Properties) == 0 {
+		return
+	}
+	creations, err := creation.FindAllCreationByFilter(ctx, CreationFilterDto{
+		CreationName: dto.CreationName,
+		Properties:   dto.Properties,
+	})
+	if err != nil {
+		return
+	}
+	selector.CreationIDs = []string{}
+	for _, creationDto := range creations {
+		selector.CreationIDs = append(selector.CreationIDs, creationDto.CreationID)
+	}
+	return
+}
+
 func (service *tradeService) TradeInCreation(
 	ctx context.Context, dto TradeInCreationDto,
 ) (txn *TransactionDto, err error) {
+	if dto.Amount <= 0 {
+		return nil, NewTradeServiceError(TradeAmountInvalid)
+	}
+
 	if isExisted, err := service.user.ExistByID(ctx, dto.Buyer); err != nil {
 		return nil, err
 	} else {
@@ -120,6 +191,15 @@ func (service *tradeService) TradeInCreation(
 	if creation == nil {
 		return nil, NewTradeServiceError(CreationNotFound)
 	}
+	creationId, err := primitive.ObjectIDFromHex(dto.CreationID)
+	if err != nil {
+		return nil, NewTradeServiceError(CreationNotFound)
+	}
+
+	ids, err := service.transferItem(ctx, creationId, dto.Seller, dto.Buyer, dto.Amount)
+	if err != nil {
+		return
+	}
 
 	transaction := &repositories.Transaction{}
 	if err = copier.Copy(transaction, &dto); err != nil {
@@ -129,8 +209,20 @@ func (service *tradeService) TradeInCreation(
 	transaction.CreationID = dto.CreationID
 	transaction.BrandID = creation.BrandID
 	transaction.Price = creation.Price * dto.Amount
+	transaction.Items = ids
 	transaction.TradeAt = time.Now()
+	err = service.fee.SplitTrade(ctx, transaction)
+	if err != nil {
+		return
+	}
 	err = service.transaction.Create(ctx, transaction)
+	if err != nil {
+		return
+	}
+	err = service.tradePublisher.PublishToTradeSettled(ctx, tradeSettledMessageOf(transaction))
+	if err != nil {
+		return
+	}
 	txn = &TransactionDto{}
 	err = copier.Copy(txn, &transaction)
 	if err != nil {
@@ -139,6 +231,149 @@ func (service *tradeService) TradeInCreation(
 	return
 }
 
+// TradeInItem sells one specific item from its owner to the buyer at the agreed price.
+// It must run inside a mongo session like TradeInCreation.
+func (service *tradeService) TradeInItem(
+	ctx context.Context, dto TradeInItemDto,
+) (txn *TransactionDto, err error) {
+	if dto.Price < 0 {
+		return nil, NewTradeServiceError(TradePriceInvalid)
+	}
+	if dto.Buyer == dto.Seller {
+		return nil, NewTradeServiceError(SelfTrade)
+	}
+	if isExisted, err := service.user.ExistByID(ctx, dto.Buyer); err != nil {
+		return nil, err
+	} else {
+		if !isExisted {
+			return nil, NewTradeServiceError(UserNotFound)
+		}
+	}
+	id := repositories.ItemID{
+		Contract: dto.Contract,
+		Token:    dto.Token,
+	}
+	item, err := service.item.Find(ctx, &id)
+	if err != nil {
+		return
+	}
+	if item == nil || item.Owner != dto.Seller {
+		return nil, NewTradeServiceError(ItemNotOwned)
+	}
+	moved, err := service.item.UpdateOwner(ctx, []repositories.ItemID{id}, dto.Seller, dto.Buyer)
+	if err != nil {
+		return
+	}
+	if moved != 1 {
+		return nil, NewTradeServiceError(ItemTransferFailed)
+	}
+
+	transaction := &repositories.Transaction{
+		ID:         primitive.NewObjectID(),
+		CreationID: item.CreationID.Hex(),
+		BrandID:    item.BrandOwner,
+		Buyer:      dto.Buyer,
+		Seller:     dto.Seller,
+		Amount:     1,
+		Price:      dto.Price,
+		Items:      []repositories.ItemID{id},
+		TradeAt:    time.Now(),
+	}
+	err = service.fee.SplitTrade(ctx, transaction)
+	if err != nil {
+		return
+	}
+	err = service.transaction.Create(ctx, transaction)
+	if err != nil {
+		return
+	}
+	err = service.tradePublisher.PublishToTradeSettled(ctx, tradeSettledMessageOf(transaction))
+	if err != nil {
+		return
+	}
+	txn = &TransactionDto{}
+	err = copier.Copy(txn, transaction)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// transferItem moves amount items of the creation from seller to buyer.
+// It must run inside a mongo session so a partial transfer can be rolled back.
+func (service *tradeService) transferItem(
+	ctx context.Context, creationId primitive.ObjectID, seller, buyer string, amount int,
+) (ids []repositories.ItemID, err error) {
+	collect, err := service.collection.Find(ctx, &repositories.CollectID{
+		Owner:      seller,
+		CreationID: creationId,
+	})
+	if err != nil {
+		return
+	}
+	if collect == nil || collect.Amount < amount {
+		return nil, NewTradeServiceError(CollectionNotEnough)
+	}
+
+	selector := repositories.ItemSelector{
+		CreationID: &creationId,
+		Owner:      &seller,
+	}
+	items, err := service.item.FindAllByFilterAndLimit(ctx, repositories.SelectorOfItem(selector), amount)
+	if err != nil {
+		return
+	}
+	if len(items) < amount {
+		return nil, NewTradeServiceError(CollectionNotEnough)
+	}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	moved, err := service.item.UpdateOwner(ctx, ids, seller, buyer)
+	if err != nil {
+		return
+	}
+	if moved != int64(len(ids)) {
+		return nil, NewTradeServiceError(ItemTransferFailed)
+	}
+	// the seller can no longer honour listings or offers on the items
+	err = service.listing.CancelByItem(ctx, ids...)
+	if err != nil {
+		return
+	}
+	err = service.offer.RejectByItem(ctx, ids...)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// tradeSettledMessageOf describes a settled transaction for the TradeSettled event.
+func tradeSettledMessageOf(transaction *repositories.Transaction) (msg messages.TradeSettledMessage) {
+	msg = messages.TradeSettledMessage{
+		TransactionId: transaction.ID.Hex(),
+		CreationId:    transaction.CreationID,
+		BrandId:       transaction.BrandID,
+		Buyer:         transaction.Buyer,
+		Seller:        transaction.Seller,
+		Amount:        transaction.Amount,
+		Price:         transaction.Price,
+		Fee: messages.TradeFee{
+			SellerProceeds: transaction.Fee.SellerProceeds,
+			Creator:        transaction.Fee.Creator,
+			CreatorRoyalty: transaction.Fee.CreatorRoyalty,
+			BrandRoyalty:   transaction.Fee.BrandRoyalty,
+			PlatformFee:    transaction.Fee.PlatformFee,
+		},
+		TradeAt: transaction.TradeAt,
+	}
+	for _, item := range transaction.Items {
+		msg.Items = append(msg.Items, messages.TradedItem{Contract: item.Contract, Token: item.Token})
+	}
+	return
+}
+
 type TransactionDto struct {
 	TransactionID string    `json:"transactionId"`
 	Creation      string    `json:"creation"`
@@ -147,6 +382,8 @@ type TransactionDto struct {
 	Seller        string    `json:"seller"`
 	Price         int       `json:"price"`
 	Amount        int       `json:"amount"`
+	Items         []ItemDto `json:"items"`
+	Fee           FeeDto    `json:"fee"`
 	TradeAt       time.Time `json:"tradeAt"`
 }
 
@@ -161,17 +398,30 @@ type TradeInCreationDto struct {
 	Amount     int    `json:"amount"`
 }
 
+type TradeInItemDto struct {
+	Contract string `json:"contract"`
+	Token    string `json:"token"`
+	Buyer    string `json:"buyer"`
+	Seller   string `json:"seller"`
+	Price    int    `json:"price"`
+}
+
+var TransactionSortFields = repositories.TransactionSortFields
+
+var TransactionFilterFields = repositories.TransactionFilterFields
+
 type TransactionFilterDto struct {
-	CreationID   *string    `json:"creationID"`
-	BrandID      *string    `json:"brandId"`
-	Buyer        *string    `json:"buyer"`
-	Seller       *string    `json:"seller"`
-	CreationName *string    `json:"creationName"`
-	Properties   []string   `json:"properties"`
-	TradedBefore *time.Time `json:"tradedBefore"`
-	TradedAfter  *time.Time `json:"tradedAfter"`
-	MaxPrice     *int       `json:"maxPrice"`
-	MinPrice     *int       `json:"minPrice"`
+	CreationID   *string           `json:"creationID"`
+	BrandID      *string           `json:"brandId"`
+	Buyer        *string           `json:"buyer"`
+	Seller       *string           `json:"seller"`
+	CreationName *string           `json:"creationName"`
+	Properties   []string          `json:"properties"`
+	TradedBefore *time.Time        `json:"tradedBefore"`
+	TradedAfter  *time.Time        `json:"tradedAfter"`
+	MaxPrice     *int              `json:"maxPrice"`
+	MinPrice     *int              `json:"minPrice"`
+	Conditions   []utils.Condition `json:"conditions"`
 }
 
 type TradeServiceError struct {
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+// existingUserService finds every user.
+type existingUserService struct {
+	UserService
+}
+
+func (service *existingUserService) ExistByID(ctx context.Context, userId string) (bool, error) {
+	return true, nil
+}
+
+func TestTradeInCreationOfDeletedCreation(t *testing.T) {
+	service := &tradeService{
+		user:     &existingUserService{},
+		creation: &creationService{creation: &deletedCreationDao{}},
+	}
+	_, err := service.TradeInCreation(context.Background(), TradeInCreationDto{
+		CreationID: primitive.NewObjectID().Hex(),
+		Buyer:      primitive.NewObjectID().Hex(),
+		Seller:     primitive.NewObjectID().Hex(),
+		Amount:     1,
+	})
+	if e, ok := err.(*TradeServiceError); !ok || e.Code != CreationNotFound.GetEvent().Code {
+		t.Fatalf("TradeInCreation() error = %v, want %v", err, NewTradeServiceError(CreationNotFound))
+	}
+}
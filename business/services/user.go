@@ -4,7 +4,11 @@ import (
 	"context"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/event/publishers"
 	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/security"
+	"time"
 )
 
 type UserService interface {
@@ -17,7 +21,8 @@ type UserService interface {
 }
 
 type userService struct {
-	user repositories.UserDao
+	user          repositories.UserDao
+	userPublisher publishers.UserPublisher
 }
 
 func NewUserService() (service UserService, err error) {
@@ -25,8 +30,13 @@ func NewUserService() (service UserService, err error) {
 	if err != nil {
 		return nil, err
 	}
+	publisher, err := publishers.GetPublisher()
+	if err != nil {
+		return
+	}
 	return &userService{
-		user: dao.User,
+		user:          dao.User,
+		userPublisher: publisher.User,
 	}, nil
 }
 
@@ -60,6 +70,14 @@ func (service *userService) Register(ctx context.Context, dto RegisterUserDto) (
 	if err != nil {
 		return
 	}
+	err = service.userPublisher.PublishToUserRegistered(ctx, messages.UserRegisteredMessage{
+		UserId:     user.ID.Hex(),
+		Account:    user.Account,
+		RegisterAt: time.Now(),
+	})
+	if err != nil {
+		return
+	}
 	userDto = &UserDto{}
 	if err = copier.Copy(userDto, user); err != nil {
 		return
@@ -74,11 +92,12 @@ func (service *userService) DeleteUser(ctx context.Context, userId string) (err
 	}
 	err = service.user.Delete(ctx, id)
 	if err != nil {
-		if err != repositories.UserNotFound {
-			return nil
-		}
+		return
 	}
-	return
+	return service.userPublisher.PublishToUserDeleted(ctx, messages.UserDeletedMessage{
+		UserId:   userId,
+		DeleteAt: time.Now(),
+	})
 }
 
 func (service *userService) FindUserByID(ctx context.Context, userId string) (userDto *UserDto, err error) {
@@ -111,6 +130,22 @@ func (service *userService) FindUserByAccount(ctx context.Context, account strin
 	return
 }
 
+// actorOf resolves the authenticated caller to the id of its user account.
+func actorOf(ctx context.Context, user UserService) (userId string, err error) {
+	auth, ok := security.AuthenticationFrom(ctx)
+	if !ok {
+		return "", NewUserServiceError(UserUnauthenticated)
+	}
+	userDto, err := user.FindUserByAccount(ctx, auth.GetName())
+	if err != nil {
+		return
+	}
+	if userDto == nil {
+		return "", NewUserServiceError(UserNotFound)
+	}
+	return userDto.UserID, nil
+}
+
 type RegisterUserDto struct {
 	EtherAccount string `json:"etherAccount"`
 }
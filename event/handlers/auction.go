@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/transactions"
+)
+
+type AuctionHandler interface {
+	ListenAuctionLost(msg *message.Message) (err error)
+}
+
+type auctionHandler struct {
+	logger      log.Logger
+	idempotency *idempotency
+}
+
+func NewAuctionHandler() (handler AuctionHandler, err error) {
+	logger, err := log.GetLog()
+	if err != nil {
+		return nil, err
+	}
+	idempotency, err := newIdempotency()
+	if err != nil {
+		return nil, err
+	}
+	return &auctionHandler{
+		logger:      logger,
+		idempotency: idempotency,
+	}, nil
+}
+
+// ListenAuctionLost tells a bidder that the auction closed without them winning.
+func (handler *auctionHandler) ListenAuctionLost(msg *message.Message) (err error) {
+	handler.logger.InfoF("received message: %s, payload: %s", msg.UUID, string(msg.Payload))
+	var m messages.AuctionLostMessage
+	_, err = messages.Open(event.AuctionLost, msg.Payload, &m)
+	if err != nil {
+		return permanent(err)
+	}
+	txn, err := transactions.NewTransaction("ListenAuctionLost")
+	if err != nil {
+		return
+	}
+	defer txn.End(context.Background())
+
+	// the uuid of a relayed event stays the same across redeliveries
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		err := handler.idempotency.Mark(transactionCtx, "AuctionLost", msg.UUID)
+		if err != nil {
+			return nil, err
+		}
+		handler.notify(m)
+		return nil, nil
+	}
+	_, err = txn.With(context.Background(), callback)
+	if errors.Is(err, repositories.ErrMessageProcessed) {
+		handler.logger.InfoF("skip notified bidder(%s): creation(%s)", m.Bidder, m.CreationId)
+		return nil
+	}
+	if err != nil {
+		return failed(err)
+	}
+	return
+}
+
+func (handler *auctionHandler) notify(m messages.AuctionLostMessage) {
+	if m.IsReserveMiss {
+		handler.logger.InfoF(
+			"notify bidder(%s): auction of creation(%s) closed below reserve price", m.Bidder, m.CreationId,
+		)
+		return
+	}
+	handler.logger.InfoF(
+		"notify bidder(%s): auction of creation(%s) was won at %d", m.Bidder, m.CreationId, m.HighestPrice,
+	)
+}
@@ -1,5 +1,7 @@
 package handlers
 
+import "errors"
+
 var handlerInstance *handler
 
 func GetHandler() (instance *handler, err error) {
@@ -14,7 +16,8 @@ func GetHandler() (instance *handler, err error) {
 }
 
 type handler struct {
-	Item ItemHandler
+	Item    ItemHandler
+	Auction AuctionHandler
 }
 
 func newHandler() (instance *handler, err error) {
@@ -22,8 +25,49 @@ func newHandler() (instance *handler, err error) {
 	if err != nil {
 		return
 	}
+	auction, err := NewAuctionHandler()
+	if err != nil {
+		return
+	}
 
 	return &handler{
-		Item: item,
+		Item:    item,
+		Auction: auction,
 	}, nil
 }
+
+// permanentError is an error redelivering the message can not fix, like a payload
+// that can not be decoded or a request the services turn down.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+func permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent tells the router to give up on the message without retrying it.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// failed makes the errors coded by services permanent, the others are worth retrying.
+func failed(err error) error {
+	var coded interface{ GetCode() int }
+	if errors.As(err, &coded) {
+		return permanent(err)
+	}
+	return err
+}
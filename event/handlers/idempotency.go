@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/config"
+	"time"
+)
+
+const defaultIdempotencyTTL = 7 * 24 * time.Hour
+
+// idempotency remembers the messages a handler has processed, so a redelivered
+// message can be acked without doing its work again.
+type idempotency struct {
+	processed repositories.ProcessedMessageDao
+	ttl       time.Duration
+}
+
+func newIdempotency() (instance *idempotency, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	instance = &idempotency{
+		processed: dao.ProcessedMessage,
+		ttl:       defaultIdempotencyTTL,
+	}
+	if c.Idempotency != nil && c.Idempotency.TTL > 0 {
+		instance.ttl = c.Idempotency.TTL
+	}
+	return
+}
+
+// Mark records key as processed by handler, marking it within the transaction of the work
+// makes both happen or neither. repositories.ErrMessageProcessed is returned for a duplicate.
+func (idempotency *idempotency) Mark(ctx context.Context, handler, key string) (err error) {
+	return idempotency.processed.Create(ctx, handler, key, time.Now().Add(idempotency.ttl))
+}
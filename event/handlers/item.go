@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/event"
 	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/persistence/repositories"
 	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/transactions"
 )
 
 type ItemHandler interface {
@@ -16,8 +18,10 @@ type ItemHandler interface {
 }
 
 type itemHandler struct {
-	logger log.Logger
-	item   services.ItemService
+	logger      log.Logger
+	item        services.ItemService
+	order       services.OrderService
+	idempotency *idempotency
 }
 
 func NewItemHandler() (handler ItemHandler, err error) {
@@ -29,39 +33,85 @@ func NewItemHandler() (handler ItemHandler, err error) {
 	if err != nil {
 		return nil, err
 	}
+	idempotency, err := newIdempotency()
+	if err != nil {
+		return nil, err
+	}
 	return &itemHandler{
-		item:   service.Item,
-		logger: logger,
+		item:        service.Item,
+		order:       service.Order,
+		logger:      logger,
+		idempotency: idempotency,
 	}, nil
 }
 
 func (handler *itemHandler) ListenDeliverItem(msg *message.Message) (err error) {
-	fmt.Printf("received message: %s, payload: %s \n", msg.UUID, string(msg.Payload))
+	handler.logger.InfoF("received message: %s, payload: %s", msg.UUID, string(msg.Payload))
 	var m messages.DeliverItemMessage
-	err = json.Unmarshal(msg.Payload, &m)
+	envelope, err := messages.Open(event.DeliverItem, msg.Payload, &m)
 	if err != nil {
-		return
+		return permanent(err)
 	}
-	item, err := handler.item.DeliverItem(context.Background(), services.DeliverItemDto{
-		Contract:   m.Contract,
-		Token:      m.Token,
-		CreationId: m.CreationId,
-	})
+	txn, err := transactions.NewTransaction("ListenDeliverItem")
 	if err != nil {
-		handler.logger.Error(err)
+		return
+	}
+	defer txn.End(context.Background())
+
+	// a token is delivered once, whichever message brings it
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		err := handler.idempotency.Mark(transactionCtx, "DeliverItem", m.Contract+":"+m.Token)
+		if err != nil {
+			return nil, err
+		}
+		return handler.item.DeliverItem(transactionCtx, services.DeliverItemDto{
+			OrderId:    m.OrderId,
+			Contract:   m.Contract,
+			Token:      m.Token,
+			CreationId: m.CreationId,
+		})
+	}
+	result, err := txn.With(messages.WithCause(context.Background(), envelope), callback)
+	if errors.Is(err, repositories.ErrMessageProcessed) {
+		handler.logger.InfoF("skip delivered item: contract(%s), token(%s)", m.Contract, m.Token)
 		return nil
 	}
-	fmt.Printf("received item:\n contract(%s),\n token(%s),\n", item.Contract, item.Token)
+	if err != nil {
+		return failed(err)
+	}
+	item := result.(*services.ItemDto)
+	handler.logger.InfoF("received item: contract(%s), token(%s)", item.Contract, item.Token)
 	return
 }
 
 func (handler *itemHandler) ListenOrderItem(msg *message.Message) (err error) {
-	fmt.Printf("received message: %s, payload: %s \n", msg.UUID, string(msg.Payload))
+	handler.logger.InfoF("received message: %s, payload: %s", msg.UUID, string(msg.Payload))
 	var m messages.OrderItemMessage
-	err = json.Unmarshal(msg.Payload, &m)
+	envelope, err := messages.Open(event.OrderItem, msg.Payload, &m)
+	if err != nil {
+		return permanent(err)
+	}
+	handler.logger.InfoF("order item: orderId(%s), creationId(%s), amount(%d)", m.OrderId, m.CreationId, m.Amount)
+	txn, err := transactions.NewTransaction("ListenOrderItem")
 	if err != nil {
 		return
 	}
-	fmt.Printf("order item:\n creationId(%s),\n amount(%d),\n", m.CreationId, m.Amount)
+	defer txn.End(context.Background())
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		err := handler.idempotency.Mark(transactionCtx, "OrderItem", m.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		return nil, handler.order.MintOrder(transactionCtx, m.OrderId)
+	}
+	_, err = txn.With(messages.WithCause(context.Background(), envelope), callback)
+	if errors.Is(err, repositories.ErrMessageProcessed) {
+		handler.logger.InfoF("skip ordered item: orderId(%s)", m.OrderId)
+		return nil
+	}
+	if err != nil {
+		return failed(err)
+	}
 	return
 }
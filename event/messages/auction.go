@@ -0,0 +1,8 @@
+package messages
+
+type AuctionLostMessage struct {
+	CreationId    string `json:"creationId"`
+	Bidder        string `json:"bidder"`
+	HighestPrice  int    `json:"highestPrice"`
+	IsReserveMiss bool   `json:"isReserveMiss"`
+}
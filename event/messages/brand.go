@@ -0,0 +1,37 @@
+package messages
+
+import "time"
+
+type BrandCreatedMessage struct {
+	BrandId     string    `json:"brandId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ImageUrl    string    `json:"imageUrl"`
+	RoyaltyBps  int       `json:"royaltyBps"`
+	Owner       string    `json:"owner"`
+	CreateAt    time.Time `json:"createAt"`
+}
+
+type BrandUpdatedMessage struct {
+	BrandId     string    `json:"brandId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ImageUrl    string    `json:"imageUrl"`
+	RoyaltyBps  int       `json:"royaltyBps"`
+	UpdateAt    time.Time `json:"updateAt"`
+}
+
+type BrandDeletedMessage struct {
+	BrandId  string    `json:"brandId"`
+	DeleteAt time.Time `json:"deleteAt"`
+}
+
+// BrandMemberMessage tells a change of membership, From is empty for a member added
+// and To is empty for a member removed.
+type BrandMemberMessage struct {
+	BrandId  string    `json:"brandId"`
+	Member   string    `json:"member"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	ChangeAt time.Time `json:"changeAt"`
+}
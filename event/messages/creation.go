@@ -0,0 +1,45 @@
+package messages
+
+import "time"
+
+type CreationSaleStatusMessage struct {
+	CreationId string    `json:"creationId"`
+	BrandId    string    `json:"brandId"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	ChangeAt   time.Time `json:"changeAt"`
+}
+
+type CreationCreatedMessage struct {
+	CreationId      string    `json:"creationId"`
+	CreationName    string    `json:"creationName"`
+	BrandId         string    `json:"brandId"`
+	Creator         string    `json:"creator"`
+	Amount          int       `json:"amount"`
+	Price           int       `json:"price"`
+	RoyaltyBps      int       `json:"royaltyBps"`
+	Properties      []string  `json:"properties"`
+	SaleWay         string    `json:"saleWay"`
+	SaleStatus      string    `json:"saleStatus"`
+	SaleStartAt     time.Time `json:"saleStartAt"`
+	SaleEndAt       time.Time `json:"saleEndAt"`
+	ContractAddress string    `json:"contractAddress"`
+	CreateAt        time.Time `json:"createAt"`
+}
+
+type CreationUpdatedMessage struct {
+	CreationId   string    `json:"creationId"`
+	BrandId      string    `json:"brandId"`
+	CreationName string    `json:"creationName"`
+	Description  string    `json:"description"`
+	RoyaltyBps   int       `json:"royaltyBps"`
+	SaleStartAt  time.Time `json:"saleStartAt"`
+	SaleEndAt    time.Time `json:"saleEndAt"`
+	UpdateAt     time.Time `json:"updateAt"`
+}
+
+type CreationDeletedMessage struct {
+	CreationId string    `json:"creationId"`
+	BrandId    string    `json:"brandId"`
+	DeleteAt   time.Time `json:"deleteAt"`
+}
@@ -0,0 +1,35 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const Producer = "nftshopping-store-api"
+
+// Envelope wraps the data of every event with what a consumer needs to read it,
+// the version tells which schema the data was written in.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Producer      string          `json:"producer"`
+	CorrelationID string          `json:"correlationId"`
+	CausationID   string          `json:"causationId,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+type causeKey struct{}
+
+// WithCause makes the events published within ctx caused by envelope,
+// they are correlated the same as it.
+func WithCause(ctx context.Context, envelope Envelope) context.Context {
+	return context.WithValue(ctx, causeKey{}, envelope)
+}
+
+func causeOf(ctx context.Context) (envelope Envelope, ok bool) {
+	envelope, ok = ctx.Value(causeKey{}).(Envelope)
+	return
+}
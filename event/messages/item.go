@@ -1,13 +1,23 @@
 package messages
 
 type DeliverItemMessage struct {
+	OrderId    string `json:"orderId"`
 	CreationId string `json:"creationId"`
 	Contract   string `json:"contract"`
 	Token      string `json:"token"`
 }
 
 type OrderItemMessage struct {
+	OrderId    string `json:"orderId"`
 	CreationId string `json:"creationId"`
 	Contract   string `json:"contract"`
 	Amount     int    `json:"amount"`
 }
+
+type ItemDeliveredMessage struct {
+	OrderId    string `json:"orderId"`
+	CreationId string `json:"creationId"`
+	Contract   string `json:"contract"`
+	Token      string `json:"token"`
+	Owner      string `json:"owner"`
+}
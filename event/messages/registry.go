@@ -0,0 +1,151 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/pkg/schemas"
+	"reflect"
+	"time"
+)
+
+var (
+	ErrTopicUnregistered  = errors.New("topic is not registered")
+	ErrMessageMismatch    = errors.New("message does not match topic")
+	ErrVersionUnsupported = errors.New("event version is not supported")
+)
+
+// Upcaster rewrites the data of an event from one version to the next,
+// so a consumer can still read the events of an older producer.
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+// Schema is what the events of a topic are, the version is the one published now.
+type Schema struct {
+	Topic     string          `json:"topic"`
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	Schema    *schemas.Schema `json:"schema"`
+	message   reflect.Type
+	upcasters map[int]Upcaster
+}
+
+var registry = newRegistry()
+
+// newRegistry lists every topic with its message, bumping the version of a message
+// needs an upcaster from the version before.
+func newRegistry() (instance map[string]*Schema) {
+	instance = map[string]*Schema{}
+	register(instance, event.DeliverItem, "DeliverItem", 1, DeliverItemMessage{})
+	register(instance, event.OrderItem, "OrderItem", 1, OrderItemMessage{})
+	register(instance, event.ItemDelivered, "ItemDelivered", 1, ItemDeliveredMessage{})
+	register(instance, event.CreationSaleStatusChanged, "CreationSaleStatusChanged", 1, CreationSaleStatusMessage{})
+	register(instance, event.AuctionLost, "AuctionLost", 1, AuctionLostMessage{})
+	register(instance, event.BrandCreated, "BrandCreated", 1, BrandCreatedMessage{})
+	register(instance, event.BrandUpdated, "BrandUpdated", 1, BrandUpdatedMessage{})
+	register(instance, event.BrandDeleted, "BrandDeleted", 1, BrandDeletedMessage{})
+	register(instance, event.BrandMemberAdded, "BrandMemberAdded", 1, BrandMemberMessage{})
+	register(instance, event.BrandMemberUpdated, "BrandMemberUpdated", 1, BrandMemberMessage{})
+	register(instance, event.BrandMemberRemoved, "BrandMemberRemoved", 1, BrandMemberMessage{})
+	register(instance, event.CreationCreated, "CreationCreated", 1, CreationCreatedMessage{})
+	register(instance, event.CreationUpdated, "CreationUpdated", 1, CreationUpdatedMessage{})
+	register(instance, event.CreationDeleted, "CreationDeleted", 1, CreationDeletedMessage{})
+	register(instance, event.UserRegistered, "UserRegistered", 1, UserRegisteredMessage{})
+	register(instance, event.UserDeleted, "UserDeleted", 1, UserDeletedMessage{})
+	register(instance, event.TradeSettled, "TradeSettled", 1, TradeSettledMessage{})
+	return
+}
+
+func register(registry map[string]*Schema, topic, eventType string, version int, msg interface{}, upcasters ...Upcaster) {
+	schema := &Schema{
+		Topic:     topic,
+		Type:      eventType,
+		Version:   version,
+		Schema:    schemas.Of(msg),
+		message:   reflect.TypeOf(msg),
+		upcasters: map[int]Upcaster{},
+	}
+	// upcasters are given from the first version on
+	for i, upcaster := range upcasters {
+		schema.upcasters[i+1] = upcaster
+	}
+	registry[topic] = schema
+}
+
+func SchemaOf(topic string) (schema *Schema, err error) {
+	schema, ok := registry[topic]
+	if !ok {
+		return nil, ErrTopicUnregistered
+	}
+	return
+}
+
+// Seal validates msg against the schema of topic and wraps it in an envelope with id,
+// the envelope is caused by the one carried in ctx if there is.
+func Seal(ctx context.Context, topic, id string, msg interface{}) (payload []byte, envelope Envelope, err error) {
+	schema, err := SchemaOf(topic)
+	if err != nil {
+		return
+	}
+	if reflect.TypeOf(msg) != schema.message {
+		return nil, envelope, ErrMessageMismatch
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err = schema.Schema.Validate(data); err != nil {
+		return
+	}
+	envelope = Envelope{
+		ID:            id,
+		Type:          schema.Type,
+		Version:       schema.Version,
+		OccurredAt:    time.Now(),
+		Producer:      Producer,
+		CorrelationID: id,
+		Data:          data,
+	}
+	if cause, ok := causeOf(ctx); ok {
+		envelope.CorrelationID = cause.CorrelationID
+		envelope.CausationID = cause.ID
+	}
+	payload, err = json.Marshal(envelope)
+	return
+}
+
+// Open reads the envelope of payload into msg, upcasting the data of an older version first.
+// A payload without envelope is taken as the data of the first version, as it was published
+// before the envelope.
+func Open(topic string, payload []byte, msg interface{}) (envelope Envelope, err error) {
+	schema, err := SchemaOf(topic)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(payload, &envelope); err != nil {
+		return
+	}
+	if len(envelope.Type) == 0 {
+		envelope = Envelope{Type: schema.Type, Version: 1, Data: payload}
+	}
+	if envelope.Type != schema.Type {
+		return envelope, ErrMessageMismatch
+	}
+	if envelope.Version > schema.Version {
+		return envelope, ErrVersionUnsupported
+	}
+	for ; envelope.Version < schema.Version; envelope.Version++ {
+		upcaster, ok := schema.upcasters[envelope.Version]
+		if !ok {
+			return envelope, ErrVersionUnsupported
+		}
+		if envelope.Data, err = upcaster(envelope.Data); err != nil {
+			return
+		}
+	}
+	if err = schema.Schema.Validate(envelope.Data); err != nil {
+		return
+	}
+	err = json.Unmarshal(envelope.Data, msg)
+	return
+}
@@ -0,0 +1,102 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"nftshopping-store-api/pkg/schemas"
+	"testing"
+)
+
+const priceChanged = "test.priceChanged"
+
+// priceChangedMessage is the second version, the first one had the price as a string
+// named amount.
+type priceChangedMessage struct {
+	CreationId string  `json:"creationId"`
+	Price      float64 `json:"price"`
+}
+
+func priceChangedV1toV2(data json.RawMessage) (json.RawMessage, error) {
+	var v1 struct {
+		CreationId string      `json:"creationId"`
+		Amount     json.Number `json:"amount"`
+	}
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return nil, err
+	}
+	price, err := v1.Amount.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(priceChangedMessage{CreationId: v1.CreationId, Price: price})
+}
+
+func init() {
+	register(registry, priceChanged, "PriceChanged", 2, priceChangedMessage{}, priceChangedV1toV2)
+}
+
+func TestOpenUpcast(t *testing.T) {
+	payload := []byte(`{"id":"e1","type":"PriceChanged","version":1,"data":{"creationId":"c1","amount":"1.5"}}`)
+	var msg priceChangedMessage
+	envelope, err := Open(priceChanged, payload, &msg)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if envelope.Version != 2 || envelope.ID != "e1" {
+		t.Errorf("envelope = %+v", envelope)
+	}
+	if msg.CreationId != "c1" || msg.Price != 1.5 {
+		t.Errorf("msg = %+v", msg)
+	}
+}
+
+func TestOpenLegacy(t *testing.T) {
+	var msg priceChangedMessage
+	if _, err := Open(priceChanged, []byte(`{"creationId":"c1","amount":"2"}`), &msg); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if msg.CreationId != "c1" || msg.Price != 2 {
+		t.Errorf("msg = %+v", msg)
+	}
+}
+
+func TestOpenRejected(t *testing.T) {
+	for name, test := range map[string]struct {
+		payload string
+		err     error
+	}{
+		"newer version":   {`{"type":"PriceChanged","version":3,"data":{}}`, ErrVersionUnsupported},
+		"other type":      {`{"type":"PriceDropped","version":2,"data":{}}`, ErrMessageMismatch},
+		"schema mismatch": {`{"type":"PriceChanged","version":2,"data":{"creationId":"c1","price":"1"}}`, schemas.ErrSchemaMismatch},
+	} {
+		var msg priceChangedMessage
+		if _, err := Open(priceChanged, []byte(test.payload), &msg); !errors.Is(err, test.err) {
+			t.Errorf("%s: error = %v, want %v", name, err, test.err)
+		}
+	}
+	if _, err := Open("test.unknown", []byte(`{}`), &priceChangedMessage{}); err != ErrTopicUnregistered {
+		t.Errorf("unknown topic: error = %v, want %v", err, ErrTopicUnregistered)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	cause := Envelope{ID: "cause", CorrelationID: "root"}
+	payload, sealed, err := Seal(WithCause(context.Background(), cause), priceChanged, "e2", priceChangedMessage{CreationId: "c2", Price: 3})
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if sealed.Version != 2 || sealed.CorrelationID != "root" || sealed.CausationID != "cause" {
+		t.Errorf("sealed = %+v", sealed)
+	}
+	var msg priceChangedMessage
+	if _, err = Open(priceChanged, payload, &msg); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if msg.CreationId != "c2" || msg.Price != 3 {
+		t.Errorf("msg = %+v", msg)
+	}
+	if _, _, err = Seal(context.Background(), priceChanged, "e3", CreationDeletedMessage{}); err != ErrMessageMismatch {
+		t.Errorf("Seal() error = %v, want %v", err, ErrMessageMismatch)
+	}
+}
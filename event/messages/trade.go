@@ -0,0 +1,31 @@
+package messages
+
+import "time"
+
+// TradeSettledMessage is sent for every transaction, whether sold from the creation,
+// a listing or an offer.
+type TradeSettledMessage struct {
+	TransactionId string       `json:"transactionId"`
+	CreationId    string       `json:"creationId"`
+	BrandId       string       `json:"brandId"`
+	Buyer         string       `json:"buyer"`
+	Seller        string       `json:"seller"`
+	Amount        int          `json:"amount"`
+	Price         int          `json:"price"`
+	Items         []TradedItem `json:"items"`
+	Fee           TradeFee     `json:"fee"`
+	TradeAt       time.Time    `json:"tradeAt"`
+}
+
+type TradedItem struct {
+	Contract string `json:"contract"`
+	Token    string `json:"token"`
+}
+
+type TradeFee struct {
+	SellerProceeds int    `json:"sellerProceeds"`
+	Creator        string `json:"creator"`
+	CreatorRoyalty int    `json:"creatorRoyalty"`
+	BrandRoyalty   int    `json:"brandRoyalty"`
+	PlatformFee    int    `json:"platformFee"`
+}
@@ -0,0 +1,14 @@
+package messages
+
+import "time"
+
+type UserRegisteredMessage struct {
+	UserId     string    `json:"userId"`
+	Account    string    `json:"account"`
+	RegisterAt time.Time `json:"registerAt"`
+}
+
+type UserDeletedMessage struct {
+	UserId   string    `json:"userId"`
+	DeleteAt time.Time `json:"deleteAt"`
+}
@@ -0,0 +1,29 @@
+package publishers
+
+import (
+	"context"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/messages"
+)
+
+type AuctionPublisher interface {
+	PublishToAuctionLost(ctx context.Context, msg messages.AuctionLostMessage) (err error)
+}
+
+type auctionPublisher struct {
+	outbox *outbox
+}
+
+func NewAuctionPublisher() (AuctionPublisher, error) {
+	outbox, err := newOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return &auctionPublisher{
+		outbox: outbox,
+	}, nil
+}
+
+func (publisher *auctionPublisher) PublishToAuctionLost(ctx context.Context, msg messages.AuctionLostMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.AuctionLost, AggregateAuction, msg.CreationId, msg)
+}
@@ -0,0 +1,54 @@
+package publishers
+
+import (
+	"context"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/messages"
+)
+
+type BrandPublisher interface {
+	PublishToBrandCreated(ctx context.Context, msg messages.BrandCreatedMessage) (err error)
+	PublishToBrandUpdated(ctx context.Context, msg messages.BrandUpdatedMessage) (err error)
+	PublishToBrandDeleted(ctx context.Context, msg messages.BrandDeletedMessage) (err error)
+	PublishToBrandMemberAdded(ctx context.Context, msg messages.BrandMemberMessage) (err error)
+	PublishToBrandMemberUpdated(ctx context.Context, msg messages.BrandMemberMessage) (err error)
+	PublishToBrandMemberRemoved(ctx context.Context, msg messages.BrandMemberMessage) (err error)
+}
+
+type brandPublisher struct {
+	outbox *outbox
+}
+
+func NewBrandPublisher() (BrandPublisher, error) {
+	outbox, err := newOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return &brandPublisher{
+		outbox: outbox,
+	}, nil
+}
+
+func (publisher *brandPublisher) PublishToBrandCreated(ctx context.Context, msg messages.BrandCreatedMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.BrandCreated, AggregateBrand, msg.BrandId, msg)
+}
+
+func (publisher *brandPublisher) PublishToBrandUpdated(ctx context.Context, msg messages.BrandUpdatedMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.BrandUpdated, AggregateBrand, msg.BrandId, msg)
+}
+
+func (publisher *brandPublisher) PublishToBrandDeleted(ctx context.Context, msg messages.BrandDeletedMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.BrandDeleted, AggregateBrand, msg.BrandId, msg)
+}
+
+func (publisher *brandPublisher) PublishToBrandMemberAdded(ctx context.Context, msg messages.BrandMemberMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.BrandMemberAdded, AggregateBrand, msg.BrandId, msg)
+}
+
+func (publisher *brandPublisher) PublishToBrandMemberUpdated(ctx context.Context, msg messages.BrandMemberMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.BrandMemberUpdated, AggregateBrand, msg.BrandId, msg)
+}
+
+func (publisher *brandPublisher) PublishToBrandMemberRemoved(ctx context.Context, msg messages.BrandMemberMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.BrandMemberRemoved, AggregateBrand, msg.BrandId, msg)
+}
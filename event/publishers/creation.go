@@ -0,0 +1,52 @@
+package publishers
+
+import (
+	"context"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/messages"
+)
+
+type CreationPublisher interface {
+	PublishToSaleStatusChanged(ctx context.Context, msg messages.CreationSaleStatusMessage) (err error)
+	PublishToCreationCreated(ctx context.Context, msg messages.CreationCreatedMessage) (err error)
+	PublishToCreationUpdated(ctx context.Context, msg messages.CreationUpdatedMessage) (err error)
+	PublishToCreationDeleted(ctx context.Context, msg messages.CreationDeletedMessage) (err error)
+}
+
+type creationPublisher struct {
+	outbox *outbox
+}
+
+func NewCreationPublisher() (CreationPublisher, error) {
+	outbox, err := newOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return &creationPublisher{
+		outbox: outbox,
+	}, nil
+}
+
+func (publisher *creationPublisher) PublishToSaleStatusChanged(
+	ctx context.Context, msg messages.CreationSaleStatusMessage,
+) (err error) {
+	return publisher.outbox.publish(ctx, event.CreationSaleStatusChanged, AggregateCreation, msg.CreationId, msg)
+}
+
+func (publisher *creationPublisher) PublishToCreationCreated(
+	ctx context.Context, msg messages.CreationCreatedMessage,
+) (err error) {
+	return publisher.outbox.publish(ctx, event.CreationCreated, AggregateCreation, msg.CreationId, msg)
+}
+
+func (publisher *creationPublisher) PublishToCreationUpdated(
+	ctx context.Context, msg messages.CreationUpdatedMessage,
+) (err error) {
+	return publisher.outbox.publish(ctx, event.CreationUpdated, AggregateCreation, msg.CreationId, msg)
+}
+
+func (publisher *creationPublisher) PublishToCreationDeleted(
+	ctx context.Context, msg messages.CreationDeletedMessage,
+) (err error) {
+	return publisher.outbox.publish(ctx, event.CreationDeleted, AggregateCreation, msg.CreationId, msg)
+}
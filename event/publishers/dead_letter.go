@@ -0,0 +1,37 @@
+package publishers
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MetadataReplayOf marks a replayed message with the id of the dead letter it came from.
+const MetadataReplayOf = "replay_of"
+
+type DeadLetterPublisher interface {
+	Replay(ctx context.Context, deadLetterId, topic, aggregateType, aggregateId string, payload []byte) (err error)
+}
+
+type deadLetterPublisher struct {
+	outbox *outbox
+}
+
+func NewDeadLetterPublisher() (DeadLetterPublisher, error) {
+	outbox, err := newOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetterPublisher{
+		outbox: outbox,
+	}, nil
+}
+
+// Replay sends the payload of a dead letter to its topic again through the outbox,
+// the replayed message gets a new uuid so handlers keyed by uuid process it.
+func (publisher *deadLetterPublisher) Replay(
+	ctx context.Context, deadLetterId, topic, aggregateType, aggregateId string, payload []byte,
+) (err error) {
+	return publisher.outbox.write(ctx, primitive.NewObjectID(), topic, aggregateType, aggregateId, payload, map[string]string{
+		MetadataReplayOf: deadLetterId,
+	})
+}
@@ -1,55 +1,42 @@
 package publishers
 
 import (
-	"encoding/json"
-	"github.com/ThreeDotsLabs/watermill"
-	"github.com/ThreeDotsLabs/watermill-amqp/pkg/amqp"
-	"github.com/ThreeDotsLabs/watermill/message"
+	"context"
 	"nftshopping-store-api/event"
 	"nftshopping-store-api/event/messages"
-	"nftshopping-store-api/pkg/pubsubs"
 )
 
 type ItemPublisher interface {
-	PublishToOrderItem(msg messages.OrderItemMessage) (err error)
-	PublishToDeliverItem(msg messages.DeliverItemMessage) (err error)
+	PublishToOrderItem(ctx context.Context, msg messages.OrderItemMessage) (err error)
+	PublishToDeliverItem(ctx context.Context, msg messages.DeliverItemMessage) (err error)
+	PublishToItemDelivered(ctx context.Context, msg messages.ItemDeliveredMessage) (err error)
 }
 
 type itemPublisher struct {
-	pub *amqp.Publisher
+	outbox *outbox
 }
 
 func NewItemPublisher() (ItemPublisher, error) {
-	pub, err := pubsubs.GetPub()
+	outbox, err := newOutbox()
 	if err != nil {
 		return nil, err
 	}
 	return &itemPublisher{
-		pub: pub,
+		outbox: outbox,
 	}, nil
 }
 
-func (publisher *itemPublisher) PublishToOrderItem(msg messages.OrderItemMessage) (err error) {
-	msgByte, err := json.Marshal(msg)
-	if err != nil {
-		return
-	}
-	m := message.NewMessage(watermill.NewUUID(), msgByte)
-	if err := publisher.pub.Publish(event.OrderItem, m); err != nil {
-		return err
-	}
-	return
+func (publisher *itemPublisher) PublishToOrderItem(ctx context.Context, msg messages.OrderItemMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.OrderItem, AggregateOrder, msg.OrderId, msg)
 }
 
-func (publisher *itemPublisher) PublishToDeliverItem(msg messages.DeliverItemMessage) (err error) {
+func (publisher *itemPublisher) PublishToDeliverItem(ctx context.Context, msg messages.DeliverItemMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.DeliverItem, AggregateOrder, msg.OrderId, msg)
+}
 
-	msgByte, err := json.Marshal(msg)
-	if err != nil {
-		return
-	}
-	m := message.NewMessage(watermill.NewUUID(), msgByte)
-	if err := publisher.pub.Publish(event.DeliverItem, m); err != nil {
-		return err
-	}
-	return
+// PublishToItemDelivered keeps the events of an item in order by its contract and token.
+func (publisher *itemPublisher) PublishToItemDelivered(
+	ctx context.Context, msg messages.ItemDeliveredMessage,
+) (err error) {
+	return publisher.outbox.publish(ctx, event.ItemDelivered, AggregateItem, msg.Contract+":"+msg.Token, msg)
 }
@@ -0,0 +1,65 @@
+package publishers
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/event/messages"
+	"nftshopping-store-api/persistence/repositories"
+	"time"
+)
+
+const (
+	AggregateOrder    = "order"
+	AggregateItem     = "item"
+	AggregateCreation = "creation"
+	AggregateAuction  = "auction"
+	AggregateBrand    = "brand"
+	AggregateUser     = "user"
+	AggregateTrade    = "trade"
+)
+
+// outbox writes an event with the session carried by ctx, so it is only relayed to the
+// broker once the change it tells about has committed.
+type outbox struct {
+	dao repositories.OutboxDao
+}
+
+func newOutbox() (instance *outbox, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return
+	}
+	return &outbox{dao: dao.Outbox}, nil
+}
+
+func (outbox *outbox) publish(
+	ctx context.Context, topic, aggregateType, aggregateId string, msg interface{},
+) (err error) {
+	id := primitive.NewObjectID()
+	payload, envelope, err := messages.Seal(ctx, topic, id.Hex(), msg)
+	if err != nil {
+		return
+	}
+	return outbox.write(ctx, id, topic, aggregateType, aggregateId, payload, map[string]string{
+		middleware.CorrelationIDMetadataKey: envelope.CorrelationID,
+	})
+}
+
+func (outbox *outbox) write(
+	ctx context.Context, id primitive.ObjectID, topic, aggregateType, aggregateId string,
+	payload []byte, metadata map[string]string,
+) (err error) {
+	now := time.Now()
+	return outbox.dao.Create(ctx, &repositories.Outbox{
+		ID:            id,
+		Topic:         topic,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateId,
+		Payload:       payload,
+		Metadata:      metadata,
+		Status:        repositories.OutboxPending,
+		NextAttemptAt: now,
+		CreateAt:      now,
+	})
+}
@@ -14,7 +14,13 @@ func GetPublisher() (instance *publisher, err error) {
 }
 
 type publisher struct {
-	Item ItemPublisher
+	Item       ItemPublisher
+	Creation   CreationPublisher
+	Auction    AuctionPublisher
+	DeadLetter DeadLetterPublisher
+	Brand      BrandPublisher
+	User       UserPublisher
+	Trade      TradePublisher
 }
 
 func newPublisher() (instance *publisher, err error) {
@@ -22,8 +28,38 @@ func newPublisher() (instance *publisher, err error) {
 	if err != nil {
 		return
 	}
+	creation, err := NewCreationPublisher()
+	if err != nil {
+		return
+	}
+	auction, err := NewAuctionPublisher()
+	if err != nil {
+		return
+	}
+	deadLetter, err := NewDeadLetterPublisher()
+	if err != nil {
+		return
+	}
+	brand, err := NewBrandPublisher()
+	if err != nil {
+		return
+	}
+	user, err := NewUserPublisher()
+	if err != nil {
+		return
+	}
+	trade, err := NewTradePublisher()
+	if err != nil {
+		return
+	}
 
 	return &publisher{
-		Item: item,
+		Item:       item,
+		Creation:   creation,
+		Auction:    auction,
+		DeadLetter: deadLetter,
+		Brand:      brand,
+		User:       user,
+		Trade:      trade,
 	}, nil
 }
@@ -0,0 +1,29 @@
+package publishers
+
+import (
+	"context"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/messages"
+)
+
+type TradePublisher interface {
+	PublishToTradeSettled(ctx context.Context, msg messages.TradeSettledMessage) (err error)
+}
+
+type tradePublisher struct {
+	outbox *outbox
+}
+
+func NewTradePublisher() (TradePublisher, error) {
+	outbox, err := newOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return &tradePublisher{
+		outbox: outbox,
+	}, nil
+}
+
+func (publisher *tradePublisher) PublishToTradeSettled(ctx context.Context, msg messages.TradeSettledMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.TradeSettled, AggregateTrade, msg.TransactionId, msg)
+}
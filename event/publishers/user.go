@@ -0,0 +1,34 @@
+package publishers
+
+import (
+	"context"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/messages"
+)
+
+type UserPublisher interface {
+	PublishToUserRegistered(ctx context.Context, msg messages.UserRegisteredMessage) (err error)
+	PublishToUserDeleted(ctx context.Context, msg messages.UserDeletedMessage) (err error)
+}
+
+type userPublisher struct {
+	outbox *outbox
+}
+
+func NewUserPublisher() (UserPublisher, error) {
+	outbox, err := newOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return &userPublisher{
+		outbox: outbox,
+	}, nil
+}
+
+func (publisher *userPublisher) PublishToUserRegistered(ctx context.Context, msg messages.UserRegisteredMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.UserRegistered, AggregateUser, msg.UserId, msg)
+}
+
+func (publisher *userPublisher) PublishToUserDeleted(ctx context.Context, msg messages.UserDeletedMessage) (err error) {
+	return publisher.outbox.publish(ctx, event.UserDeleted, AggregateUser, msg.UserId, msg)
+}
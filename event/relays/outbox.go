@@ -0,0 +1,220 @@
+package relays
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"nftshopping-store-api/persistence/repositories"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/pubsubs"
+	"time"
+)
+
+const (
+	MetadataAggregateType = "aggregate_type"
+	MetadataAggregateID   = "aggregate_id"
+)
+
+var relayInstance *outboxRelay
+
+func GetRelay() (instance *outboxRelay, err error) {
+	if relayInstance == nil {
+		instance, err = newOutboxRelay()
+		if err != nil {
+			return nil, err
+		}
+		relayInstance = instance
+	}
+	return relayInstance, nil
+}
+
+// outboxRelay hands the events of the outbox to the broker at least once. Events of the
+// same aggregate go out in the order they were written, a failed one holds back the rest
+// of its aggregate until it is published or parked after too many attempts.
+type outboxRelay struct {
+	logger  log.Logger
+	outbox  repositories.OutboxDao
+	pub     message.Publisher
+	config  config.Outbox
+	owner   string
+	cleanAt time.Time
+}
+
+func newOutboxRelay() (instance *outboxRelay, err error) {
+	logger, err := log.GetLog()
+	if err != nil {
+		return
+	}
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return
+	}
+	pub, err := pubsubs.GetPub()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	owner := make([]byte, 8)
+	if _, err = rand.Read(owner); err != nil {
+		return
+	}
+	instance = &outboxRelay{
+		logger: logger,
+		outbox: dao.Outbox,
+		pub:    pub,
+		config: config.Outbox{
+			PollInterval:     time.Second,
+			BatchSize:        100,
+			RetryInterval:    time.Second,
+			MaxRetryInterval: 5 * time.Minute,
+			MaxAttempts:      20,
+			LeaseTimeout:     30 * time.Second,
+			Retention:        72 * time.Hour,
+			CleanupInterval:  time.Hour,
+		},
+		owner: hex.EncodeToString(owner),
+	}
+	if c.Outbox != nil {
+		instance.configure(*c.Outbox)
+	}
+	return instance, nil
+}
+
+// configure takes the settings given, the defaults stay for the ones left out.
+func (relay *outboxRelay) configure(c config.Outbox) {
+	if c.PollInterval > 0 {
+		relay.config.PollInterval = c.PollInterval
+	}
+	if c.BatchSize > 0 {
+		relay.config.BatchSize = c.BatchSize
+	}
+	if c.RetryInterval > 0 {
+		relay.config.RetryInterval = c.RetryInterval
+	}
+	if c.MaxRetryInterval > 0 {
+		relay.config.MaxRetryInterval = c.MaxRetryInterval
+	}
+	if c.MaxAttempts > 0 {
+		relay.config.MaxAttempts = c.MaxAttempts
+	}
+	if c.LeaseTimeout > 0 {
+		relay.config.LeaseTimeout = c.LeaseTimeout
+	}
+	if c.Retention > 0 {
+		relay.config.Retention = c.Retention
+	}
+	if c.CleanupInterval > 0 {
+		relay.config.CleanupInterval = c.CleanupInterval
+	}
+}
+
+func (relay *outboxRelay) Run(ctx context.Context) (err error) {
+	ticker := time.NewTicker(relay.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := relay.relay(ctx); err != nil {
+			relay.logger.Error(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay drains the due events of the outbox, only the instance holding the lease does it.
+// Every batch carries the head of each aggregate, so batches go on while they move some
+// aggregate forward.
+func (relay *outboxRelay) relay(ctx context.Context) (err error) {
+	now := time.Now()
+	acquired, err := relay.outbox.AcquireLease(ctx, relay.owner, now.Add(relay.config.LeaseTimeout))
+	if err != nil || !acquired {
+		return
+	}
+	for ctx.Err() == nil {
+		settled, err := relay.relayBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if settled == 0 {
+			break
+		}
+		acquired, err = relay.outbox.AcquireLease(ctx, relay.owner, time.Now().Add(relay.config.LeaseTimeout))
+		if err != nil || !acquired {
+			return err
+		}
+	}
+	if now.Sub(relay.cleanAt) >= relay.config.CleanupInterval {
+		relay.cleanAt = now
+		amount, err := relay.outbox.DeletePublishedBefore(ctx, now.Add(-relay.config.Retention))
+		if err != nil {
+			return err
+		}
+		if amount > 0 {
+			relay.logger.InfoF("cleaned %d relayed event(s)", amount)
+		}
+	}
+	return
+}
+
+// relayBatch publishes the due head of each aggregate, settled counts the events that
+// left the pending state, published or parked.
+func (relay *outboxRelay) relayBatch(ctx context.Context) (settled int, err error) {
+	outboxes, err := relay.outbox.FindAllPending(ctx, time.Now(), relay.config.BatchSize)
+	if err != nil {
+		return
+	}
+	for _, outbox := range outboxes {
+		aggregate := outbox.AggregateType + "/" + outbox.AggregateID
+		if publishErr := relay.publish(outbox); publishErr != nil {
+			if outbox.Attempts+1 >= relay.config.MaxAttempts {
+				if err = relay.outbox.MarkParked(ctx, outbox.ID, publishErr.Error()); err != nil {
+					return
+				}
+				settled++
+				relay.logger.ErrorF("park event %s of %s after %d attempts: %v",
+					outbox.ID.Hex(), aggregate, outbox.Attempts+1, publishErr)
+				continue
+			}
+			next := time.Now().Add(relay.backoffOf(outbox.Attempts))
+			if err = relay.outbox.MarkFailed(ctx, outbox.ID, publishErr.Error(), next); err != nil {
+				return
+			}
+			relay.logger.WarnF("fail to relay event %s of %s: %v", outbox.ID.Hex(), aggregate, publishErr)
+			continue
+		}
+		if err = relay.outbox.MarkPublished(ctx, outbox.ID, time.Now()); err != nil {
+			return
+		}
+		settled++
+	}
+	return
+}
+
+// publish uses the id of the outbox as the uuid, so a redelivered event can be told apart.
+func (relay *outboxRelay) publish(outbox repositories.Outbox) (err error) {
+	msg := message.NewMessage(outbox.ID.Hex(), outbox.Payload)
+	for key, value := range outbox.Metadata {
+		msg.Metadata.Set(key, value)
+	}
+	msg.Metadata.Set(MetadataAggregateType, outbox.AggregateType)
+	msg.Metadata.Set(MetadataAggregateID, outbox.AggregateID)
+	return relay.pub.Publish(outbox.Topic, msg)
+}
+
+func (relay *outboxRelay) backoffOf(attempts int) time.Duration {
+	backoff := relay.config.RetryInterval
+	for i := 0; i < attempts && backoff < relay.config.MaxRetryInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > relay.config.MaxRetryInterval {
+		return relay.config.MaxRetryInterval
+	}
+	return backoff
+}
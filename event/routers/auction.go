@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/event/handlers"
+	"nftshopping-store-api/pkg/pubsubs"
+)
+
+func InitAuctionRouter(router *message.Router) (err error) {
+	sub, err := pubsubs.GetSub()
+	if err != nil {
+		return
+	}
+	handler, err := handlers.GetHandler()
+	if err != nil {
+		return
+	}
+	router.AddNoPublisherHandler(
+		"AuctionLost",
+		event.AuctionLost,
+		sub,
+		handler.Auction.ListenAuctionLost,
+	)
+	return
+}
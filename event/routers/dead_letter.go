@@ -0,0 +1,57 @@
+package routers
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"nftshopping-store-api/event"
+	"nftshopping-store-api/persistence/repositories"
+	"strconv"
+	"time"
+)
+
+func newPoisonQueue() (poisonQueue message.HandlerMiddleware, err error) {
+	dao, err := repositories.GetRepository()
+	if err != nil {
+		return
+	}
+	return middleware.PoisonQueue(&deadLetterStore{deadLetter: dao.DeadLetter}, event.DeadLetter)
+}
+
+// deadLetterStore is the publisher of the poison queue, instead of going back to the broker
+// the poisoned messages are kept in mongo for the admin to replay or discard.
+type deadLetterStore struct {
+	deadLetter repositories.DeadLetterDao
+}
+
+func (store *deadLetterStore) Publish(topic string, messages ...*message.Message) (err error) {
+	for _, msg := range messages {
+		attempts, _ := strconv.Atoi(msg.Metadata.Get(metadataAttempts))
+		metadata := map[string]string{}
+		for key, value := range msg.Metadata {
+			metadata[key] = value
+		}
+		now := time.Now()
+		err = store.deadLetter.Create(context.Background(), &repositories.DeadLetter{
+			UUID:       msg.UUID,
+			Topic:      msg.Metadata.Get(middleware.PoisonedTopicKey),
+			Handler:    msg.Metadata.Get(middleware.PoisonedHandlerKey),
+			Subscriber: msg.Metadata.Get(middleware.PoisonedSubscriberKey),
+			Payload:    msg.Payload,
+			Metadata:   metadata,
+			Error:      msg.Metadata.Get(middleware.ReasonForPoisonedKey),
+			Attempts:   attempts,
+			Status:     repositories.DeadLetterPending,
+			CreateAt:   now,
+			UpdateAt:   now,
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (store *deadLetterStore) Close() error {
+	return nil
+}
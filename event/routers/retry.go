@@ -0,0 +1,38 @@
+package routers
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"nftshopping-store-api/event/handlers"
+	"strconv"
+)
+
+const metadataAttempts = "attempts"
+
+// retry retries the handler on the errors worth retrying, a permanent error gives up at once.
+// The attempts made are counted in the metadata for the poison queue.
+type retry struct {
+	middleware.Retry
+}
+
+func (r retry) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		var permanentErr error
+		attempts := 0
+		attempt := func(msg *message.Message) ([]*message.Message, error) {
+			attempts++
+			msg.Metadata.Set(metadataAttempts, strconv.Itoa(attempts))
+			messages, err := h(msg)
+			if handlers.IsPermanent(err) {
+				permanentErr = err
+				return messages, nil
+			}
+			return messages, err
+		}
+		messages, err := r.Retry.Middleware(attempt)(msg)
+		if permanentErr != nil {
+			return nil, permanentErr
+		}
+		return messages, err
+	}
+}
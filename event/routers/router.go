@@ -31,18 +31,28 @@ func newRouter() (router *message.Router, err error) {
 	}
 	router.AddPlugin(plugin.SignalsHandler)
 
+	poisonQueue, err := newPoisonQueue()
+	if err != nil {
+		return
+	}
+	// a message still failing after the retries is moved to the dead letters
 	router.AddMiddleware(
 		middleware.CorrelationID,
-		middleware.Retry{
+		poisonQueue,
+		retry{middleware.Retry{
 			MaxRetries:      3,
 			InitialInterval: time.Millisecond * 100,
 			Logger:          logger,
-		}.Middleware,
+		}}.Middleware,
 		middleware.Recoverer,
 	)
 	err = InitItemRouter(router)
 	if err != nil {
 		return
 	}
+	err = InitAuctionRouter(router)
+	if err != nil {
+		return
+	}
 	return router, nil
 }
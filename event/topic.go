@@ -1,6 +1,47 @@
 package event
 
-var (
+// The message of every topic is registered in messages, with its schema and version.
+// Events of the same aggregate are relayed in the order they were written.
+const (
+	// DeliverItem asks to deliver a minted token of an order, aggregate order.
 	DeliverItem = "topic.deliverItem"
-	OrderItem   = "topic.orderItem"
+	// OrderItem asks to mint the items of an order placed, aggregate order.
+	OrderItem = "topic.orderItem"
+	// ItemDelivered tells an item has reached its owner, aggregate item.
+	ItemDelivered = "topic.itemDelivered"
+	// AuctionLost tells a bidder the auction closed without them winning, aggregate auction.
+	AuctionLost = "topic.auctionLost"
+
+	// BrandCreated tells a brand was posted with its owner, aggregate brand.
+	BrandCreated = "topic.brandCreated"
+	// BrandUpdated carries the brand as it is after the update, aggregate brand.
+	BrandUpdated = "topic.brandUpdated"
+	// BrandDeleted tells a brand and its members were removed, aggregate brand.
+	BrandDeleted = "topic.brandDeleted"
+	// BrandMemberAdded, BrandMemberUpdated and BrandMemberRemoved tell the change
+	// of a member and its role, aggregate brand.
+	BrandMemberAdded   = "topic.brandMemberAdded"
+	BrandMemberUpdated = "topic.brandMemberUpdated"
+	BrandMemberRemoved = "topic.brandMemberRemoved"
+
+	// CreationCreated tells a creation was posted, aggregate creation.
+	CreationCreated = "topic.creationCreated"
+	// CreationUpdated carries the creation as it is after the update, aggregate creation.
+	CreationUpdated = "topic.creationUpdated"
+	// CreationDeleted tells a creation was removed, aggregate creation.
+	CreationDeleted = "topic.creationDeleted"
+	// CreationSaleStatusChanged tells the sale status moved, by hand or on schedule, aggregate creation.
+	CreationSaleStatusChanged = "topic.creationSaleStatusChanged"
+
+	// UserRegistered tells an account has registered, aggregate user.
+	UserRegistered = "topic.userRegistered"
+	// UserDeleted tells a user was removed, aggregate user.
+	UserDeleted = "topic.userDeleted"
+
+	// TradeSettled carries a transaction with its fee, from a creation, listing or offer, aggregate trade.
+	TradeSettled = "topic.tradeSettled"
+
+	// DeadLetter is where the poison queue puts the messages the handlers gave up on,
+	// they are kept in mongo instead of the broker.
+	DeadLetter = "topic.deadLetter"
 )
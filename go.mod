@@ -22,6 +22,7 @@ require (
 	github.com/swaggo/swag v1.7.0
 	go.mongodb.org/mongo-driver v1.5.2
 	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/mod v0.3.1-0.20200828183125-ce943fd02449 // indirect
 	items v0.0.1
 )
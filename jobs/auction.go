@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/transactions"
+	"time"
+)
+
+type auctionJob struct {
+	logger   log.Logger
+	auction  services.AuctionService
+	interval time.Duration
+}
+
+func NewAuctionJob() (job Job, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	logger, err := log.GetLog()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &auctionJob{
+		logger:   logger,
+		auction:  service.Auction,
+		interval: c.Auction.CheckInterval,
+	}, nil
+}
+
+func (job *auctionJob) Name() string {
+	return "SettleAuction"
+}
+
+func (job *auctionJob) Interval() time.Duration {
+	return job.interval
+}
+
+func (job *auctionJob) Execute(ctx context.Context) (err error) {
+	txn, err := transactions.NewTransaction(job.Name())
+	if err != nil {
+		return
+	}
+	defer txn.End(ctx)
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return job.auction.SettleAuction(transactionCtx)
+	}
+	amount, err := txn.With(ctx, callback)
+	if err != nil {
+		return
+	}
+	if amount.(int) > 0 {
+		job.logger.InfoF("settled %d auction(s)", amount)
+	}
+	return
+}
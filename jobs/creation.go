@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/transactions"
+	"time"
+)
+
+type creationJob struct {
+	logger   log.Logger
+	creation services.CreationService
+	interval time.Duration
+}
+
+func NewCreationJob() (job Job, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	logger, err := log.GetLog()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &creationJob{
+		logger:   logger,
+		creation: service.Creation,
+		interval: c.Creation.CheckInterval,
+	}, nil
+}
+
+func (job *creationJob) Name() string {
+	return "ScheduleSaleStatus"
+}
+
+func (job *creationJob) Interval() time.Duration {
+	return job.interval
+}
+
+// Execute changes the sale statuses in one transaction, so a change and its
+// CreationSaleStatusChanged event are stored together or not at all.
+func (job *creationJob) Execute(ctx context.Context) (err error) {
+	txn, err := transactions.NewTransaction(job.Name())
+	if err != nil {
+		return
+	}
+	defer txn.End(ctx)
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return job.creation.ScheduleSaleStatus(transactionCtx)
+	}
+	amount, err := txn.With(ctx, callback)
+	if err != nil {
+		return
+	}
+	if amount.(int) > 0 {
+		job.logger.InfoF("changed sale status of %d creation(s)", amount)
+	}
+	return
+}
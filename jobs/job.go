@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"nftshopping-store-api/pkg/log"
+	"sync"
+	"time"
+)
+
+var schedulerInstance *scheduler
+
+func GetScheduler() (instance *scheduler, err error) {
+	if schedulerInstance == nil {
+		instance, err = newScheduler()
+		if err != nil {
+			return nil, err
+		}
+		schedulerInstance = instance
+	}
+	return schedulerInstance, nil
+}
+
+type Job interface {
+	Name() string
+	Interval() time.Duration
+	Execute(ctx context.Context) (err error)
+}
+
+type scheduler struct {
+	logger log.Logger
+	jobs   []Job
+}
+
+func newScheduler() (instance *scheduler, err error) {
+	logger, err := log.GetLog()
+	if err != nil {
+		return
+	}
+	order, err := NewOrderJob()
+	if err != nil {
+		return
+	}
+	creation, err := NewCreationJob()
+	if err != nil {
+		return
+	}
+	auction, err := NewAuctionJob()
+	if err != nil {
+		return
+	}
+	market, err := NewMarketJob()
+	if err != nil {
+		return
+	}
+	return &scheduler{
+		logger: logger,
+		jobs:   []Job{order, creation, auction, market},
+	}, nil
+}
+
+func (s *scheduler) Run(ctx context.Context) (err error) {
+	var wg sync.WaitGroup
+	wg.Add(len(s.jobs))
+	for _, job := range s.jobs {
+		go func(job Job) {
+			defer wg.Done()
+			s.schedule(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+	return
+}
+
+func (s *scheduler) schedule(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job.Execute(ctx); err != nil {
+				s.logger.ErrorF("job(%s): %v", job.Name(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/log"
+	"time"
+)
+
+type marketJob struct {
+	logger   log.Logger
+	market   services.MarketService
+	interval time.Duration
+}
+
+func NewMarketJob() (job Job, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	logger, err := log.GetLog()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &marketJob{
+		logger:   logger,
+		market:   service.Market,
+		interval: c.Market.CheckInterval,
+	}, nil
+}
+
+func (job *marketJob) Name() string {
+	return "ExpireOffer"
+}
+
+func (job *marketJob) Interval() time.Duration {
+	return job.interval
+}
+
+func (job *marketJob) Execute(ctx context.Context) (err error) {
+	amount, err := job.market.ExpireOffer(ctx)
+	if err != nil {
+		return
+	}
+	if amount > 0 {
+		job.logger.InfoF("expired %d offer(s)", amount)
+	}
+	return
+}
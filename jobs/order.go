@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"nftshopping-store-api/business/services"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/log"
+	"nftshopping-store-api/pkg/transactions"
+	"time"
+)
+
+type orderJob struct {
+	logger   log.Logger
+	order    services.OrderService
+	interval time.Duration
+}
+
+func NewOrderJob() (job Job, err error) {
+	service, err := services.GetService()
+	if err != nil {
+		return
+	}
+	logger, err := log.GetLog()
+	if err != nil {
+		return
+	}
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	return &orderJob{
+		logger:   logger,
+		order:    service.Order,
+		interval: c.Order.CheckInterval,
+	}, nil
+}
+
+func (job *orderJob) Name() string {
+	return "ExpireOrder"
+}
+
+func (job *orderJob) Interval() time.Duration {
+	return job.interval
+}
+
+func (job *orderJob) Execute(ctx context.Context) (err error) {
+	txn, err := transactions.NewTransaction(job.Name())
+	if err != nil {
+		return
+	}
+	defer txn.End(ctx)
+
+	callback := func(transactionCtx transactions.TransactionContext) (interface{}, error) {
+		return job.order.ExpireOrder(transactionCtx)
+	}
+	amount, err := txn.With(ctx, callback)
+	if err != nil {
+		return
+	}
+	if amount.(int) > 0 {
+		job.logger.InfoF("expired %d order(s)", amount)
+	}
+	return
+}
@@ -5,7 +5,9 @@ import (
 	"flag"
 	adapterRouters "nftshopping-store-api/adapter/routers"
 	_ "nftshopping-store-api/docs"
+	"nftshopping-store-api/event/relays"
 	eventRouters "nftshopping-store-api/event/routers"
+	"nftshopping-store-api/jobs"
 	"nftshopping-store-api/pkg/config"
 	"nftshopping-store-api/pkg/flags"
 	"nftshopping-store-api/pkg/log"
@@ -39,7 +41,7 @@ func main() {
 		panic(err)
 	}
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(4)
 	//sub,err:=subscribers.NewItemSubscriber()
 	//if err != nil {
 	//	return
@@ -63,6 +65,30 @@ func main() {
 			logger.Error(err)
 		}
 	}()
+	relay, err := relays.GetRelay()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	go func() {
+		defer wg.Done()
+		err := relay.Run(context.Background())
+		if err != nil {
+			logger.Error(err)
+		}
+	}()
+	scheduler, err := jobs.GetScheduler()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	go func() {
+		defer wg.Done()
+		err := scheduler.Run(context.Background())
+		if err != nil {
+			logger.Error(err)
+		}
+	}()
 	adapterRouter, err := adapterRouters.GetRouter()
 	if err != nil {
 		logger.Error(err)
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+type ApiKeyStatus string
+
+const (
+	ApiKeyActive  ApiKeyStatus = "ACTIVE"
+	ApiKeyRevoked ApiKeyStatus = "REVOKED"
+)
+
+// ApiKey only keeps the hash of the secret, the key itself is shown once on creation.
+type ApiKey struct {
+	ID               primitive.ObjectID `bson:"_id"`
+	ServiceAccountID primitive.ObjectID `bson:"service_account_id"`
+	Hash             string             `bson:"hash"`
+	Status           ApiKeyStatus       `bson:"status"`
+	CreateAt         time.Time          `bson:"create_at"`
+	ExpireAt         *time.Time         `bson:"expire_at,omitempty"`
+	RevokeAt         *time.Time         `bson:"revoke_at,omitempty"`
+}
+
+// IsUsable tells whether the key is active and not past its expire time at the moment.
+func (key *ApiKey) IsUsable(at time.Time) bool {
+	return key.Status == ApiKeyActive && (key.ExpireAt == nil || at.Before(*key.ExpireAt))
+}
+
+type ApiKeyDao interface {
+	Find(ctx context.Context, id primitive.ObjectID) (key *ApiKey, err error)
+	Create(ctx context.Context, key *ApiKey) (err error)
+	Revoke(ctx context.Context, id primitive.ObjectID, at time.Time) (key *ApiKey, err error)
+	Expire(ctx context.Context, id primitive.ObjectID, at time.Time) (key *ApiKey, err error)
+	FindAllByServiceAccount(ctx context.Context, accountId primitive.ObjectID) (keys []ApiKey, err error)
+}
+
+type apiKeyDao struct {
+	collection *mongo.Collection
+}
+
+func NewApiKeyDao() (dao ApiKeyDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("api_key")
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.M{
+				"service_account_id": 1,
+			},
+		},
+	})
+	return &apiKeyDao{col}, nil
+}
+
+func (dao *apiKeyDao) Find(ctx context.Context, id primitive.ObjectID) (key *ApiKey, err error) {
+	key = &ApiKey{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", id}}).Decode(key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *apiKeyDao) Create(ctx context.Context, key *ApiKey) (err error) {
+	_, err = dao.collection.InsertOne(ctx, key)
+	return
+}
+
+func (dao *apiKeyDao) Revoke(ctx context.Context, id primitive.ObjectID, at time.Time) (key *ApiKey, err error) {
+	filter := bson.D{
+		{"_id", id},
+		{"status", ApiKeyActive},
+	}
+	set := bson.D{
+		{"status", ApiKeyRevoked},
+		{"revoke_at", at},
+	}
+	return dao.update(ctx, filter, set)
+}
+
+// Expire lets an active key keep working until at, it is used to overlap keys on rotation.
+// A key already set to expire is left as it is, so a key is only rotated once.
+func (dao *apiKeyDao) Expire(ctx context.Context, id primitive.ObjectID, at time.Time) (key *ApiKey, err error) {
+	filter := bson.D{
+		{"_id", id},
+		{"status", ApiKeyActive},
+		{"expire_at", bson.D{{"$exists", false}}},
+	}
+	return dao.update(ctx, filter, bson.D{{"expire_at", at}})
+}
+
+func (dao *apiKeyDao) FindAllByServiceAccount(ctx context.Context, accountId primitive.ObjectID) (keys []ApiKey, err error) {
+	option := options.Find().SetSort(bson.D{{"create_at", -1}})
+	cur, err := dao.collection.Find(ctx, bson.D{{"service_account_id", accountId}}, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var key ApiKey
+		err := cur.Decode(&key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return
+}
+
+func (dao *apiKeyDao) update(ctx context.Context, filter, set bson.D) (key *ApiKey, err error) {
+	key = &ApiKey{}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, bson.D{{"$set", set}}, option).Decode(key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+type AuctionDao interface {
+	Find(ctx context.Context, creationId primitive.ObjectID) (auction *Auction, err error)
+	Create(ctx context.Context, auction *Auction) (err error)
+	Bid(ctx context.Context, creationId primitive.ObjectID, bidder string, price int, at time.Time) (isAccepted bool, err error)
+	Close(ctx context.Context, creationId primitive.ObjectID, status AuctionStatus, tnxId *primitive.ObjectID, at time.Time) (auction *Auction, err error)
+	FindAllByFilter(ctx context.Context, filter AuctionFilter) (auctions []Auction, err error)
+}
+
+type auctionDao struct {
+	collection *mongo.Collection
+}
+
+func NewAuctionDao() (dao AuctionDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &auctionDao{db.Collection("auction")}, nil
+}
+
+func (dao *auctionDao) Create(ctx context.Context, auction *Auction) (err error) {
+	_, err = dao.collection.InsertOne(ctx, auction)
+	return
+}
+
+func (dao *auctionDao) Find(ctx context.Context, creationId primitive.ObjectID) (auction *Auction, err error) {
+	auction = &Auction{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", creationId}}).Decode(auction)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+// Bid takes the bid as the highest one only if the auction is still open and the price
+// beats the current highest bid by the minimum increment, or reaches the start price
+// when nobody has bid yet.
+func (dao *auctionDao) Bid(
+	ctx context.Context, creationId primitive.ObjectID, bidder string, price int, at time.Time,
+) (isAccepted bool, err error) {
+	filter := bson.D{
+		{"_id", creationId},
+		{"status", AuctionOpen},
+		{"$expr", bson.D{{"$cond", bson.A{
+			bson.D{{"$gt", bson.A{"$bid_count", 0}}},
+			bson.D{{"$lte", bson.A{bson.D{{"$add", bson.A{"$highest_price", "$min_increment"}}}, price}}},
+			bson.D{{"$lte", bson.A{"$start_price", price}}},
+		}}}},
+	}
+	update := bson.D{
+		{"$set", bson.D{
+			{"highest_price", price},
+			{"highest_bidder", bidder},
+			{"update_at", at},
+		}},
+		{"$inc", bson.D{
+			{"bid_count", 1},
+		}},
+	}
+	result, err := dao.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (dao *auctionDao) Close(
+	ctx context.Context, creationId primitive.ObjectID, status AuctionStatus, tnxId *primitive.ObjectID, at time.Time,
+) (auction *Auction, err error) {
+	auction = &Auction{}
+	filter := bson.D{
+		{"_id", creationId},
+		{"status", AuctionOpen},
+	}
+	set := bson.D{
+		{"status", status},
+		{"close_at", at},
+		{"update_at", at},
+	}
+	if tnxId != nil {
+		set = append(set, bson.E{Key: "transaction_id", Value: tnxId})
+	}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, bson.D{{"$set", set}}, option).Decode(auction)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *auctionDao) FindAllByFilter(ctx context.Context, filter AuctionFilter) (auctions []Auction, err error) {
+	auctions, err = dao.findList(ctx, filter)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *auctionDao) findList(ctx context.Context, filter interface{}) (auctions []Auction, err error) {
+	cur, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var auction Auction
+		err := cur.Decode(&auction)
+		if err != nil {
+			return nil, err
+		}
+		auctions = append(auctions, auction)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+type AuctionStatus string
+
+const (
+	AuctionOpen   AuctionStatus = "OPEN"
+	AuctionSold   AuctionStatus = "SOLD"
+	AuctionUnsold AuctionStatus = "UNSOLD"
+)
+
+// Auction is the English auction of a creation sold by SaleWayAuction, keyed by the creation id.
+type Auction struct {
+	ID            primitive.ObjectID  `bson:"_id" json:"id"`
+	BrandID       string              `bson:"brand_id" json:"brandId"`
+	StartPrice    int                 `bson:"start_price" json:"startPrice"`
+	ReservePrice  int                 `bson:"reserve_price" json:"reservePrice"`
+	MinIncrement  int                 `bson:"min_increment" json:"minIncrement"`
+	HighestPrice  int                 `bson:"highest_price" json:"highestPrice"`
+	HighestBidder string              `bson:"highest_bidder" json:"highestBidder"`
+	BidCount      int                 `bson:"bid_count" json:"bidCount"`
+	Status        AuctionStatus       `bson:"status" json:"status"`
+	TransactionID *primitive.ObjectID `bson:"transaction_id,omitempty" json:"transactionId"`
+	CreateAt      time.Time           `bson:"create_at" json:"createAt"`
+	UpdateAt      time.Time           `bson:"update_at" json:"updateAt"`
+	CloseAt       time.Time           `bson:"close_at" json:"closeAt"`
+}
+
+type AuctionFilter bson.D
+
+func SelectorOfAuction(selector AuctionSelector) (filter AuctionFilter) {
+	filter = AuctionFilter{}
+	if selector.BrandID != nil {
+		filter = append(filter, bson.E{
+			Key: "brand_id", Value: selector.BrandID,
+		})
+	}
+
+	if len(selector.Status) > 0 {
+		filter = append(filter, bson.E{
+			Key: "status", Value: bson.D{{Key: "$in", Value: selector.Status}},
+		})
+	}
+	return
+}
+
+type AuctionSelector struct {
+	BrandID *string         `json:"brandId"`
+	Status  []AuctionStatus `json:"status"`
+}
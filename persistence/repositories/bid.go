@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type BidDao interface {
+	Create(ctx context.Context, bid *Bid) (err error)
+	FindBidders(ctx context.Context, creationId primitive.ObjectID) (bidders []string, err error)
+	FindAllByFilter(ctx context.Context, filter BidFilter) (bids []Bid, err error)
+	FindAllByFilterAndPage(ctx context.Context, filter BidFilter, pageable utils.Pageable) (bids *utils.Page, err error)
+}
+
+type bidDao struct {
+	collection *mongo.Collection
+}
+
+func NewBidDao() (dao BidDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &bidDao{db.Collection("auction_bid")}, nil
+}
+
+func (dao *bidDao) Create(ctx context.Context, bid *Bid) (err error) {
+	_, err = dao.collection.InsertOne(ctx, bid)
+	return
+}
+
+func (dao *bidDao) FindBidders(ctx context.Context, creationId primitive.ObjectID) (bidders []string, err error) {
+	values, err := dao.collection.Distinct(ctx, "bidder", bson.D{{"creation_id", creationId}})
+	if err != nil {
+		return
+	}
+	for _, value := range values {
+		if bidder, ok := value.(string); ok {
+			bidders = append(bidders, bidder)
+		}
+	}
+	return
+}
+
+func (dao *bidDao) FindAllByFilter(ctx context.Context, filter BidFilter) (bids []Bid, err error) {
+	bids, err = dao.findList(ctx, filter)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *bidDao) FindAllByFilterAndPage(
+	ctx context.Context, filter BidFilter, pageable utils.Pageable,
+) (bids *utils.Page, err error) {
+	bids, err = dao.findPage(ctx, filter, pageable)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *bidDao) findList(ctx context.Context, filter interface{}) (bids []Bid, err error) {
+	option := options.Find().SetSort(bson.D{{"price", -1}})
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var bid Bid
+		err := cur.Decode(&bid)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *bidDao) findPage(
+	ctx context.Context, filter interface{}, pageable utils.Pageable,
+) (bids *utils.Page, err error) {
+	total, err := dao.collection.CountDocuments(ctx, filter)
+	bids = &utils.Page{Size: pageable.Size, Page: pageable.Page, Total: total}
+	option := options.Find()
+	option.SetSkip(int64(pageable.Size * pageable.Page))
+	option.SetLimit(int64(pageable.Size))
+
+	option.SetSort(sortOf(pageable, bson.E{Key: "price", Value: -1}))
+
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var content []Bid
+	for cur.Next(ctx) {
+		var bid Bid
+		err := cur.Decode(&bid)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, bid)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	bids.Content = content
+	bids.TotalPage = utils.GetTotalPage(int64(bids.Size), bids.Total)
+	return
+}
+
+type Bid struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	CreationID primitive.ObjectID `bson:"creation_id" json:"creationId"`
+	Bidder     string             `bson:"bidder" json:"bidder"`
+	Price      int                `bson:"price" json:"price"`
+	BidAt      time.Time          `bson:"bid_at" json:"bidAt"`
+}
+
+type BidFilter bson.D
+
+func SelectorOfBid(selector BidSelector) (filter BidFilter) {
+	filter = BidFilter{}
+	if selector.CreationID != nil {
+		filter = append(filter, bson.E{
+			Key: "creation_id", Value: selector.CreationID,
+		})
+	}
+
+	if selector.Bidder != nil {
+		filter = append(filter, bson.E{
+			Key: "bidder", Value: selector.Bidder,
+		})
+	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
+	return
+}
+
+// BidSortFields are the fields a page of bids can be sorted by.
+var BidSortFields = utils.SortFields{
+	"price": "price",
+	"bidAt": "bid_at",
+}
+
+// BidFilterFields are the fields the filter of bids can be written on.
+var BidFilterFields = utils.FilterFields{
+	"creationId": objectIdField("creation_id"),
+	"bidder":     utils.StringField("bidder"),
+	"price":      utils.IntField("price"),
+	"bidAt":      utils.TimeField("bid_at"),
+}
+
+type BidSelector struct {
+	CreationID *primitive.ObjectID `json:"creationId"`
+	Bidder     *string             `json:"bidder"`
+	Conditions []utils.Condition   `json:"conditions"`
+}
@@ -76,6 +76,7 @@ func (dao *brandDao) Save(ctx context.Context, brand *Brand) (err error) {
 		{"image_url", brand.ImageURL},
 		{"create_at", brand.CreateAt},
 		{"description", brand.Description},
+		{"royalty_bps", brand.RoyaltyBps},
 	}}}
 	option := options.FindOneAndUpdate().SetUpsert(true)
 	err = dao.collection.FindOneAndUpdate(ctx, filter, update, option).Decode(&oldBrand)
@@ -167,13 +168,7 @@ func (dao *brandDao) findPage(
 	option.SetSkip(int64(pageable.Size * pageable.Page))
 	option.SetLimit(int64(pageable.Size))
 
-	if len(pageable.Sort) > 0 {
-		sort := bson.D{}
-		for key, value := range pageable.Sort {
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		option.SetSort(sort)
-	}
+	option.SetSort(sortOf(pageable))
 
 	cur, err := dao.collection.Find(ctx, filter, option)
 	if err != nil {
@@ -203,6 +198,7 @@ type Brand struct {
 	Name        string    `bson:"name" json:"name"`
 	Description string    `bson:"description" json:"description"`
 	ImageURL    string    `bson:"image_url" json:"imageUrl"`
+	RoyaltyBps  int       `bson:"royalty_bps" json:"royaltyBps"`
 	CreateAt    time.Time `bson:"create_at" json:"createAt"`
 }
 
@@ -226,13 +222,30 @@ func SelectorOfBrand(selector BrandSelector) (filter BrandFilter) {
 		}
 		filter = append(filter, bson.E{Key: "$expr", Value: bson.D{{"$and", intervalFilter}}})
 	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
 	return
 }
 
+// BrandSortFields are the fields a page of brands can be sorted by.
+var BrandSortFields = utils.SortFields{
+	"name":       "name",
+	"royaltyBps": "royalty_bps",
+	"createAt":   "create_at",
+}
+
+// BrandFilterFields are the fields the filter of brands can be written on.
+var BrandFilterFields = utils.FilterFields{
+	"id":         utils.StringField("_id"),
+	"name":       utils.StringField("name"),
+	"royaltyBps": utils.IntField("royalty_bps"),
+	"createAt":   utils.TimeField("create_at"),
+}
+
 type BrandSelector struct {
-	Name         *string    `json:"name"`
-	CreateBefore *time.Time `json:"createBefore"`
-	CreateAfter  *time.Time `json:"createAfter"`
+	Name         *string           `json:"name"`
+	CreateBefore *time.Time        `json:"createBefore"`
+	CreateAfter  *time.Time        `json:"createAfter"`
+	Conditions   []utils.Condition `json:"conditions"`
 }
 
 var BrandNotFound = errors.New("brand not found")
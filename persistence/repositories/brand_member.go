@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+type BrandRole string
+
+const (
+	BrandOwner  BrandRole = "OWNER"
+	BrandAdmin  BrandRole = "ADMIN"
+	BrandEditor BrandRole = "EDITOR"
+)
+
+type BrandMember struct {
+	BrandID  string    `bson:"brand_id"`
+	Member   string    `bson:"member"`
+	Role     BrandRole `bson:"role"`
+	CreateAt time.Time `bson:"create_at"`
+	UpdateAt time.Time `bson:"update_at"`
+}
+
+type BrandMemberDao interface {
+	Find(ctx context.Context, brandId, member string) (brandMember *BrandMember, err error)
+	Create(ctx context.Context, brandMember *BrandMember) (err error)
+	UpdateRole(ctx context.Context, brandId, member string, role BrandRole) (brandMember *BrandMember, err error)
+	Delete(ctx context.Context, brandId, member string) (err error)
+	DeleteByBrand(ctx context.Context, brandId string) (err error)
+	CountByRole(ctx context.Context, brandId string, role BrandRole) (count int64, err error)
+	FindAllByBrand(ctx context.Context, brandId string) (brandMembers []BrandMember, err error)
+	FindAllByMember(ctx context.Context, member string) (brandMembers []BrandMember, err error)
+}
+
+type brandMemberDao struct {
+	collection *mongo.Collection
+}
+
+func NewBrandMemberDao() (dao BrandMemberDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("brand_member")
+	opt := options.Index().SetUnique(true)
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{"brand_id", 1},
+				{"member", 1},
+			}, Options: opt,
+		},
+		{
+			Keys: bson.M{
+				"member": 1,
+			},
+		},
+	})
+	return &brandMemberDao{col}, nil
+}
+
+func (dao *brandMemberDao) Find(ctx context.Context, brandId, member string) (brandMember *BrandMember, err error) {
+	brandMember = &BrandMember{}
+	err = dao.collection.FindOne(ctx, bson.D{{"brand_id", brandId}, {"member", member}}).Decode(brandMember)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *brandMemberDao) Create(ctx context.Context, brandMember *BrandMember) (err error) {
+	_, err = dao.collection.InsertOne(ctx, brandMember)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return BrandMemberDuplicate
+		}
+		return
+	}
+	return
+}
+
+func (dao *brandMemberDao) UpdateRole(
+	ctx context.Context, brandId, member string, role BrandRole,
+) (brandMember *BrandMember, err error) {
+	brandMember = &BrandMember{}
+	update := bson.D{{"$set", bson.D{
+		{"role", role},
+		{"update_at", time.Now()},
+	}}}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(
+		ctx, bson.D{{"brand_id", brandId}, {"member", member}}, update, option,
+	).Decode(brandMember)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *brandMemberDao) Delete(ctx context.Context, brandId, member string) (err error) {
+	result, err := dao.collection.DeleteOne(ctx, bson.D{{"brand_id", brandId}, {"member", member}})
+	if err != nil {
+		return
+	}
+	if result.DeletedCount == 0 {
+		return BrandMemberNotFound
+	}
+	return
+}
+
+func (dao *brandMemberDao) DeleteByBrand(ctx context.Context, brandId string) (err error) {
+	_, err = dao.collection.DeleteMany(ctx, bson.D{{"brand_id", brandId}})
+	return
+}
+
+func (dao *brandMemberDao) CountByRole(ctx context.Context, brandId string, role BrandRole) (count int64, err error) {
+	return dao.collection.CountDocuments(ctx, bson.D{{"brand_id", brandId}, {"role", role}})
+}
+
+func (dao *brandMemberDao) FindAllByBrand(ctx context.Context, brandId string) (brandMembers []BrandMember, err error) {
+	return dao.findList(ctx, bson.D{{"brand_id", brandId}})
+}
+
+func (dao *brandMemberDao) FindAllByMember(ctx context.Context, member string) (brandMembers []BrandMember, err error) {
+	return dao.findList(ctx, bson.D{{"member", member}})
+}
+
+func (dao *brandMemberDao) findList(ctx context.Context, filter interface{}) (brandMembers []BrandMember, err error) {
+	cur, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var brandMember BrandMember
+		err := cur.Decode(&brandMember)
+		if err != nil {
+			return nil, err
+		}
+		brandMembers = append(brandMembers, brandMember)
+	}
+	return
+}
+
+var (
+	BrandMemberNotFound  = errors.New("brand member not found")
+	BrandMemberDuplicate = errors.New("brand member is duplicate")
+)
@@ -16,6 +16,7 @@ type CollectDao interface {
 	FindAllByPage(ctx context.Context, pageable utils.Pageable) (collects *utils.Page, err error)
 	FindAllByFilter(ctx context.Context, filter CollectFilter) (collects []Collect, err error)
 	FindAllByFilterAndPage(ctx context.Context, filter CollectFilter, pageable utils.Pageable) (collects *utils.Page, err error)
+	FindAllByFilterAndCursor(ctx context.Context, filter CollectFilter, pageable utils.Pageable) (collects *utils.Page, err error)
 }
 
 type Collect struct {
@@ -42,13 +43,29 @@ func SelectorOfCollect(selector CollectSelector) (filter CollectFilter) {
 			Key: "_id.creation_id", Value: selector.CreationID,
 		})
 	}
+	matchStage = append(matchStage, conditionsOf(selector.Conditions)...)
 
 	return CollectFilter(bson.D{{"$match", matchStage}})
 }
 
+// CollectSortFields are the fields a page of collects can be sorted by.
+var CollectSortFields = utils.SortFields{
+	"owner":      "_id.owner",
+	"creationId": "_id.creation_id",
+	"amount":     "amount",
+}
+
+// CollectFilterFields are the fields the filter of collections can be written on.
+var CollectFilterFields = utils.FilterFields{
+	"owner":      utils.StringField("_id.owner"),
+	"creationId": objectIdField("_id.creation_id"),
+	"amount":     utils.IntField("amount"),
+}
+
 type CollectSelector struct {
 	Owner      *string             `json:"owner"`
 	CreationID *primitive.ObjectID `json:"creationId"`
+	Conditions []utils.Condition   `json:"conditions"`
 }
 
 type CollectionDao interface {
@@ -131,6 +148,29 @@ func (dao *collectionDao) FindAllByFilterAndPage(
 	return
 }
 
+func (dao *collectionDao) FindAllByFilterAndCursor(
+	ctx context.Context, filter CollectFilter, pageable utils.Pageable,
+) (collections *utils.Page, err error) {
+	pipeline := mongo.Pipeline{}
+	pipeline = append(pipeline, stageOfCollection...)
+	pipeline = append(pipeline, bson.D(filter))
+	docs, next, err := aggregateByCursor(ctx, dao.collection, pipeline, pageable)
+	if err != nil {
+		return
+	}
+	collections = &utils.Page{Size: pageable.Size, NextCursor: next}
+	var content []Collect
+	for _, doc := range docs {
+		var collection Collect
+		if err = bson.Unmarshal(doc, &collection); err != nil {
+			return nil, err
+		}
+		content = append(content, collection)
+	}
+	collections.Content = content
+	return
+}
+
 func (dao *collectionDao) findOne(ctx context.Context, pipeline []bson.D) (collection *Collect, err error) {
 	if err != nil {
 		return
@@ -193,15 +233,9 @@ func (dao *collectionDao) findPage(
 
 	pipelineOfPage := mongo.Pipeline{}
 	pageStage := []bson.D{
-		{{"$limit", pageable.Size}},
+		{{"$sort", sortOf(pageable)}},
 		{{"$skip", pageable.Size * pageable.Page}},
-	}
-	if len(pageable.Sort) > 0 {
-		sort := bson.D{}
-		for key, value := range pageable.Sort {
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		pageStage = append(pageStage, bson.D{{"$sort", sort}})
+		{{"$limit", pageable.Size}},
 	}
 
 	pipelineOfPage = append(pipelineOfPage, pipeline...)
@@ -343,6 +377,29 @@ func (dao *stockDao) FindAllByFilterAndPage(
 	return
 }
 
+func (dao *stockDao) FindAllByFilterAndCursor(
+	ctx context.Context, filter CollectFilter, pageable utils.Pageable,
+) (collections *utils.Page, err error) {
+	pipeline := mongo.Pipeline{}
+	pipeline = append(pipeline, stageOfStock...)
+	pipeline = append(pipeline, bson.D(filter))
+	docs, next, err := aggregateByCursor(ctx, dao.stock, pipeline, pageable)
+	if err != nil {
+		return
+	}
+	collections = &utils.Page{Size: pageable.Size, NextCursor: next}
+	var content []Collect
+	for _, doc := range docs {
+		var collection Collect
+		if err = bson.Unmarshal(doc, &collection); err != nil {
+			return nil, err
+		}
+		content = append(content, collection)
+	}
+	collections.Content = content
+	return
+}
+
 func (dao *stockDao) findOne(ctx context.Context, pipeline []bson.D) (collection *Collect, err error) {
 	if err != nil {
 		return
@@ -404,15 +461,9 @@ func (dao *stockDao) findPage(
 
 	pipelineOfPage := mongo.Pipeline{}
 	pageStage := []bson.D{
-		{{"$limit", pageable.Size}},
+		{{"$sort", sortOf(pageable)}},
 		{{"$skip", pageable.Size * pageable.Page}},
-	}
-	if len(pageable.Sort) > 0 {
-		sort := bson.D{}
-		for key, value := range pageable.Sort {
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		pageStage = append(pageStage, bson.D{{"$sort", sort}})
+		{{"$limit", pageable.Size}},
 	}
 
 	pipelineOfPage = append(pipelineOfPage, pipeline...)
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"nftshopping-store-api/pkg/databases"
 	"nftshopping-store-api/pkg/utils"
+	"regexp"
 	"time"
 )
 
@@ -23,6 +24,12 @@ type CreationDao interface {
 	FindAllByCreationName(ctx context.Context, creationName string) (creations []Creation, err error)
 	FindAllByFilter(ctx context.Context, filter CreationFilter) (creations []Creation, err error)
 	FindAllByFilterAndPage(ctx context.Context, filter CreationFilter, pageable utils.Pageable) (creations *utils.Page, err error)
+	FindAllByFilterAndCursor(ctx context.Context, filter CreationFilter, pageable utils.Pageable) (creations *utils.Page, err error)
+	Reserve(ctx context.Context, creationId primitive.ObjectID, amount int, at time.Time) (isReserved bool, err error)
+	Release(ctx context.Context, creationId primitive.ObjectID, amount int) (err error)
+	UpdateSaleStatus(ctx context.Context, creationId primitive.ObjectID, from, to SaleStatus) (isUpdated bool, err error)
+	Search(ctx context.Context, text string, filter CreationFilter, pageable utils.Pageable) (result *CreationSearch, err error)
+	Autocomplete(ctx context.Context, prefix string, limit int) (suggestions []CreationSuggestion, err error)
 }
 
 type creationDao struct {
@@ -34,7 +41,24 @@ func NewCreationDao() (dao CreationDao, err error) {
 	if err != nil {
 		return nil, err
 	}
-	return &creationDao{db.Collection("creation")}, nil
+	col := db.Collection("creation")
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{"creation_name", "text"},
+				{"creator", "text"},
+				{"description", "text"},
+			}, Options: options.Index().SetWeights(bson.D{
+				{"creation_name", 10},
+				{"creator", 5},
+				{"description", 1},
+			}),
+		},
+		{
+			Keys: bson.D{{"creation_name", 1}},
+		},
+	})
+	return &creationDao{col}, nil
 }
 
 func (dao *creationDao) Exist(ctx context.Context, id primitive.ObjectID) (isExisted bool, err error) {
@@ -75,6 +99,7 @@ func (dao *creationDao) Save(ctx context.Context, creation *Creation) (err error
 		{"small_image_url", creation.SmallImageURL},
 		{"properties", creation.Properties},
 		{"price", creation.Price},
+		{"royalty_bps", creation.RoyaltyBps},
 		{"brand_id", creation.BrandID},
 		{"sale_way", creation.SaleWay},
 		{"sale_status", creation.SaleStatus},
@@ -148,6 +173,169 @@ func (dao *creationDao) FindAllByFilterAndPage(
 	return
 }
 
+func (dao *creationDao) FindAllByFilterAndCursor(
+	ctx context.Context, filter CreationFilter, pageable utils.Pageable,
+) (creations *utils.Page, err error) {
+	docs, next, err := findByCursor(ctx, dao.collection, bson.D(filter), pageable)
+	if err != nil {
+		return
+	}
+	creations = &utils.Page{Size: pageable.Size, NextCursor: next}
+	var content []Creation
+	for _, doc := range docs {
+		var creation Creation
+		if err = bson.Unmarshal(doc, &creation); err != nil {
+			return nil, err
+		}
+		content = append(content, creation)
+	}
+	creations.Content = content
+	return
+}
+
+func (dao *creationDao) Reserve(
+	ctx context.Context, creationId primitive.ObjectID, amount int, at time.Time,
+) (isReserved bool, err error) {
+	filter := bson.D{
+		{"_id", creationId},
+		{"sale_status", SaleStatusOnSale},
+		{"sale_start_at", bson.D{{"$lte", at}}},
+		{"sale_end_at", bson.D{{"$gte", at}}},
+		{"$expr", bson.D{{"$lte", bson.A{
+			bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$reserved", 0}}}, amount}}},
+			"$amount",
+		}}}},
+	}
+	update := bson.D{{"$inc", bson.D{
+		{"reserved", amount},
+	}}}
+	result, err := dao.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (dao *creationDao) Release(ctx context.Context, creationId primitive.ObjectID, amount int) (err error) {
+	filter := bson.D{
+		{"_id", creationId},
+		{"reserved", bson.D{{"$gte", amount}}},
+	}
+	update := bson.D{{"$inc", bson.D{
+		{"reserved", -amount},
+	}}}
+	_, err = dao.collection.UpdateOne(ctx, filter, update)
+	return
+}
+
+func (dao *creationDao) UpdateSaleStatus(
+	ctx context.Context, creationId primitive.ObjectID, from, to SaleStatus,
+) (isUpdated bool, err error) {
+	filter := bson.D{
+		{"_id", creationId},
+		{"sale_status", from},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"sale_status", to},
+	}}}
+	result, err := dao.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// Search matches the words of text against name, creator and description, ranked by relevance
+// unless pageable asks for another sort, and counts the facets of every matched creation.
+// A negative page returns all the matches.
+func (dao *creationDao) Search(
+	ctx context.Context, text string, filter CreationFilter, pageable utils.Pageable,
+) (result *CreationSearch, err error) {
+	match := bson.D(filter)
+	if len(text) > 0 {
+		match = append(bson.D{{"$text", bson.D{{"$search", text}}}}, match...)
+	}
+	var relevance []bson.E
+	if len(text) > 0 {
+		relevance = append(relevance, bson.E{Key: "score", Value: -1})
+	}
+	sort := sortOf(pageable, relevance...)
+	content := bson.A{bson.D{{"$sort", sort}}}
+	if pageable.Page >= 0 {
+		content = append(content,
+			bson.D{{"$skip", pageable.Size * pageable.Page}},
+			bson.D{{"$limit", pageable.Size}},
+		)
+	}
+	bands := bson.A{}
+	for _, band := range CreationPriceBands {
+		bands = append(bands, band)
+	}
+	pipeline := mongo.Pipeline{
+		{{"$match", match}},
+	}
+	if len(text) > 0 {
+		pipeline = append(pipeline, bson.D{{"$addFields", bson.D{{"score", bson.D{{"$meta", "textScore"}}}}}})
+	}
+	pipeline = append(pipeline, bson.D{{"$facet", bson.D{
+		{"total", bson.A{bson.D{{"$count", "count"}}}},
+		{"content", content},
+		{"properties", bson.A{
+			bson.D{{"$unwind", "$properties"}},
+			bson.D{{"$sortByCount", "$properties"}},
+		}},
+		{"brands", bson.A{bson.D{{"$sortByCount", "$brand_id"}}}},
+		{"sale_status", bson.A{bson.D{{"$sortByCount", "$sale_status"}}}},
+		{"price_bands", bson.A{bson.D{{"$bucket", bson.D{
+			{"groupBy", "$price"},
+			{"boundaries", bands},
+			{"default", CreationPriceBands[len(CreationPriceBands)-1]},
+		}}}}},
+	}}})
+	cur, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var facets []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		CreationSearch `bson:",inline"`
+	}
+	if err = cur.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+	result = &CreationSearch{}
+	if len(facets) > 0 {
+		*result = facets[0].CreationSearch
+		if len(facets[0].Total) > 0 {
+			result.Total = facets[0].Total[0].Count
+		}
+	}
+	return
+}
+
+// Autocomplete suggests the creation names starting with prefix, ignoring case.
+func (dao *creationDao) Autocomplete(
+	ctx context.Context, prefix string, limit int,
+) (suggestions []CreationSuggestion, err error) {
+	filter := bson.D{{"creation_name", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix), Options: "i"}}}
+	option := options.Find().
+		SetProjection(bson.D{{"creation_name", 1}, {"creator", 1}}).
+		SetSort(bson.D{{"creation_name", 1}}).
+		SetLimit(int64(limit))
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	if err = cur.All(ctx, &suggestions); err != nil {
+		return nil, err
+	}
+	return
+}
+
 func (dao *creationDao) findList(ctx context.Context, filter interface{}) (creations []Creation, err error) {
 	cur, err := dao.collection.Find(ctx, filter)
 	if err != nil {
@@ -177,13 +365,7 @@ func (dao *creationDao) findPage(
 	option.SetSkip(int64(pageable.Size * pageable.Page))
 	option.SetLimit(int64(pageable.Size))
 
-	if len(pageable.Sort) > 0 {
-		sort := bson.D{}
-		for key, value := range pageable.Sort {
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		option.SetSort(sort)
-	}
+	option.SetSort(sortOf(pageable))
 
 	cur, err := dao.collection.Find(ctx, filter, option)
 	if err != nil {
@@ -214,17 +396,64 @@ type Creation struct {
 	SmallImageURL   string             `json:"smallImageUrl"`
 	Properties      []string           `bson:"properties" json:"properties"`
 	Amount          int                `bson:"amount" json:"amount"`
+	Reserved        int                `bson:"reserved" json:"reserved"`
 	Price           int                `bson:"price" json:"price"`
+	RoyaltyBps      int                `bson:"royalty_bps" json:"royaltyBps"`
 	CreateAt        time.Time          `bson:"create_at" json:"createAt"`
 	BrandID         string             `bson:"brand_id" json:"brandId"`
-	SaleWay         string             `bson:"sale_way" json:"saleWay"`
-	SaleStatus      string             `bson:"sale_status" json:"saleStatus"`
+	SaleWay         SaleWay            `bson:"sale_way" json:"saleWay"`
+	SaleStatus      SaleStatus         `bson:"sale_status" json:"saleStatus"`
 	SaleStartAt     time.Time          `bson:"sale_start_at" json:"saleStartAt"`
 	SaleEndAt       time.Time          `bson:"sale_end_at" json:"saleEndAt"`
 	Description     string             `bson:"description" json:"description"`
 	ContractAddress string             `bson:"contract_address" json:"contractAddress"`
 }
 
+// CreationPriceBands are the lower bounds of the price facet, the last one is open ended.
+var CreationPriceBands = []int{0, 100, 1000, 10000, 100000}
+
+type CreationSearch struct {
+	Total      int64         `bson:"-" json:"total"`
+	Content    []Creation    `bson:"content" json:"content"`
+	Properties []FacetCount  `bson:"properties" json:"properties"`
+	Brands     []FacetCount  `bson:"brands" json:"brands"`
+	SaleStatus []FacetCount  `bson:"sale_status" json:"saleStatus"`
+	PriceBands []PriceBucket `bson:"price_bands" json:"priceBands"`
+}
+
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int    `bson:"count" json:"count"`
+}
+
+type PriceBucket struct {
+	Min   int `bson:"_id" json:"min"`
+	Count int `bson:"count" json:"count"`
+}
+
+type CreationSuggestion struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	CreationName string             `bson:"creation_name" json:"creationName"`
+	Creator      string             `bson:"creator" json:"creator"`
+}
+
+type SaleStatus string
+
+const (
+	SaleStatusWaitForSale SaleStatus = "WAIT_FOR_SALE"
+	SaleStatusOnSale      SaleStatus = "ON_SALE"
+	SaleStatusSoldOut     SaleStatus = "SOLD_OUT"
+	SaleStatusEnded       SaleStatus = "ENDED"
+	SaleStatusCancelled   SaleStatus = "CANCELLED"
+)
+
+type SaleWay string
+
+const (
+	SaleWayFixedPrice SaleWay = "FIXED_PRICE"
+	SaleWayAuction    SaleWay = "AUCTION"
+)
+
 type CreationFilter bson.D
 
 func SelectorOfCreation(selector CreationSelector) (filter CreationFilter) {
@@ -270,6 +499,18 @@ func SelectorOfCreation(selector CreationSelector) (filter CreationFilter) {
 		filter = append(filter, bson.E{Key: "sale_start_at", Value: saleTimeFilter})
 	}
 
+	if selector.SaleEndBefore != nil {
+		filter = append(filter, bson.E{
+			Key: "sale_end_at", Value: bson.D{{Key: "$lte", Value: selector.SaleEndBefore}},
+		})
+	}
+
+	if len(selector.SaleStatus) > 0 {
+		filter = append(filter, bson.E{
+			Key: "sale_status", Value: bson.D{{Key: "$in", Value: selector.SaleStatus}},
+		})
+	}
+
 	if selector.MaxPrice != nil || selector.MinPrice != nil {
 		priceFilter := bson.D{}
 		if selector.MinPrice != nil {
@@ -280,9 +521,40 @@ func SelectorOfCreation(selector CreationSelector) (filter CreationFilter) {
 		}
 		filter = append(filter, bson.E{Key: "price", Value: priceFilter})
 	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
 	return
 }
 
+// CreationSortFields are the fields a page of creations can be sorted by.
+var CreationSortFields = utils.SortFields{
+	"creationName": "creation_name",
+	"amount":       "amount",
+	"price":        "price",
+	"royaltyBps":   "royalty_bps",
+	"createAt":     "create_at",
+	"saleStartAt":  "sale_start_at",
+	"saleEndAt":    "sale_end_at",
+}
+
+// CreationFilterFields are the fields the filter of creations can be written on.
+var CreationFilterFields = utils.FilterFields{
+	"id":              objectIdField("_id"),
+	"creationName":    utils.StringField("creation_name"),
+	"creator":         utils.StringField("creator"),
+	"properties":      utils.StringField("properties"),
+	"amount":          utils.IntField("amount"),
+	"reserved":        utils.IntField("reserved"),
+	"price":           utils.IntField("price"),
+	"royaltyBps":      utils.IntField("royalty_bps"),
+	"createAt":        utils.TimeField("create_at"),
+	"brandId":         utils.StringField("brand_id"),
+	"saleWay":         utils.StringField("sale_way"),
+	"saleStatus":      utils.StringField("sale_status"),
+	"saleStartAt":     utils.TimeField("sale_start_at"),
+	"saleEndAt":       utils.TimeField("sale_end_at"),
+	"contractAddress": utils.StringField("contract_address"),
+}
+
 type CreationSelector struct {
 	CreationIDs     []primitive.ObjectID `json:"creationIDs"`
 	CreationName    *string              `json:"creationName"`
@@ -290,9 +562,12 @@ type CreationSelector struct {
 	Properties      []string             `json:"properties"`
 	SaleStartBefore *time.Time           `json:"saleStartBefore"`
 	SaleStartAfter  *time.Time           `json:"saleStartAfter"`
+	SaleEndBefore   *time.Time           `json:"saleEndBefore"`
+	SaleStatus      []SaleStatus         `json:"saleStatus"`
 	MaxPrice        *int                 `json:"maxPrice"`
 	MinPrice        *int                 `json:"minPrice"`
 	BrandID         *string              `json:"brandId"`
+	Conditions      []utils.Condition    `json:"conditions"`
 }
 
 var CreationNotFound = errors.New("creation not found")
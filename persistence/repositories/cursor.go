@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/utils"
+	"strings"
+)
+
+var (
+	ErrCursorInvalid   = errors.New("cursor is invalid")
+	ErrPageSizeInvalid = errors.New("page size must be positive")
+)
+
+// cursor is the sort key of the last document of a page, _id always comes last to break ties.
+type cursor struct {
+	Keys   []string        `bson:"k"`
+	Orders []int           `bson:"o"`
+	Values []bson.RawValue `bson:"v"`
+}
+
+func encodeCursor(c cursor) (token string, err error) {
+	raw, err := bson.Marshal(c)
+	if err != nil {
+		return
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(token string) (c cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrCursorInvalid
+	}
+	if err = bson.Unmarshal(raw, &c); err != nil {
+		return c, ErrCursorInvalid
+	}
+	if len(c.Keys) != len(c.Orders) || len(c.Keys) != len(c.Values) {
+		return c, ErrCursorInvalid
+	}
+	return
+}
+
+// keysetOf returns the sort of pageable and the filter of the documents after its cursor,
+// the filter is nil on the first page.
+func keysetOf(pageable utils.Pageable) (sorting bson.D, after bson.D, err error) {
+	if pageable.Size <= 0 {
+		return nil, nil, ErrPageSizeInvalid
+	}
+	sorting = sortOf(pageable)
+	var keys []string
+	var orders []int
+	for _, e := range sorting {
+		keys = append(keys, e.Key)
+		orders = append(orders, e.Value.(int))
+	}
+	if pageable.Cursor == nil || len(*pageable.Cursor) == 0 {
+		return
+	}
+	c, err := decodeCursor(*pageable.Cursor)
+	if err != nil {
+		return
+	}
+	// a cursor only makes sense with the sort it was made for
+	if strings.Join(c.Keys, ",") != strings.Join(keys, ",") {
+		return nil, nil, ErrCursorInvalid
+	}
+	for i := range orders {
+		if c.Orders[i] != orders[i] {
+			return nil, nil, ErrCursorInvalid
+		}
+	}
+	clauses := bson.A{}
+	for i := range keys {
+		clause := bson.D{}
+		for j := 0; j < i; j++ {
+			clause = append(clause, bson.E{Key: keys[j], Value: c.Values[j]})
+		}
+		op := "$gt"
+		if orders[i] < 0 {
+			op = "$lt"
+		}
+		clause = append(clause, bson.E{Key: keys[i], Value: bson.D{{op, c.Values[i]}}})
+		clauses = append(clauses, clause)
+	}
+	return sorting, bson.D{{"$or", clauses}}, nil
+}
+
+// nextCursorOf reads the cursor of the page ending at the last of docs.
+func nextCursorOf(docs []bson.Raw, sorting bson.D) (token string, err error) {
+	last := docs[len(docs)-1]
+	c := cursor{}
+	for _, e := range sorting {
+		value, err := last.LookupErr(strings.Split(e.Key, ".")...)
+		if err != nil {
+			value = bson.RawValue{Type: bsontype.Null}
+		}
+		c.Keys = append(c.Keys, e.Key)
+		c.Orders = append(c.Orders, e.Value.(int))
+		c.Values = append(c.Values, value)
+	}
+	return encodeCursor(c)
+}
+
+// findByCursor reads the page after the cursor of pageable, next is empty on the last page.
+func findByCursor(
+	ctx context.Context, collection *mongo.Collection, filter bson.D, pageable utils.Pageable,
+) (docs []bson.Raw, next string, err error) {
+	sorting, after, err := keysetOf(pageable)
+	if err != nil {
+		return
+	}
+	if after != nil {
+		filter = bson.D{{"$and", bson.A{filter, after}}}
+	}
+	option := options.Find().SetSort(sorting).SetLimit(int64(pageable.Size + 1))
+	cur, err := collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	return readByCursor(ctx, cur, sorting, pageable)
+}
+
+// aggregateByCursor is findByCursor for the documents coming out of pipeline.
+func aggregateByCursor(
+	ctx context.Context, collection *mongo.Collection, pipeline []bson.D, pageable utils.Pageable,
+) (docs []bson.Raw, next string, err error) {
+	sorting, after, err := keysetOf(pageable)
+	if err != nil {
+		return
+	}
+	pipelineOfPage := mongo.Pipeline{}
+	pipelineOfPage = append(pipelineOfPage, pipeline...)
+	if after != nil {
+		pipelineOfPage = append(pipelineOfPage, bson.D{{"$match", after}})
+	}
+	pipelineOfPage = append(pipelineOfPage,
+		bson.D{{"$sort", sorting}},
+		bson.D{{"$limit", pageable.Size + 1}},
+	)
+	cur, err := collection.Aggregate(ctx, pipelineOfPage)
+	if err != nil {
+		return
+	}
+	return readByCursor(ctx, cur, sorting, pageable)
+}
+
+// readByCursor reads one more document than the page holds to tell whether another page follows.
+func readByCursor(
+	ctx context.Context, cur *mongo.Cursor, sorting bson.D, pageable utils.Pageable,
+) (docs []bson.Raw, next string, err error) {
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		docs = append(docs, append(bson.Raw{}, cur.Current...))
+	}
+	if err = cur.Err(); err != nil {
+		return nil, "", err
+	}
+	if len(docs) <= pageable.Size {
+		return
+	}
+	docs = docs[:pageable.Size]
+	next, err = nextCursorOf(docs, sorting)
+	return
+}
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type DeadLetterDao interface {
+	Find(ctx context.Context, id primitive.ObjectID) (deadLetter *DeadLetter, err error)
+	Create(ctx context.Context, deadLetter *DeadLetter) (err error)
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from []DeadLetterStatus, to DeadLetterStatus) (deadLetter *DeadLetter, err error)
+	FindAllByFilter(ctx context.Context, filter DeadLetterFilter) (deadLetters []DeadLetter, err error)
+	FindAllByFilterAndPage(ctx context.Context, filter DeadLetterFilter, pageable utils.Pageable) (deadLetters *utils.Page, err error)
+}
+
+type deadLetterDao struct {
+	collection *mongo.Collection
+}
+
+func NewDeadLetterDao() (dao DeadLetterDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("dead_letter")
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{"status", 1}, {"create_at", -1}}},
+		{Keys: bson.D{{"topic", 1}, {"handler", 1}}},
+	})
+	return &deadLetterDao{col}, nil
+}
+
+func (dao *deadLetterDao) Find(ctx context.Context, id primitive.ObjectID) (deadLetter *DeadLetter, err error) {
+	deadLetter = &DeadLetter{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", id}}).Decode(deadLetter)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *deadLetterDao) Create(ctx context.Context, deadLetter *DeadLetter) (err error) {
+	if deadLetter.ID.IsZero() {
+		deadLetter.ID = primitive.NewObjectID()
+	}
+	_, err = dao.collection.InsertOne(ctx, deadLetter)
+	return
+}
+
+// UpdateStatus settles a dead letter only while it is in one of the from statuses,
+// nil is returned when it is not.
+func (dao *deadLetterDao) UpdateStatus(
+	ctx context.Context, id primitive.ObjectID, from []DeadLetterStatus, to DeadLetterStatus,
+) (deadLetter *DeadLetter, err error) {
+	deadLetter = &DeadLetter{}
+	filter := bson.D{
+		{"_id", id},
+		{"status", bson.D{{"$in", from}}},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"status", to},
+		{"update_at", time.Now()},
+	}}}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, update, option).Decode(deadLetter)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *deadLetterDao) FindAllByFilter(ctx context.Context, filter DeadLetterFilter) (deadLetters []DeadLetter, err error) {
+	cur, err := dao.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{"_id", -1}}))
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var deadLetter DeadLetter
+		err := cur.Decode(&deadLetter)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *deadLetterDao) FindAllByFilterAndPage(
+	ctx context.Context, filter DeadLetterFilter, pageable utils.Pageable,
+) (deadLetters *utils.Page, err error) {
+	total, err := dao.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return
+	}
+	deadLetters = &utils.Page{Size: pageable.Size, Page: pageable.Page, Total: total}
+	option := options.Find()
+	option.SetSkip(int64(pageable.Size * pageable.Page))
+	option.SetLimit(int64(pageable.Size))
+
+	option.SetSort(sortOf(pageable, bson.E{Key: "create_at", Value: -1}))
+
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var content []DeadLetter
+	for cur.Next(ctx) {
+		var deadLetter DeadLetter
+		err := cur.Decode(&deadLetter)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, deadLetter)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	deadLetters.Content = content
+	deadLetters.TotalPage = utils.GetTotalPage(int64(deadLetters.Size), deadLetters.Total)
+	return
+}
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterPending   DeadLetterStatus = "PENDING"
+	DeadLetterReplayed  DeadLetterStatus = "REPLAYED"
+	DeadLetterDiscarded DeadLetterStatus = "DISCARDED"
+)
+
+// DeadLetter is a message a handler gave up on, it is kept with the reason
+// until an admin replays it to its topic or discards it.
+type DeadLetter struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	UUID       string             `bson:"uuid" json:"uuid"`
+	Topic      string             `bson:"topic" json:"topic"`
+	Handler    string             `bson:"handler" json:"handler"`
+	Subscriber string             `bson:"subscriber" json:"subscriber"`
+	Payload    []byte             `bson:"payload" json:"payload"`
+	Metadata   map[string]string  `bson:"metadata" json:"metadata"`
+	Error      string             `bson:"error" json:"error"`
+	Attempts   int                `bson:"attempts" json:"attempts"`
+	Status     DeadLetterStatus   `bson:"status" json:"status"`
+	CreateAt   time.Time          `bson:"create_at" json:"createAt"`
+	UpdateAt   time.Time          `bson:"update_at" json:"updateAt"`
+}
+
+type DeadLetterFilter bson.D
+
+func SelectorOfDeadLetter(selector DeadLetterSelector) (filter DeadLetterFilter) {
+	filter = DeadLetterFilter{}
+	if selector.Topic != nil {
+		filter = append(filter, bson.E{
+			Key: "topic", Value: selector.Topic,
+		})
+	}
+
+	if selector.Handler != nil {
+		filter = append(filter, bson.E{
+			Key: "handler", Value: selector.Handler,
+		})
+	}
+
+	if len(selector.Status) > 0 {
+		filter = append(filter, bson.E{
+			Key: "status", Value: bson.D{{Key: "$in", Value: selector.Status}},
+		})
+	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
+	return
+}
+
+// DeadLetterSortFields are the fields a page of dead letters can be sorted by.
+var DeadLetterSortFields = utils.SortFields{
+	"attempts": "attempts",
+	"createAt": "create_at",
+	"updateAt": "update_at",
+}
+
+// DeadLetterFilterFields are the fields the filter of dead letters can be written on.
+var DeadLetterFilterFields = utils.FilterFields{
+	"id":       objectIdField("_id"),
+	"uuid":     utils.StringField("uuid"),
+	"topic":    utils.StringField("topic"),
+	"handler":  utils.StringField("handler"),
+	"attempts": utils.IntField("attempts"),
+	"status":   utils.StringField("status"),
+	"createAt": utils.TimeField("create_at"),
+	"updateAt": utils.TimeField("update_at"),
+}
+
+type DeadLetterSelector struct {
+	Topic      *string            `json:"topic"`
+	Handler    *string            `json:"handler"`
+	Status     []DeadLetterStatus `json:"status"`
+	Conditions []utils.Condition  `json:"conditions"`
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type EarningDao interface {
+	CreateMany(ctx context.Context, earnings []Earning) (err error)
+	SumByPayeeType(ctx context.Context, filter EarningFilter) (sums []EarningSum, err error)
+	FindAllByFilter(ctx context.Context, filter EarningFilter) (earnings []Earning, err error)
+	FindAllByFilterAndPage(ctx context.Context, filter EarningFilter, pageable utils.Pageable) (earnings *utils.Page, err error)
+}
+
+type earningDao struct {
+	collection *mongo.Collection
+}
+
+func NewEarningDao() (dao EarningDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &earningDao{db.Collection("trade_earning")}, nil
+}
+
+func (dao *earningDao) CreateMany(ctx context.Context, earnings []Earning) (err error) {
+	if len(earnings) == 0 {
+		return
+	}
+	documents := make([]interface{}, 0, len(earnings))
+	for _, earning := range earnings {
+		documents = append(documents, earning)
+	}
+	_, err = dao.collection.InsertMany(ctx, documents)
+	return
+}
+
+func (dao *earningDao) SumByPayeeType(ctx context.Context, filter EarningFilter) (sums []EarningSum, err error) {
+	pipeline := []bson.D{
+		{{"$match", filter}},
+		{{"$group", bson.D{
+			{"_id", "$payee_type"},
+			{"amount", bson.D{{"$sum", "$amount"}}},
+			{"count", bson.D{{"$sum", 1}}},
+		}}},
+		{{"$sort", bson.D{{"_id", 1}}}},
+	}
+	cur, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var sum EarningSum
+		err := cur.Decode(&sum)
+		if err != nil {
+			return nil, err
+		}
+		sums = append(sums, sum)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *earningDao) FindAllByFilter(ctx context.Context, filter EarningFilter) (earnings []Earning, err error) {
+	earnings, err = dao.findList(ctx, filter)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *earningDao) FindAllByFilterAndPage(
+	ctx context.Context, filter EarningFilter, pageable utils.Pageable,
+) (earnings *utils.Page, err error) {
+	earnings, err = dao.findPage(ctx, filter, pageable)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *earningDao) findList(ctx context.Context, filter interface{}) (earnings []Earning, err error) {
+	cur, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var earning Earning
+		err := cur.Decode(&earning)
+		if err != nil {
+			return nil, err
+		}
+		earnings = append(earnings, earning)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *earningDao) findPage(
+	ctx context.Context, filter interface{}, pageable utils.Pageable,
+) (earnings *utils.Page, err error) {
+	total, err := dao.collection.CountDocuments(ctx, filter)
+	earnings = &utils.Page{Size: pageable.Size, Page: pageable.Page, Total: total}
+	option := options.Find()
+	option.SetSkip(int64(pageable.Size * pageable.Page))
+	option.SetLimit(int64(pageable.Size))
+
+	option.SetSort(sortOf(pageable))
+
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var content []Earning
+	for cur.Next(ctx) {
+		var earning Earning
+		err := cur.Decode(&earning)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, earning)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	earnings.Content = content
+	earnings.TotalPage = utils.GetTotalPage(int64(earnings.Size), earnings.Total)
+	return
+}
+
+type PayeeType string
+
+const (
+	PayeeSeller   PayeeType = "SELLER"
+	PayeeCreator  PayeeType = "CREATOR"
+	PayeeBrand    PayeeType = "BRAND"
+	PayeePlatform PayeeType = "PLATFORM"
+)
+
+// Earning is one payee's share of a settled trade.
+type Earning struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	Payee         string             `bson:"payee" json:"payee"`
+	PayeeType     PayeeType          `bson:"payee_type" json:"payeeType"`
+	TransactionID primitive.ObjectID `bson:"transaction_id" json:"transactionId"`
+	CreationID    string             `bson:"creation_id" json:"creationId"`
+	Amount        int                `bson:"amount" json:"amount"`
+	EarnAt        time.Time          `bson:"earn_at" json:"earnAt"`
+}
+
+type EarningSum struct {
+	PayeeType PayeeType `bson:"_id" json:"payeeType"`
+	Amount    int       `bson:"amount" json:"amount"`
+	Count     int       `bson:"count" json:"count"`
+}
+
+type EarningFilter bson.D
+
+func SelectorOfEarning(selector EarningSelector) (filter EarningFilter) {
+	filter = EarningFilter{}
+	if selector.Payee != nil {
+		filter = append(filter, bson.E{
+			Key: "payee", Value: selector.Payee,
+		})
+	}
+
+	if len(selector.PayeeType) > 0 {
+		filter = append(filter, bson.E{
+			Key: "payee_type", Value: bson.D{{Key: "$in", Value: selector.PayeeType}},
+		})
+	}
+
+	if selector.EarnBefore != nil || selector.EarnAfter != nil {
+		earnFilter := bson.D{}
+		if selector.EarnAfter != nil {
+			earnFilter = append(earnFilter, bson.E{Key: "$gte", Value: selector.EarnAfter})
+		}
+		if selector.EarnBefore != nil {
+			earnFilter = append(earnFilter, bson.E{Key: "$lte", Value: selector.EarnBefore})
+		}
+		filter = append(filter, bson.E{Key: "earn_at", Value: earnFilter})
+	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
+	return
+}
+
+// EarningSortFields are the fields a page of earnings can be sorted by.
+var EarningSortFields = utils.SortFields{
+	"amount": "amount",
+	"earnAt": "earn_at",
+}
+
+// EarningFilterFields are the fields the filter of earnings can be written on.
+var EarningFilterFields = utils.FilterFields{
+	"payee":         utils.StringField("payee"),
+	"payeeType":     utils.StringField("payee_type"),
+	"transactionId": objectIdField("transaction_id"),
+	"creationId":    utils.StringField("creation_id"),
+	"amount":        utils.IntField("amount"),
+	"earnAt":        utils.TimeField("earn_at"),
+}
+
+type EarningSelector struct {
+	Payee      *string           `json:"payee"`
+	PayeeType  []PayeeType       `json:"payeeType"`
+	EarnBefore *time.Time        `json:"earnBefore"`
+	EarnAfter  *time.Time        `json:"earnAfter"`
+	Conditions []utils.Condition `json:"conditions"`
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nftshopping-store-api/pkg/utils"
+)
+
+var operatorOfCondition = map[utils.Operator]string{
+	utils.OpEq:     "$eq",
+	utils.OpNe:     "$ne",
+	utils.OpGt:     "$gt",
+	utils.OpGte:    "$gte",
+	utils.OpLt:     "$lt",
+	utils.OpLte:    "$lte",
+	utils.OpIn:     "$in",
+	utils.OpNin:    "$nin",
+	utils.OpExists: "$exists",
+}
+
+// conditionsOf compiles conditions into a filter, each of them is kept apart under $and
+// so that several conditions on the same field all apply.
+func conditionsOf(conditions []utils.Condition) (filter bson.D) {
+	if len(conditions) == 0 {
+		return
+	}
+	clauses := bson.A{}
+	for _, condition := range conditions {
+		var value interface{} = condition.Values
+		if condition.Operator != utils.OpIn && condition.Operator != utils.OpNin {
+			value = condition.Values[0]
+		}
+		clauses = append(clauses, bson.D{{condition.Key, bson.D{{operatorOfCondition[condition.Operator], value}}}})
+	}
+	return bson.D{{"$and", clauses}}
+}
+
+func objectIdField(key string) utils.FilterField {
+	return utils.FilterField{Key: key, Value: func(text string) (interface{}, error) {
+		return primitive.ObjectIDFromHex(text)
+	}}
+}
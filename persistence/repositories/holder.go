@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+)
+
+// HolderBoundaries are the lower bounds of the distribution buckets, the last one is open ended.
+var HolderBoundaries = []int{1, 2, 6, 21, 101}
+
+type HolderDao interface {
+	FindAllByFilterAndPage(ctx context.Context, filter HolderFilter, pageable utils.Pageable) (holders *utils.Page, err error)
+	Summarize(ctx context.Context, filter HolderFilter) (summary *HolderSummary, err error)
+}
+
+type holderDao struct {
+	collection *mongo.Collection
+}
+
+func NewHolderDao() (dao HolderDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &holderDao{db.Collection("creation_item")}, nil
+}
+
+// FindAllByFilterAndPage ranks the holders by the amount of items they hold, the sort of pageable is ignored.
+func (dao *holderDao) FindAllByFilterAndPage(
+	ctx context.Context, filter HolderFilter, pageable utils.Pageable,
+) (holders *utils.Page, err error) {
+	holders = &utils.Page{Size: pageable.Size, Page: pageable.Page}
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+	}
+	pipeline = append(pipeline, stageOfHolder...)
+	pipeline = append(pipeline, bson.D{{"$facet", bson.D{
+		{"total", bson.A{bson.D{{"$count", "count"}}}},
+		{"content", bson.A{
+			bson.D{{"$sort", bson.D{{"amount", -1}, {"_id", 1}}}},
+			bson.D{{"$skip", pageable.Size * pageable.Page}},
+			bson.D{{"$limit", pageable.Size}},
+		}},
+	}}})
+	cur, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var result []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Content []Holder `bson:"content"`
+	}
+	if err = cur.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	var content []Holder
+	if len(result) > 0 {
+		content = result[0].Content
+		if len(result[0].Total) > 0 {
+			holders.Total = result[0].Total[0].Count
+		}
+	}
+	holders.Content = content
+	if holders.Size > 0 {
+		holders.TotalPage = utils.GetTotalPage(int64(holders.Size), holders.Total)
+	}
+	return
+}
+
+// Summarize counts the unique holders and how many of them fall into each bucket of HolderBoundaries.
+func (dao *holderDao) Summarize(ctx context.Context, filter HolderFilter) (summary *HolderSummary, err error) {
+	boundaries := bson.A{}
+	for _, boundary := range HolderBoundaries {
+		boundaries = append(boundaries, boundary)
+	}
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+	}
+	pipeline = append(pipeline, stageOfHolder...)
+	pipeline = append(pipeline, bson.D{{"$facet", bson.D{
+		{"total", bson.A{bson.D{{"$group", bson.D{
+			{"_id", nil},
+			{"holders", bson.D{{"$sum", 1}}},
+			{"amount", bson.D{{"$sum", "$amount"}}},
+		}}}}},
+		{"distribution", bson.A{bson.D{{"$bucket", bson.D{
+			{"groupBy", "$amount"},
+			{"boundaries", boundaries},
+			{"default", HolderBoundaries[len(HolderBoundaries)-1]},
+			{"output", bson.D{
+				{"holders", bson.D{{"$sum", 1}}},
+				{"amount", bson.D{{"$sum", "$amount"}}},
+			}},
+		}}}}},
+	}}})
+	cur, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var result []struct {
+		Total []struct {
+			Holders int `bson:"holders"`
+			Amount  int `bson:"amount"`
+		} `bson:"total"`
+		Distribution []HolderBucket `bson:"distribution"`
+	}
+	if err = cur.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	summary = &HolderSummary{}
+	if len(result) > 0 {
+		summary.Distribution = result[0].Distribution
+		if len(result[0].Total) > 0 {
+			summary.Holders = result[0].Total[0].Holders
+			summary.Amount = result[0].Total[0].Amount
+		}
+	}
+	return
+}
+
+var stageOfHolder = []bson.D{
+	{
+		{"$group", bson.D{
+			{"_id", "$owner"},
+			{"amount", bson.D{{"$sum", 1}}},
+			{"creations", bson.D{{"$addToSet", "$creation_id"}}},
+		}},
+	},
+	{
+		{"$project", bson.D{
+			{"amount", 1},
+			{"creations", bson.D{{"$size", "$creations"}}},
+		}},
+	},
+}
+
+type Holder struct {
+	Owner     string `bson:"_id" json:"owner"`
+	Amount    int    `bson:"amount" json:"amount"`
+	Creations int    `bson:"creations" json:"creations"`
+}
+
+type HolderBucket struct {
+	Min     int `bson:"_id" json:"min"`
+	Holders int `bson:"holders" json:"holders"`
+	Amount  int `bson:"amount" json:"amount"`
+}
+
+type HolderSummary struct {
+	Holders      int            `json:"holders"`
+	Amount       int            `json:"amount"`
+	Distribution []HolderBucket `json:"distribution"`
+}
+
+type HolderFilter bson.D
+
+// SelectorOfHolder only counts delivered items, an item waiting for delivery has no owner yet.
+func SelectorOfHolder(selector HolderSelector) (filter HolderFilter) {
+	filter = HolderFilter{
+		{"owner", bson.D{{"$nin", bson.A{"", nil}}}},
+	}
+	if selector.CreationID != nil {
+		filter = append(filter, bson.E{
+			Key: "creation_id", Value: selector.CreationID,
+		})
+	}
+	if selector.BrandID != nil {
+		filter = append(filter, bson.E{
+			Key: "brand_owner", Value: selector.BrandID,
+		})
+	}
+	return
+}
+
+type HolderSelector struct {
+	CreationID *primitive.ObjectID `json:"creationId"`
+	BrandID    *string             `json:"brandId"`
+}
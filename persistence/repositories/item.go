@@ -19,10 +19,14 @@ type ItemDao interface {
 	Delete(ctx context.Context, id ItemID) (err error)
 	CountByBrandOwner(ctx context.Context, brandOwner string) (amount int64, err error)
 	CountByOwner(ctx context.Context, owner string) (amount int64, err error)
+	CountByCreation(ctx context.Context, creationId primitive.ObjectID) (amount int64, err error)
 	FindAll(ctx context.Context) (items []Item, err error)
 	FindAllByPage(ctx context.Context, pageable utils.Pageable) (items *utils.Page, err error)
 	FindAllByFilter(ctx context.Context, filter ItemFilter) (items []Item, err error)
 	FindAllByFilterAndPage(ctx context.Context, filter ItemFilter, pageable utils.Pageable) (items *utils.Page, err error)
+	FindAllByFilterAndCursor(ctx context.Context, filter ItemFilter, pageable utils.Pageable) (items *utils.Page, err error)
+	FindAllByFilterAndLimit(ctx context.Context, filter ItemFilter, limit int) (items []Item, err error)
+	UpdateOwner(ctx context.Context, ids []ItemID, from, to string) (amount int64, err error)
 }
 
 type itemDao struct {
@@ -112,6 +116,14 @@ func (dao *itemDao) CountByOwner(ctx context.Context, owner string) (amount int6
 	return
 }
 
+func (dao *itemDao) CountByCreation(ctx context.Context, creationId primitive.ObjectID) (amount int64, err error) {
+	amount, err = dao.collection.CountDocuments(ctx, bson.D{{"creation_id", creationId}})
+	if err != nil {
+		return
+	}
+	return
+}
+
 func (dao *itemDao) FindAll(ctx context.Context) (items []Item, err error) {
 	items, err = dao.findList(ctx, bson.D{})
 	if err != nil {
@@ -147,8 +159,56 @@ func (dao *itemDao) FindAllByFilterAndPage(
 	return
 }
 
-func (dao *itemDao) findList(ctx context.Context, filter interface{}) (items []Item, err error) {
-	cur, err := dao.collection.Find(ctx, filter)
+func (dao *itemDao) FindAllByFilterAndCursor(
+	ctx context.Context, filter ItemFilter, pageable utils.Pageable,
+) (items *utils.Page, err error) {
+	docs, next, err := findByCursor(ctx, dao.collection, bson.D(filter), pageable)
+	if err != nil {
+		return
+	}
+	items = &utils.Page{Size: pageable.Size, NextCursor: next}
+	var content []Item
+	for _, doc := range docs {
+		var item Item
+		if err = bson.Unmarshal(doc, &item); err != nil {
+			return nil, err
+		}
+		content = append(content, item)
+	}
+	items.Content = content
+	return
+}
+
+func (dao *itemDao) FindAllByFilterAndLimit(
+	ctx context.Context, filter ItemFilter, limit int,
+) (items []Item, err error) {
+	option := options.Find().SetLimit(int64(limit))
+	items, err = dao.findList(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *itemDao) UpdateOwner(ctx context.Context, ids []ItemID, from, to string) (amount int64, err error) {
+	filter := bson.D{
+		{"_id", bson.D{{"$in", ids}}},
+		{"owner", from},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"owner", to},
+	}}}
+	result, err := dao.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return
+	}
+	return result.ModifiedCount, nil
+}
+
+func (dao *itemDao) findList(
+	ctx context.Context, filter interface{}, opts ...*options.FindOptions,
+) (items []Item, err error) {
+	cur, err := dao.collection.Find(ctx, filter, opts...)
 	if err != nil {
 		return
 	}
@@ -175,6 +235,7 @@ func (dao *itemDao) findPage(
 	option := options.Find()
 	option.SetSkip(int64(pageable.Size * pageable.Page))
 	option.SetLimit(int64(pageable.Size))
+	option.SetSort(sortOf(pageable))
 	cur, err := dao.collection.Find(ctx, filter, option)
 	if err != nil {
 		return
@@ -213,6 +274,11 @@ type ItemFilter bson.D
 
 func SelectorOfItem(selector ItemSelector) (filter ItemFilter) {
 	filter = ItemFilter{}
+	if selector.CreationID != nil {
+		filter = append(filter, bson.E{
+			Key: "creation_id", Value: selector.CreationID,
+		})
+	}
 	if selector.Owner != nil {
 		filter = append(filter, bson.E{
 			Key: "owner", Value: selector.Owner,
@@ -223,12 +289,32 @@ func SelectorOfItem(selector ItemSelector) (filter ItemFilter) {
 			Key: "brand_owner", Value: selector.BrandOwner,
 		})
 	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
 	return
 }
 
+// ItemSortFields are the fields a page of items can be sorted by.
+var ItemSortFields = utils.SortFields{
+	"contract":   "_id.contract",
+	"token":      "_id.token",
+	"owner":      "owner",
+	"creationId": "creation_id",
+}
+
+// ItemFilterFields are the fields the filter of items can be written on.
+var ItemFilterFields = utils.FilterFields{
+	"contract":   utils.StringField("_id.contract"),
+	"token":      utils.StringField("_id.token"),
+	"creationId": objectIdField("creation_id"),
+	"owner":      utils.StringField("owner"),
+	"brandOwner": utils.StringField("brand_owner"),
+}
+
 type ItemSelector struct {
-	Owner      *string `json:"owner"`
-	BrandOwner *string `json:"brandOwner"`
+	CreationID *primitive.ObjectID `json:"creationId"`
+	Owner      *string             `json:"owner"`
+	BrandOwner *string             `json:"brandOwner"`
+	Conditions []utils.Condition   `json:"conditions"`
 }
 
 type ItemDetailDao interface {
@@ -347,15 +433,9 @@ func (dao *itemDetailDao) findPage(
 
 	pipelineOfPage := mongo.Pipeline{}
 	pageStage := []bson.D{
-		{{"$limit", pageable.Size}},
+		{{"$sort", sortOf(pageable)}},
 		{{"$skip", pageable.Size * pageable.Page}},
-	}
-	if len(pageable.Sort) > 0 {
-		sort := bson.D{}
-		for key, value := range pageable.Sort {
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		pageStage = append(pageStage, bson.D{{"$sort", sort}})
+		{{"$limit", pageable.Size}},
 	}
 
 	pipelineOfPage = append(pipelineOfPage, pipeline...)
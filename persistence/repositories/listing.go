@@ -0,0 +1,278 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type ListingDao interface {
+	Find(ctx context.Context, listingId primitive.ObjectID) (listing *Listing, err error)
+	Create(ctx context.Context, listing *Listing) (err error)
+	UpdateStatus(ctx context.Context, listingId primitive.ObjectID, from, to ListingStatus, tnxId *primitive.ObjectID) (listing *Listing, err error)
+	CancelByItem(ctx context.Context, itemIds ...ItemID) (err error)
+	FindAllByFilter(ctx context.Context, filter ListingFilter) (listings []Listing, err error)
+	FindAllByFilterAndPage(ctx context.Context, filter ListingFilter, pageable utils.Pageable) (listings *utils.Page, err error)
+}
+
+type listingDao struct {
+	collection *mongo.Collection
+}
+
+func NewListingDao() (dao ListingDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("item_listing")
+	// an item can only be listed once at a time
+	opt := options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"status", ListingActive}})
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{"item.contract", 1},
+				{"item.token", 1},
+			}, Options: opt,
+		},
+	})
+	return &listingDao{col}, nil
+}
+
+func (dao *listingDao) Create(ctx context.Context, listing *Listing) (err error) {
+	_, err = dao.collection.InsertOne(ctx, listing)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ListingDuplicate
+		}
+		return
+	}
+	return
+}
+
+func (dao *listingDao) Find(ctx context.Context, listingId primitive.ObjectID) (listing *Listing, err error) {
+	listing = &Listing{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", listingId}}).Decode(listing)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *listingDao) UpdateStatus(
+	ctx context.Context, listingId primitive.ObjectID, from, to ListingStatus, tnxId *primitive.ObjectID,
+) (listing *Listing, err error) {
+	listing = &Listing{}
+	filter := bson.D{
+		{"_id", listingId},
+		{"status", from},
+	}
+	set := bson.D{
+		{"status", to},
+		{"update_at", time.Now()},
+	}
+	if tnxId != nil {
+		set = append(set, bson.E{Key: "transaction_id", Value: tnxId})
+	}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, bson.D{{"$set", set}}, option).Decode(listing)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+// CancelByItem cancels the active listings of items that have just changed hands.
+func (dao *listingDao) CancelByItem(ctx context.Context, itemIds ...ItemID) (err error) {
+	filter := bson.D{
+		{"item", bson.D{{"$in", itemIds}}},
+		{"status", ListingActive},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"status", ListingCancelled},
+		{"update_at", time.Now()},
+	}}}
+	_, err = dao.collection.UpdateMany(ctx, filter, update)
+	return
+}
+
+func (dao *listingDao) FindAllByFilter(ctx context.Context, filter ListingFilter) (listings []Listing, err error) {
+	listings, err = dao.findList(ctx, filter)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *listingDao) FindAllByFilterAndPage(
+	ctx context.Context, filter ListingFilter, pageable utils.Pageable,
+) (listings *utils.Page, err error) {
+	listings, err = dao.findPage(ctx, filter, pageable)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *listingDao) findList(ctx context.Context, filter interface{}) (listings []Listing, err error) {
+	cur, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var listing Listing
+		err := cur.Decode(&listing)
+		if err != nil {
+			return nil, err
+		}
+		listings = append(listings, listing)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *listingDao) findPage(
+	ctx context.Context, filter interface{}, pageable utils.Pageable,
+) (listings *utils.Page, err error) {
+	total, err := dao.collection.CountDocuments(ctx, filter)
+	listings = &utils.Page{Size: pageable.Size, Page: pageable.Page, Total: total}
+	option := options.Find()
+	option.SetSkip(int64(pageable.Size * pageable.Page))
+	option.SetLimit(int64(pageable.Size))
+
+	option.SetSort(sortOf(pageable))
+
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var content []Listing
+	for cur.Next(ctx) {
+		var listing Listing
+		err := cur.Decode(&listing)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, listing)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	listings.Content = content
+	listings.TotalPage = utils.GetTotalPage(int64(listings.Size), listings.Total)
+	return
+}
+
+type ListingStatus string
+
+const (
+	ListingActive    ListingStatus = "ACTIVE"
+	ListingSold      ListingStatus = "SOLD"
+	ListingCancelled ListingStatus = "CANCELLED"
+)
+
+type Listing struct {
+	ID            primitive.ObjectID  `bson:"_id" json:"id"`
+	Item          ItemID              `bson:"item" json:"item"`
+	CreationID    primitive.ObjectID  `bson:"creation_id" json:"creationId"`
+	BrandID       string              `bson:"brand_id" json:"brandId"`
+	Seller        string              `bson:"seller" json:"seller"`
+	Price         int                 `bson:"price" json:"price"`
+	Status        ListingStatus       `bson:"status" json:"status"`
+	TransactionID *primitive.ObjectID `bson:"transaction_id,omitempty" json:"transactionId"`
+	CreateAt      time.Time           `bson:"create_at" json:"createAt"`
+	UpdateAt      time.Time           `bson:"update_at" json:"updateAt"`
+}
+
+type ListingFilter bson.D
+
+func SelectorOfListing(selector ListingSelector) (filter ListingFilter) {
+	filter = ListingFilter{}
+	if selector.CreationIDs != nil {
+		filter = append(filter, bson.E{
+			Key: "creation_id", Value: bson.D{{Key: "$in", Value: selector.CreationIDs}},
+		})
+	}
+
+	if selector.BrandID != nil {
+		filter = append(filter, bson.E{
+			Key: "brand_id", Value: selector.BrandID,
+		})
+	}
+
+	if selector.Seller != nil {
+		filter = append(filter, bson.E{
+			Key: "seller", Value: selector.Seller,
+		})
+	}
+
+	if len(selector.Status) > 0 {
+		filter = append(filter, bson.E{
+			Key: "status", Value: bson.D{{Key: "$in", Value: selector.Status}},
+		})
+	}
+
+	if selector.MaxPrice != nil || selector.MinPrice != nil {
+		priceFilter := bson.D{}
+		if selector.MinPrice != nil {
+			priceFilter = append(priceFilter, bson.E{Key: "$gte", Value: selector.MinPrice})
+		}
+		if selector.MaxPrice != nil {
+			priceFilter = append(priceFilter, bson.E{Key: "$lte", Value: selector.MaxPrice})
+		}
+		filter = append(filter, bson.E{Key: "price", Value: priceFilter})
+	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
+	return
+}
+
+// ListingSortFields are the fields a page of listings can be sorted by.
+var ListingSortFields = utils.SortFields{
+	"price":    "price",
+	"createAt": "create_at",
+	"updateAt": "update_at",
+}
+
+// ListingFilterFields are the fields the filter of listings can be written on.
+var ListingFilterFields = utils.FilterFields{
+	"id":         objectIdField("_id"),
+	"contract":   utils.StringField("item.contract"),
+	"token":      utils.StringField("item.token"),
+	"creationId": objectIdField("creation_id"),
+	"brandId":    utils.StringField("brand_id"),
+	"seller":     utils.StringField("seller"),
+	"price":      utils.IntField("price"),
+	"status":     utils.StringField("status"),
+	"createAt":   utils.TimeField("create_at"),
+	"updateAt":   utils.TimeField("update_at"),
+}
+
+type ListingSelector struct {
+	CreationIDs []primitive.ObjectID `json:"creationIds"`
+	BrandID     *string              `json:"brandId"`
+	Seller      *string              `json:"seller"`
+	Status      []ListingStatus      `json:"status"`
+	MaxPrice    *int                 `json:"maxPrice"`
+	MinPrice    *int                 `json:"minPrice"`
+	Conditions  []utils.Condition    `json:"conditions"`
+}
+
+var (
+	ListingNotFound  = errors.New("listing not found")
+	ListingDuplicate = errors.New("item has been listed")
+)
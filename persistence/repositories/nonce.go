@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+type Nonce struct {
+	Nonce    string    `bson:"_id"`
+	Account  string    `bson:"account"`
+	Message  string    `bson:"message"`
+	IssueAt  time.Time `bson:"issue_at"`
+	ExpireAt time.Time `bson:"expire_at"`
+}
+
+type NonceDao interface {
+	Create(ctx context.Context, nonce *Nonce) (err error)
+	Consume(ctx context.Context, account, nonce string, at time.Time) (consumed *Nonce, err error)
+}
+
+type nonceDao struct {
+	collection *mongo.Collection
+}
+
+func NewNonceDao() (dao NonceDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("auth_nonce")
+	// mongo removes expired nonces by itself
+	opt := options.Index().SetExpireAfterSeconds(0)
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.M{
+				"expire_at": 1,
+			}, Options: opt,
+		},
+	})
+	return &nonceDao{col}, nil
+}
+
+func (dao *nonceDao) Create(ctx context.Context, nonce *Nonce) (err error) {
+	_, err = dao.collection.InsertOne(ctx, nonce)
+	return
+}
+
+// Consume deletes the nonce issued to account and returns it, so it can only be used once.
+// A nonce past its expire time is left for the ttl index and nil is returned.
+func (dao *nonceDao) Consume(ctx context.Context, account, nonce string, at time.Time) (consumed *Nonce, err error) {
+	consumed = &Nonce{}
+	filter := bson.D{
+		{"_id", nonce},
+		{"account", account},
+		{"expire_at", bson.D{{"$gt", at}}},
+	}
+	err = dao.collection.FindOneAndDelete(ctx, filter).Decode(consumed)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
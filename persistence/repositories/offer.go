@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type OfferDao interface {
+	Find(ctx context.Context, offerId primitive.ObjectID) (offer *Offer, err error)
+	Create(ctx context.Context, offer *Offer) (err error)
+	UpdateStatus(ctx context.Context, offerId primitive.ObjectID, from, to OfferStatus, tnxId *primitive.ObjectID) (offer *Offer, err error)
+	RejectByItem(ctx context.Context, itemIds ...ItemID) (err error)
+	Expire(ctx context.Context, at time.Time) (amount int64, err error)
+	FindAllByFilter(ctx context.Context, filter OfferFilter) (offers []Offer, err error)
+	FindAllByFilterAndPage(ctx context.Context, filter OfferFilter, pageable utils.Pageable) (offers *utils.Page, err error)
+}
+
+type offerDao struct {
+	collection *mongo.Collection
+}
+
+func NewOfferDao() (dao OfferDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &offerDao{db.Collection("item_offer")}, nil
+}
+
+func (dao *offerDao) Create(ctx context.Context, offer *Offer) (err error) {
+	_, err = dao.collection.InsertOne(ctx, offer)
+	return
+}
+
+func (dao *offerDao) Find(ctx context.Context, offerId primitive.ObjectID) (offer *Offer, err error) {
+	offer = &Offer{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", offerId}}).Decode(offer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *offerDao) UpdateStatus(
+	ctx context.Context, offerId primitive.ObjectID, from, to OfferStatus, tnxId *primitive.ObjectID,
+) (offer *Offer, err error) {
+	offer = &Offer{}
+	now := time.Now()
+	filter := bson.D{
+		{"_id", offerId},
+		{"status", from},
+	}
+	if from == OfferPending {
+		filter = append(filter, bson.E{Key: "expire_at", Value: bson.D{{"$gt", now}}})
+	}
+	set := bson.D{
+		{"status", to},
+		{"update_at", now},
+	}
+	if tnxId != nil {
+		set = append(set, bson.E{Key: "transaction_id", Value: tnxId})
+	}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, bson.D{{"$set", set}}, option).Decode(offer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+// RejectByItem rejects the pending offers on items that have just changed hands.
+func (dao *offerDao) RejectByItem(ctx context.Context, itemIds ...ItemID) (err error) {
+	filter := bson.D{
+		{"item", bson.D{{"$in", itemIds}}},
+		{"status", OfferPending},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"status", OfferRejected},
+		{"update_at", time.Now()},
+	}}}
+	_, err = dao.collection.UpdateMany(ctx, filter, update)
+	return
+}
+
+func (dao *offerDao) Expire(ctx context.Context, at time.Time) (amount int64, err error) {
+	filter := bson.D{
+		{"status", OfferPending},
+		{"expire_at", bson.D{{"$lte", at}}},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"status", OfferExpired},
+		{"update_at", at},
+	}}}
+	result, err := dao.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return
+	}
+	return result.ModifiedCount, nil
+}
+
+func (dao *offerDao) FindAllByFilter(ctx context.Context, filter OfferFilter) (offers []Offer, err error) {
+	offers, err = dao.findList(ctx, filter)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *offerDao) FindAllByFilterAndPage(
+	ctx context.Context, filter OfferFilter, pageable utils.Pageable,
+) (offers *utils.Page, err error) {
+	offers, err = dao.findPage(ctx, filter, pageable)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *offerDao) findList(ctx context.Context, filter interface{}) (offers []Offer, err error) {
+	cur, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var offer Offer
+		err := cur.Decode(&offer)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, offer)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *offerDao) findPage(
+	ctx context.Context, filter interface{}, pageable utils.Pageable,
+) (offers *utils.Page, err error) {
+	total, err := dao.collection.CountDocuments(ctx, filter)
+	offers = &utils.Page{Size: pageable.Size, Page: pageable.Page, Total: total}
+	option := options.Find()
+	option.SetSkip(int64(pageable.Size * pageable.Page))
+	option.SetLimit(int64(pageable.Size))
+
+	option.SetSort(sortOf(pageable))
+
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var content []Offer
+	for cur.Next(ctx) {
+		var offer Offer
+		err := cur.Decode(&offer)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, offer)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	offers.Content = content
+	offers.TotalPage = utils.GetTotalPage(int64(offers.Size), offers.Total)
+	return
+}
+
+type OfferStatus string
+
+const (
+	OfferPending  OfferStatus = "PENDING"
+	OfferAccepted OfferStatus = "ACCEPTED"
+	OfferRejected OfferStatus = "REJECTED"
+	OfferExpired  OfferStatus = "EXPIRED"
+)
+
+type Offer struct {
+	ID            primitive.ObjectID  `bson:"_id" json:"id"`
+	Item          ItemID              `bson:"item" json:"item"`
+	CreationID    primitive.ObjectID  `bson:"creation_id" json:"creationId"`
+	BrandID       string              `bson:"brand_id" json:"brandId"`
+	Buyer         string              `bson:"buyer" json:"buyer"`
+	Seller        string              `bson:"seller" json:"seller"`
+	Price         int                 `bson:"price" json:"price"`
+	Status        OfferStatus         `bson:"status" json:"status"`
+	TransactionID *primitive.ObjectID `bson:"transaction_id,omitempty" json:"transactionId"`
+	CreateAt      time.Time           `bson:"create_at" json:"createAt"`
+	UpdateAt      time.Time           `bson:"update_at" json:"updateAt"`
+	ExpireAt      time.Time           `bson:"expire_at" json:"expireAt"`
+}
+
+type OfferFilter bson.D
+
+func SelectorOfOffer(selector OfferSelector) (filter OfferFilter) {
+	filter = OfferFilter{}
+	if selector.Item != nil {
+		filter = append(filter, bson.E{
+			Key: "item", Value: selector.Item,
+		})
+	}
+
+	if selector.Buyer != nil {
+		filter = append(filter, bson.E{
+			Key: "buyer", Value: selector.Buyer,
+		})
+	}
+
+	if selector.Seller != nil {
+		filter = append(filter, bson.E{
+			Key: "seller", Value: selector.Seller,
+		})
+	}
+
+	if len(selector.Status) > 0 {
+		filter = append(filter, bson.E{
+			Key: "status", Value: bson.D{{Key: "$in", Value: selector.Status}},
+		})
+	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
+	return
+}
+
+// OfferSortFields are the fields a page of offers can be sorted by.
+var OfferSortFields = utils.SortFields{
+	"price":    "price",
+	"createAt": "create_at",
+	"updateAt": "update_at",
+	"expireAt": "expire_at",
+}
+
+// OfferFilterFields are the fields the filter of offers can be written on.
+var OfferFilterFields = utils.FilterFields{
+	"id":         objectIdField("_id"),
+	"contract":   utils.StringField("item.contract"),
+	"token":      utils.StringField("item.token"),
+	"creationId": objectIdField("creation_id"),
+	"brandId":    utils.StringField("brand_id"),
+	"buyer":      utils.StringField("buyer"),
+	"seller":     utils.StringField("seller"),
+	"price":      utils.IntField("price"),
+	"status":     utils.StringField("status"),
+	"createAt":   utils.TimeField("create_at"),
+	"updateAt":   utils.TimeField("update_at"),
+	"expireAt":   utils.TimeField("expire_at"),
+}
+
+type OfferSelector struct {
+	Item       *ItemID           `json:"item"`
+	Buyer      *string           `json:"buyer"`
+	Seller     *string           `json:"seller"`
+	Status     []OfferStatus     `json:"status"`
+	Conditions []utils.Condition `json:"conditions"`
+}
+
+var OfferNotFound = errors.New("offer not found")
@@ -0,0 +1,303 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"nftshopping-store-api/pkg/utils"
+	"time"
+)
+
+type OrderDao interface {
+	Find(ctx context.Context, orderId primitive.ObjectID) (order *Order, err error)
+	Create(ctx context.Context, order *Order) (err error)
+	Deliver(ctx context.Context, orderId primitive.ObjectID, itemId ItemID) (order *Order, err error)
+	UpdateStatus(ctx context.Context, orderId primitive.ObjectID, from []OrderStatus, to OrderStatus) (order *Order, err error)
+	Fail(ctx context.Context, orderId primitive.ObjectID, reason string) (order *Order, err error)
+	FindAllByFilter(ctx context.Context, filter OrderFilter) (orders []Order, err error)
+	FindAllByFilterAndPage(ctx context.Context, filter OrderFilter, pageable utils.Pageable) (orders *utils.Page, err error)
+}
+
+type orderDao struct {
+	collection *mongo.Collection
+}
+
+func NewOrderDao() (dao OrderDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return &orderDao{db.Collection("order")}, nil
+}
+
+func (dao *orderDao) Create(ctx context.Context, order *Order) (err error) {
+	_, err = dao.collection.InsertOne(ctx, order)
+	return
+}
+
+func (dao *orderDao) Find(ctx context.Context, orderId primitive.ObjectID) (order *Order, err error) {
+	order = &Order{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", orderId}}).Decode(order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *orderDao) Deliver(
+	ctx context.Context, orderId primitive.ObjectID, itemId ItemID,
+) (order *Order, err error) {
+	order = &Order{}
+	filter := bson.D{
+		{"_id", orderId},
+		{"status", bson.D{{"$in", OrderStatusOfDeliverable}}},
+		{"items", bson.D{{"$ne", itemId}}},
+		{"$expr", bson.D{{"$lt", bson.A{"$delivered", "$amount"}}}},
+	}
+	update := bson.D{
+		{"$inc", bson.D{
+			{"delivered", 1},
+		}},
+		{"$push", bson.D{
+			{"items", itemId},
+		}},
+		{"$set", bson.D{
+			{"update_at", time.Now()},
+		}},
+	}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, update, option).Decode(order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *orderDao) UpdateStatus(
+	ctx context.Context, orderId primitive.ObjectID, from []OrderStatus, to OrderStatus,
+) (order *Order, err error) {
+	order = &Order{}
+	filter := bson.D{
+		{"_id", orderId},
+		{"status", bson.D{{"$in", from}}},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"status", to},
+		{"update_at", time.Now()},
+	}}}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, update, option).Decode(order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *orderDao) Fail(ctx context.Context, orderId primitive.ObjectID, reason string) (order *Order, err error) {
+	order = &Order{}
+	filter := bson.D{
+		{"_id", orderId},
+		{"status", bson.D{{"$in", OrderStatusOfDeliverable}}},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"status", OrderFailed},
+		{"reason", reason},
+		{"update_at", time.Now()},
+	}}}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, filter, update, option).Decode(order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *orderDao) FindAllByFilter(ctx context.Context, filter OrderFilter) (orders []Order, err error) {
+	orders, err = dao.findList(ctx, filter)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *orderDao) FindAllByFilterAndPage(
+	ctx context.Context, filter OrderFilter, pageable utils.Pageable,
+) (orders *utils.Page, err error) {
+	orders, err = dao.findPage(ctx, filter, pageable)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func (dao *orderDao) findList(ctx context.Context, filter interface{}) (orders []Order, err error) {
+	cur, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var order Order
+		err := cur.Decode(&order)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *orderDao) findPage(
+	ctx context.Context, filter interface{}, pageable utils.Pageable,
+) (orders *utils.Page, err error) {
+	total, err := dao.collection.CountDocuments(ctx, filter)
+	orders = &utils.Page{Size: pageable.Size, Page: pageable.Page, Total: total}
+	option := options.Find()
+	option.SetSkip(int64(pageable.Size * pageable.Page))
+	option.SetLimit(int64(pageable.Size))
+
+	option.SetSort(sortOf(pageable))
+
+	cur, err := dao.collection.Find(ctx, filter, option)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var content []Order
+	for cur.Next(ctx) {
+		var order Order
+		err := cur.Decode(&order)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, order)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	orders.Content = content
+	orders.TotalPage = utils.GetTotalPage(int64(orders.Size), orders.Total)
+	return
+}
+
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "PENDING"
+	OrderMinting   OrderStatus = "MINTING"
+	OrderDelivered OrderStatus = "DELIVERED"
+	OrderFailed    OrderStatus = "FAILED"
+)
+
+// OrderStatusOfDeliverable are the statuses in which an order still accepts delivered items.
+var OrderStatusOfDeliverable = []OrderStatus{OrderPending, OrderMinting}
+
+type Order struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	CreationID primitive.ObjectID `bson:"creation_id" json:"creationId"`
+	BrandID    string             `bson:"brand_id" json:"brandId"`
+	Buyer      string             `bson:"buyer" json:"buyer"`
+	Amount     int                `bson:"amount" json:"amount"`
+	Delivered  int                `bson:"delivered" json:"delivered"`
+	Price      int                `bson:"price" json:"price"`
+	Items      []ItemID           `bson:"items" json:"items"`
+	Status     OrderStatus        `bson:"status" json:"status"`
+	Reason     string             `bson:"reason" json:"reason"`
+	CreateAt   time.Time          `bson:"create_at" json:"createAt"`
+	UpdateAt   time.Time          `bson:"update_at" json:"updateAt"`
+	ExpireAt   time.Time          `bson:"expire_at" json:"expireAt"`
+}
+
+type OrderFilter bson.D
+
+func SelectorOfOrder(selector OrderSelector) (filter OrderFilter) {
+	filter = OrderFilter{}
+	if selector.Buyer != nil {
+		filter = append(filter, bson.E{
+			Key: "buyer", Value: selector.Buyer,
+		})
+	}
+
+	if selector.CreationID != nil {
+		filter = append(filter, bson.E{
+			Key: "creation_id", Value: selector.CreationID,
+		})
+	}
+
+	if len(selector.Status) > 0 {
+		filter = append(filter, bson.E{
+			Key: "status", Value: bson.D{{Key: "$in", Value: selector.Status}},
+		})
+	}
+
+	if selector.CreateBefore != nil || selector.CreateAfter != nil {
+		createFilter := bson.D{}
+		if selector.CreateAfter != nil {
+			createFilter = append(createFilter, bson.E{Key: "$gte", Value: selector.CreateAfter})
+		}
+		if selector.CreateBefore != nil {
+			createFilter = append(createFilter, bson.E{Key: "$lte", Value: selector.CreateBefore})
+		}
+		filter = append(filter, bson.E{Key: "create_at", Value: createFilter})
+	}
+
+	if selector.ExpireBefore != nil {
+		filter = append(filter, bson.E{
+			Key: "expire_at", Value: bson.D{{Key: "$lte", Value: selector.ExpireBefore}},
+		})
+	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
+	return
+}
+
+// OrderSortFields are the fields a page of orders can be sorted by.
+var OrderSortFields = utils.SortFields{
+	"amount":   "amount",
+	"price":    "price",
+	"createAt": "create_at",
+	"updateAt": "update_at",
+	"expireAt": "expire_at",
+}
+
+// OrderFilterFields are the fields the filter of orders can be written on.
+var OrderFilterFields = utils.FilterFields{
+	"id":         objectIdField("_id"),
+	"creationId": objectIdField("creation_id"),
+	"brandId":    utils.StringField("brand_id"),
+	"buyer":      utils.StringField("buyer"),
+	"amount":     utils.IntField("amount"),
+	"delivered":  utils.IntField("delivered"),
+	"price":      utils.IntField("price"),
+	"status":     utils.StringField("status"),
+	"createAt":   utils.TimeField("create_at"),
+	"updateAt":   utils.TimeField("update_at"),
+	"expireAt":   utils.TimeField("expire_at"),
+}
+
+type OrderSelector struct {
+	Buyer        *string             `json:"buyer"`
+	CreationID   *primitive.ObjectID `json:"creationId"`
+	Status       []OrderStatus       `json:"status"`
+	CreateBefore *time.Time          `json:"createBefore"`
+	CreateAfter  *time.Time          `json:"createAfter"`
+	ExpireBefore *time.Time          `json:"expireBefore"`
+	Conditions   []utils.Condition   `json:"conditions"`
+}
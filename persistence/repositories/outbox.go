@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxPublished OutboxStatus = "published"
+	OutboxParked    OutboxStatus = "parked"
+)
+
+// Outbox is an event written in the same session as the change it tells about,
+// it waits here until the relay has handed it to the broker.
+type Outbox struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	Topic         string             `bson:"topic" json:"topic"`
+	AggregateType string             `bson:"aggregate_type" json:"aggregateType"`
+	AggregateID   string             `bson:"aggregate_id" json:"aggregateId"`
+	Payload       []byte             `bson:"payload" json:"payload"`
+	Metadata      map[string]string  `bson:"metadata" json:"metadata"`
+	Status        OutboxStatus       `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	LastError     string             `bson:"last_error" json:"lastError"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"nextAttemptAt"`
+	CreateAt      time.Time          `bson:"create_at" json:"createAt"`
+	PublishAt     *time.Time         `bson:"publish_at,omitempty" json:"publishAt"`
+}
+
+type OutboxDao interface {
+	Create(ctx context.Context, outbox *Outbox) (err error)
+	FindAllPending(ctx context.Context, at time.Time, limit int) (outboxes []Outbox, err error)
+	MarkPublished(ctx context.Context, id primitive.ObjectID, at time.Time) (err error)
+	MarkFailed(ctx context.Context, id primitive.ObjectID, reason string, next time.Time) (err error)
+	MarkParked(ctx context.Context, id primitive.ObjectID, reason string) (err error)
+	DeletePublishedBefore(ctx context.Context, before time.Time) (amount int64, err error)
+	AcquireLease(ctx context.Context, owner string, until time.Time) (acquired bool, err error)
+}
+
+type outboxDao struct {
+	collection *mongo.Collection
+	lease      *mongo.Collection
+}
+
+func NewOutboxDao() (dao OutboxDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("outbox")
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{"status", 1}, {"_id", 1}}},
+		{Keys: bson.D{{"status", 1}, {"aggregate_type", 1}, {"aggregate_id", 1}, {"_id", 1}}},
+		{Keys: bson.D{{"status", 1}, {"publish_at", 1}}},
+	})
+	return &outboxDao{
+		collection: col,
+		lease:      db.Collection("outbox_lease"),
+	}, nil
+}
+
+func (dao *outboxDao) Create(ctx context.Context, outbox *Outbox) (err error) {
+	if outbox.ID.IsZero() {
+		outbox.ID = primitive.NewObjectID()
+	}
+	_, err = dao.collection.InsertOne(ctx, outbox)
+	return
+}
+
+// FindAllPending reads the oldest pending event of every aggregate, in the order they
+// were written, if it is due at the given time. An aggregate whose head waits for its
+// next attempt is left out along with the rest of its events.
+func (dao *outboxDao) FindAllPending(ctx context.Context, at time.Time, limit int) (outboxes []Outbox, err error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"status", OutboxPending}}}},
+		{{"$sort", bson.D{{"_id", 1}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"type", "$aggregate_type"}, {"id", "$aggregate_id"}}},
+			{"head", bson.D{{"$first", "$$ROOT"}}},
+		}}},
+		{{"$replaceRoot", bson.D{{"newRoot", "$head"}}}},
+		{{"$match", bson.D{{"next_attempt_at", bson.D{{"$lte", at}}}}}},
+		{{"$sort", bson.D{{"_id", 1}}}},
+		{{"$limit", limit}},
+	}
+	cur, err := dao.collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var outbox Outbox
+		if err := cur.Decode(&outbox); err != nil {
+			return nil, err
+		}
+		outboxes = append(outboxes, outbox)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (dao *outboxDao) MarkPublished(ctx context.Context, id primitive.ObjectID, at time.Time) (err error) {
+	update := bson.D{
+		{"$set", bson.D{
+			{"status", OutboxPublished},
+			{"publish_at", at},
+		}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	}
+	_, err = dao.collection.UpdateOne(ctx, bson.D{{"_id", id}}, update)
+	return
+}
+
+func (dao *outboxDao) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string, next time.Time) (err error) {
+	update := bson.D{
+		{"$set", bson.D{
+			{"last_error", reason},
+			{"next_attempt_at", next},
+		}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	}
+	_, err = dao.collection.UpdateOne(ctx, bson.D{{"_id", id}}, update)
+	return
+}
+
+// MarkParked gives up on an event that kept failing, it stays in the outbox for inspection.
+func (dao *outboxDao) MarkParked(ctx context.Context, id primitive.ObjectID, reason string) (err error) {
+	update := bson.D{
+		{"$set", bson.D{
+			{"status", OutboxParked},
+			{"last_error", reason},
+		}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	}
+	_, err = dao.collection.UpdateOne(ctx, bson.D{{"_id", id}}, update)
+	return
+}
+
+func (dao *outboxDao) DeletePublishedBefore(ctx context.Context, before time.Time) (amount int64, err error) {
+	filter := bson.D{
+		{"status", OutboxPublished},
+		{"publish_at", bson.D{{"$lt", before}}},
+	}
+	result, err := dao.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return
+	}
+	return result.DeletedCount, nil
+}
+
+// AcquireLease keeps one relay publishing at a time, owner holds the lease until it
+// stops renewing it; another owner gets it only after it has expired.
+func (dao *outboxDao) AcquireLease(ctx context.Context, owner string, until time.Time) (acquired bool, err error) {
+	filter := bson.D{
+		{"_id", "relay"},
+		{"$or", bson.A{
+			bson.D{{"owner", owner}},
+			bson.D{{"expire_at", bson.D{{"$lt", time.Now()}}}},
+		}},
+	}
+	update := bson.D{{"$set", bson.D{
+		{"owner", owner},
+		{"expire_at", until},
+	}}}
+	_, err = dao.lease.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		// the upsert collides with the lease of another owner
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return
+	}
+	return true, nil
+}
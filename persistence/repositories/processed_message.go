@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+var ErrMessageProcessed = errors.New("message has been processed")
+
+// ProcessedMessage remembers that a handler has dealt with the message of a key
+// until it expires, the key is either the uuid of the message or a business key.
+type ProcessedMessage struct {
+	ID          string    `bson:"_id"`
+	Handler     string    `bson:"handler"`
+	Key         string    `bson:"key"`
+	ProcessedAt time.Time `bson:"processed_at"`
+	ExpireAt    time.Time `bson:"expire_at"`
+}
+
+type ProcessedMessageDao interface {
+	Exist(ctx context.Context, handler, key string) (isExisted bool, err error)
+	Create(ctx context.Context, handler, key string, expireAt time.Time) (err error)
+}
+
+type processedMessageDao struct {
+	collection *mongo.Collection
+}
+
+func NewProcessedMessageDao() (dao ProcessedMessageDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("processed_message")
+	// mongo removes expired keys by itself
+	opt := options.Index().SetExpireAfterSeconds(0)
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.M{
+				"expire_at": 1,
+			}, Options: opt,
+		},
+	})
+	return &processedMessageDao{col}, nil
+}
+
+func (dao *processedMessageDao) Exist(ctx context.Context, handler, key string) (isExisted bool, err error) {
+	count, err := dao.collection.CountDocuments(ctx, bson.D{{"_id", idOfProcessedMessage(handler, key)}})
+	if err != nil {
+		return
+	}
+	return count > 0, nil
+}
+
+// Create records the key, ErrMessageProcessed is returned when it has been recorded already.
+func (dao *processedMessageDao) Create(ctx context.Context, handler, key string, expireAt time.Time) (err error) {
+	_, err = dao.collection.InsertOne(ctx, &ProcessedMessage{
+		ID:          idOfProcessedMessage(handler, key),
+		Handler:     handler,
+		Key:         key,
+		ProcessedAt: time.Now(),
+		ExpireAt:    expireAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrMessageProcessed
+	}
+	return
+}
+
+func idOfProcessedMessage(handler, key string) string {
+	return handler + ":" + key
+}
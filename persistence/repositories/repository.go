@@ -14,14 +14,28 @@ func GetRepository() (instance *repository, err error) {
 }
 
 type repository struct {
-	Auth        AuthDao
-	User        UserDao
-	Creation    CreationDao
-	Transaction TransactionDao
-	Brand       BrandDao
-	Item        ItemDao
-	Collection  CollectionDao
-	Stock       StockDao
+	Auth             AuthDao
+	User             UserDao
+	Creation         CreationDao
+	Transaction      TransactionDao
+	Brand            BrandDao
+	Item             ItemDao
+	Collection       CollectionDao
+	Stock            StockDao
+	Order            OrderDao
+	Auction          AuctionDao
+	Bid              BidDao
+	Listing          ListingDao
+	Offer            OfferDao
+	Earning          EarningDao
+	Nonce            NonceDao
+	ServiceAccount   ServiceAccountDao
+	ApiKey           ApiKeyDao
+	BrandMember      BrandMemberDao
+	Holder           HolderDao
+	Outbox           OutboxDao
+	ProcessedMessage ProcessedMessageDao
+	DeadLetter       DeadLetterDao
 }
 
 func newRepository() (instance *repository, err error) {
@@ -57,14 +71,84 @@ func newRepository() (instance *repository, err error) {
 	if err != nil {
 		return nil, err
 	}
+	order, err := NewOrderDao()
+	if err != nil {
+		return nil, err
+	}
+	auction, err := NewAuctionDao()
+	if err != nil {
+		return nil, err
+	}
+	bid, err := NewBidDao()
+	if err != nil {
+		return nil, err
+	}
+	listing, err := NewListingDao()
+	if err != nil {
+		return nil, err
+	}
+	offer, err := NewOfferDao()
+	if err != nil {
+		return nil, err
+	}
+	earning, err := NewEarningDao()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := NewNonceDao()
+	if err != nil {
+		return nil, err
+	}
+	serviceAccount, err := NewServiceAccountDao()
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := NewApiKeyDao()
+	if err != nil {
+		return nil, err
+	}
+	brandMember, err := NewBrandMemberDao()
+	if err != nil {
+		return nil, err
+	}
+	holder, err := NewHolderDao()
+	if err != nil {
+		return nil, err
+	}
+	outbox, err := NewOutboxDao()
+	if err != nil {
+		return nil, err
+	}
+	processedMessage, err := NewProcessedMessageDao()
+	if err != nil {
+		return nil, err
+	}
+	deadLetter, err := NewDeadLetterDao()
+	if err != nil {
+		return nil, err
+	}
 	return &repository{
-		Auth:        auth,
-		User:        user,
-		Creation:    creation,
-		Transaction: transaction,
-		Brand:       brand,
-		Item:        item,
-		Collection:  collection,
-		Stock:       stock,
+		Auth:             auth,
+		User:             user,
+		Creation:         creation,
+		Transaction:      transaction,
+		Brand:            brand,
+		Item:             item,
+		Collection:       collection,
+		Stock:            stock,
+		Order:            order,
+		Auction:          auction,
+		Bid:              bid,
+		Listing:          listing,
+		Offer:            offer,
+		Earning:          earning,
+		Nonce:            nonce,
+		ServiceAccount:   serviceAccount,
+		ApiKey:           apiKey,
+		BrandMember:      brandMember,
+		Holder:           holder,
+		Outbox:           outbox,
+		ProcessedMessage: processedMessage,
+		DeadLetter:       deadLetter,
 	}, nil
 }
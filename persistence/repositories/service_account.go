@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"time"
+)
+
+// ServiceAccountSubject prefixes the name a service account acts under, so it never
+// collides with a user account in casbin rules or brand memberships.
+const ServiceAccountSubject = "sa:"
+
+// ServiceAccount is the identity of a machine client, its scopes are casbin roles.
+type ServiceAccount struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Name     string             `bson:"name"`
+	Scopes   []string           `bson:"scopes"`
+	CreateAt time.Time          `bson:"create_at"`
+	UpdateAt time.Time          `bson:"update_at"`
+}
+
+func (a *ServiceAccount) GetName() string {
+	return ServiceAccountSubject + a.Name
+}
+
+func (a *ServiceAccount) GetAuthorities() []string {
+	return a.Scopes
+}
+
+type ServiceAccountDao interface {
+	Find(ctx context.Context, id primitive.ObjectID) (account *ServiceAccount, err error)
+	Create(ctx context.Context, account *ServiceAccount) (err error)
+	UpdateScopes(ctx context.Context, id primitive.ObjectID, scopes []string) (account *ServiceAccount, err error)
+	FindAll(ctx context.Context) (accounts []ServiceAccount, err error)
+}
+
+type serviceAccountDao struct {
+	collection *mongo.Collection
+}
+
+func NewServiceAccountDao() (dao ServiceAccountDao, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("service_account")
+	opt := options.Index().SetUnique(true)
+	col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.M{
+				"name": 1,
+			}, Options: opt,
+		},
+	})
+	return &serviceAccountDao{col}, nil
+}
+
+func (dao *serviceAccountDao) Find(ctx context.Context, id primitive.ObjectID) (account *ServiceAccount, err error) {
+	account = &ServiceAccount{}
+	err = dao.collection.FindOne(ctx, bson.D{{"_id", id}}).Decode(account)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *serviceAccountDao) Create(ctx context.Context, account *ServiceAccount) (err error) {
+	_, err = dao.collection.InsertOne(ctx, account)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ServiceAccountDuplicate
+		}
+		return
+	}
+	return
+}
+
+func (dao *serviceAccountDao) UpdateScopes(
+	ctx context.Context, id primitive.ObjectID, scopes []string,
+) (account *ServiceAccount, err error) {
+	account = &ServiceAccount{}
+	update := bson.D{{"$set", bson.D{
+		{"scopes", scopes},
+		{"update_at", time.Now()},
+	}}}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = dao.collection.FindOneAndUpdate(ctx, bson.D{{"_id", id}}, update, option).Decode(account)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return
+	}
+	return
+}
+
+func (dao *serviceAccountDao) FindAll(ctx context.Context) (accounts []ServiceAccount, err error) {
+	cur, err := dao.collection.Find(ctx, bson.D{})
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var account ServiceAccount
+		err := cur.Decode(&account)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return
+}
+
+var (
+	ServiceAccountDuplicate = errors.New("service account is duplicate")
+)
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"nftshopping-store-api/pkg/utils"
+)
+
+// sortOf is the sort of pageable in its order, _id is appended to break ties
+// so that every page is read in the same order.
+func sortOf(pageable utils.Pageable, defaults ...bson.E) (sort bson.D) {
+	for _, s := range pageable.Sort {
+		sort = append(sort, bson.E{Key: s.Key, Value: orderOf(s.Order)})
+	}
+	if len(sort) == 0 {
+		sort = append(sort, defaults...)
+	}
+	for _, e := range sort {
+		if e.Key == "_id" {
+			return
+		}
+	}
+	return append(sort, bson.E{Key: "_id", Value: 1})
+}
+
+func orderOf(order int) int {
+	if order < 0 {
+		return -1
+	}
+	return 1
+}
@@ -21,6 +21,9 @@ type TransactionDao interface {
 	FindAllByPage(ctx context.Context, pageable utils.Pageable) (transactions *utils.Page, err error)
 	FindAllByFilter(ctx context.Context, filter TransactionFilter) (transactions []Transaction, err error)
 	FindAllByFilterAndPage(ctx context.Context, filter TransactionFilter, pageable utils.Pageable) (transactions *utils.Page, err error)
+	FindAllByFilterAndCursor(ctx context.Context, filter TransactionFilter, pageable utils.Pageable) (transactions *utils.Page, err error)
+	SumByGroup(ctx context.Context, filter TransactionFilter, group TransactionGroup, bucket TimeBucket) (stats []TransactionStats, err error)
+	FindPriceHistory(ctx context.Context, filter TransactionFilter, limit int) (points []PricePoint, err error)
 }
 
 type transactionDao struct {
@@ -118,6 +121,152 @@ func (dao *transactionDao) FindAllByFilterAndPage(
 	return
 }
 
+func (dao *transactionDao) FindAllByFilterAndCursor(
+	ctx context.Context, filter TransactionFilter, pageable utils.Pageable,
+) (transactions *utils.Page, err error) {
+	docs, next, err := findByCursor(ctx, dao.collection, bson.D(filter), pageable)
+	if err != nil {
+		return
+	}
+	transactions = &utils.Page{Size: pageable.Size, NextCursor: next}
+	var content []Transaction
+	for _, doc := range docs {
+		var transaction Transaction
+		if err = bson.Unmarshal(doc, &transaction); err != nil {
+			return nil, err
+		}
+		content = append(content, transaction)
+	}
+	transactions.Content = content
+	return
+}
+
+// SumByGroup aggregates the volume and prices of the matched trades per group and time bucket.
+// Prices are compared per unit since a trade may move several items of a creation at once.
+func (dao *transactionDao) SumByGroup(
+	ctx context.Context, filter TransactionFilter, group TransactionGroup, bucket TimeBucket,
+) (stats []TransactionStats, err error) {
+	id := bson.D{}
+	switch group {
+	case GroupByCreation:
+		id = append(id, bson.E{Key: "creation_id", Value: "$creation_id"})
+	case GroupByBrand:
+		id = append(id, bson.E{Key: "brand_id", Value: "$brand_id"})
+	}
+	if start := bucketOf(bucket); start != nil {
+		id = append(id, bson.E{Key: "bucket", Value: start})
+	}
+	unitPrice := bson.D{{"$divide", bson.A{"$price", bson.D{{"$max", bson.A{"$amount", 1}}}}}}
+	pipeline := []bson.D{
+		{{"$match", filter}},
+		{{"$group", bson.D{
+			{"_id", id},
+			{"volume", bson.D{{"$sum", "$price"}}},
+			{"trade_count", bson.D{{"$sum", 1}}},
+			{"amount", bson.D{{"$sum", "$amount"}}},
+			{"floor_price", bson.D{{"$min", unitPrice}}},
+			{"ceiling_price", bson.D{{"$max", unitPrice}}},
+			{"average_price", bson.D{{"$avg", unitPrice}}},
+			{"buyers", bson.D{{"$addToSet", "$buyer"}}},
+			{"sellers", bson.D{{"$addToSet", "$seller"}}},
+		}}},
+		{{"$project", bson.D{
+			{"_id", 0},
+			{"creation_id", "$_id.creation_id"},
+			{"brand_id", "$_id.brand_id"},
+			{"bucket", "$_id.bucket"},
+			{"volume", 1},
+			{"trade_count", 1},
+			{"amount", 1},
+			{"floor_price", 1},
+			{"ceiling_price", 1},
+			{"average_price", 1},
+			{"unique_buyers", bson.D{{"$size", "$buyers"}}},
+			{"unique_sellers", bson.D{{"$size", "$sellers"}}},
+		}}},
+		{{"$sort", bson.D{{"bucket", 1}, {"creation_id", 1}, {"brand_id", 1}}}},
+	}
+	cur, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var stat TransactionStats
+		err := cur.Decode(&stat)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// FindPriceHistory returns the unit price of the latest limit matched trades in the order they happened.
+func (dao *transactionDao) FindPriceHistory(
+	ctx context.Context, filter TransactionFilter, limit int,
+) (points []PricePoint, err error) {
+	pipeline := []bson.D{
+		{{"$match", filter}},
+		{{"$sort", bson.D{{"trade_at", -1}}}},
+		{{"$limit", limit}},
+		{{"$sort", bson.D{{"trade_at", 1}}}},
+		{{"$project", bson.D{
+			{"creation_id", 1},
+			{"price", 1},
+			{"amount", 1},
+			{"unit_price", bson.D{{"$divide", bson.A{"$price", bson.D{{"$max", bson.A{"$amount", 1}}}}}}},
+			{"trade_at", 1},
+		}}},
+	}
+	cur, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var point PricePoint
+		err := cur.Decode(&point)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// bucketOf is the expression truncating trade_at to the start of its bucket, in UTC.
+func bucketOf(bucket TimeBucket) interface{} {
+	switch bucket {
+	case BucketHour:
+		return bson.D{{"$dateFromParts", bson.D{
+			{"year", bson.D{{"$year", "$trade_at"}}},
+			{"month", bson.D{{"$month", "$trade_at"}}},
+			{"day", bson.D{{"$dayOfMonth", "$trade_at"}}},
+			{"hour", bson.D{{"$hour", "$trade_at"}}},
+		}}}
+	case BucketDay:
+		return bson.D{{"$dateFromParts", bson.D{
+			{"year", bson.D{{"$year", "$trade_at"}}},
+			{"month", bson.D{{"$month", "$trade_at"}}},
+			{"day", bson.D{{"$dayOfMonth", "$trade_at"}}},
+		}}}
+	case BucketWeek:
+		return bson.D{{"$dateFromParts", bson.D{
+			{"isoWeekYear", bson.D{{"$isoWeekYear", "$trade_at"}}},
+			{"isoWeek", bson.D{{"$isoWeek", "$trade_at"}}},
+		}}}
+	default:
+		return nil
+	}
+}
+
 func (dao *transactionDao) findList(
 	ctx context.Context, filter interface{},
 ) (transactions []Transaction, err error) {
@@ -148,13 +297,7 @@ func (dao *transactionDao) findPage(
 	option := options.Find()
 	option.SetSkip(int64(pageable.Size * pageable.Page))
 	option.SetLimit(int64(pageable.Size))
-	if len(pageable.Sort) > 0 {
-		sort := bson.D{}
-		for key, value := range pageable.Sort {
-			sort = append(sort, bson.E{Key: key, Value: value})
-		}
-		option.SetSort(sort)
-	}
+	option.SetSort(sortOf(pageable))
 	cur, err := dao.collection.Find(ctx, filter, option)
 	if err != nil {
 		return
@@ -184,6 +327,57 @@ type Transaction struct {
 	Seller     string             `bson:"seller" json:"seller"`
 	Amount     int                `bson:"amount" json:"amount"`
 	Price      int                `bson:"price" json:"price"`
+	Items      []ItemID           `bson:"items" json:"items"`
+	Fee        Fee                `bson:"fee" json:"fee"`
+	TradeAt    time.Time          `bson:"trade_at" json:"tradeAt"`
+}
+
+// Fee is how the price of a trade is split between its payees.
+type Fee struct {
+	SellerProceeds int    `bson:"seller_proceeds" json:"sellerProceeds"`
+	Creator        string `bson:"creator" json:"creator"`
+	CreatorRoyalty int    `bson:"creator_royalty" json:"creatorRoyalty"`
+	BrandRoyalty   int    `bson:"brand_royalty" json:"brandRoyalty"`
+	PlatformFee    int    `bson:"platform_fee" json:"platformFee"`
+}
+
+type TransactionGroup string
+
+const (
+	GroupByAll      TransactionGroup = ""
+	GroupByCreation TransactionGroup = "creation"
+	GroupByBrand    TransactionGroup = "brand"
+)
+
+type TimeBucket string
+
+const (
+	BucketNone TimeBucket = ""
+	BucketHour TimeBucket = "hour"
+	BucketDay  TimeBucket = "day"
+	BucketWeek TimeBucket = "week"
+)
+
+type TransactionStats struct {
+	CreationID    *string    `bson:"creation_id" json:"creationId"`
+	BrandID       *string    `bson:"brand_id" json:"brandId"`
+	Bucket        *time.Time `bson:"bucket" json:"bucket"`
+	Volume        int        `bson:"volume" json:"volume"`
+	TradeCount    int        `bson:"trade_count" json:"tradeCount"`
+	Amount        int        `bson:"amount" json:"amount"`
+	FloorPrice    float64    `bson:"floor_price" json:"floorPrice"`
+	CeilingPrice  float64    `bson:"ceiling_price" json:"ceilingPrice"`
+	AveragePrice  float64    `bson:"average_price" json:"averagePrice"`
+	UniqueBuyers  int        `bson:"unique_buyers" json:"uniqueBuyers"`
+	UniqueSellers int        `bson:"unique_sellers" json:"uniqueSellers"`
+}
+
+type PricePoint struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	CreationID string             `bson:"creation_id" json:"creationId"`
+	Price      int                `bson:"price" json:"price"`
+	Amount     int                `bson:"amount" json:"amount"`
+	UnitPrice  float64            `bson:"unit_price" json:"unitPrice"`
 	TradeAt    time.Time          `bson:"trade_at" json:"tradeAt"`
 }
 
@@ -210,6 +404,12 @@ func SelectorOfTransaction(selector TransactionSelector) (filter TransactionFilt
 		})
 	}
 
+	if selector.CreationIDs != nil {
+		filter = append(filter, bson.E{
+			Key: "creation_id", Value: bson.D{{Key: "$in", Value: selector.CreationIDs}},
+		})
+	}
+
 	if selector.BrandID != nil {
 		filter = append(filter, bson.E{
 			Key: "brand_id", Value: selector.BrandID,
@@ -237,18 +437,41 @@ func SelectorOfTransaction(selector TransactionSelector) (filter TransactionFilt
 		}
 		filter = append(filter, bson.E{Key: "price", Value: priceFilter})
 	}
+	filter = append(filter, conditionsOf(selector.Conditions)...)
 	return
 }
 
+// TransactionSortFields are the fields a page of transactions can be sorted by.
+var TransactionSortFields = utils.SortFields{
+	"amount":  "amount",
+	"price":   "price",
+	"tradeAt": "trade_at",
+}
+
+// TransactionFilterFields are the fields the filter of transactions can be written on.
+var TransactionFilterFields = utils.FilterFields{
+	"id":         objectIdField("_id"),
+	"creationId": utils.StringField("creation_id"),
+	"brandId":    utils.StringField("brand_id"),
+	"buyer":      utils.StringField("buyer"),
+	"seller":     utils.StringField("seller"),
+	"creator":    utils.StringField("fee.creator"),
+	"amount":     utils.IntField("amount"),
+	"price":      utils.IntField("price"),
+	"tradeAt":    utils.TimeField("trade_at"),
+}
+
 type TransactionSelector struct {
-	CreationID   *string    `json:"creationID"`
-	BrandID      *string    `json:"brandId"`
-	Buyer        *string    `json:"buyer"`
-	Seller       *string    `json:"seller"`
-	TradedBefore *time.Time `json:"tradedBefore"`
-	TradedAfter  *time.Time `json:"tradedAfter"`
-	MaxPrice     *int       `json:"maxPrice"`
-	MinPrice     *int       `json:"minPrice"`
+	CreationID   *string           `json:"creationID"`
+	CreationIDs  []string          `json:"creationIds"`
+	BrandID      *string           `json:"brandId"`
+	Buyer        *string           `json:"buyer"`
+	Seller       *string           `json:"seller"`
+	TradedBefore *time.Time        `json:"tradedBefore"`
+	TradedAfter  *time.Time        `json:"tradedAfter"`
+	MaxPrice     *int              `json:"maxPrice"`
+	MinPrice     *int              `json:"minPrice"`
+	Conditions   []utils.Condition `json:"conditions"`
 }
 
 var TransactionNotFound = errors.New("transaction not found")
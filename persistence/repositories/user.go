@@ -137,6 +137,7 @@ func (dao *userDao) findPage(
 	option := options.Find()
 	option.SetSkip(int64(pageable.Size * pageable.Page))
 	option.SetLimit(int64(pageable.Size))
+	option.SetSort(sortOf(pageable))
 	cur, err := dao.collection.Find(ctx, filter, option)
 	if err != nil {
 		return
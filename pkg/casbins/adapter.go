@@ -0,0 +1,196 @@
+package casbins
+
+import (
+	"context"
+	"errors"
+	"github.com/casbin/casbin/model"
+	"github.com/casbin/casbin/persist"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/databases"
+	"strings"
+)
+
+// rule is one line of a casbin policy, like "p, user, /api/user/findUser, GET".
+type rule struct {
+	PType string `bson:"ptype"`
+	V0    string `bson:"v0,omitempty"`
+	V1    string `bson:"v1,omitempty"`
+	V2    string `bson:"v2,omitempty"`
+	V3    string `bson:"v3,omitempty"`
+	V4    string `bson:"v4,omitempty"`
+	V5    string `bson:"v5,omitempty"`
+}
+
+func newRule(ptype string, values []string) rule {
+	r := rule{PType: ptype}
+	fields := []*string{&r.V0, &r.V1, &r.V2, &r.V3, &r.V4, &r.V5}
+	for i := range values {
+		if i < len(fields) {
+			*fields[i] = values[i]
+		}
+	}
+	return r
+}
+
+func (r rule) line() string {
+	values := []string{r.PType}
+	for _, v := range []string{r.V0, r.V1, r.V2, r.V3, r.V4, r.V5} {
+		if len(v) == 0 {
+			break
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, ", ")
+}
+
+// mongoAdapter keeps the casbin policy in mongo so it can be changed at runtime.
+type mongoAdapter struct {
+	collection *mongo.Collection
+}
+
+func newMongoAdapter() (adapter *mongoAdapter, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	col := db.Collection("casbin_rule")
+	ctx := context.Background()
+	// the index cannot be built on rules stored twice before it existed
+	if err = removeDuplicateRules(ctx, col); err != nil {
+		return
+	}
+	// instances seeding or changing the policy at the same time must not store a rule twice
+	_, err = col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{"ptype", 1},
+			{"v0", 1},
+			{"v1", 1},
+			{"v2", 1},
+			{"v3", 1},
+			{"v4", 1},
+			{"v5", 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return
+	}
+	return &mongoAdapter{col}, nil
+}
+
+// removeDuplicateRules keeps the first of every rule stored more than once.
+func removeDuplicateRules(ctx context.Context, col *mongo.Collection) (err error) {
+	pipeline := mongo.Pipeline{
+		{{"$sort", bson.D{{"_id", 1}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{
+				{"ptype", "$ptype"},
+				{"v0", "$v0"},
+				{"v1", "$v1"},
+				{"v2", "$v2"},
+				{"v3", "$v3"},
+				{"v4", "$v4"},
+				{"v5", "$v5"},
+			}},
+			{"ids", bson.D{{"$push", "$_id"}}},
+			{"count", bson.D{{"$sum", 1}}},
+		}}},
+		{{"$match", bson.D{{"count", bson.D{{"$gt", 1}}}}}},
+	}
+	cur, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	var duplicates []interface{}
+	for cur.Next(ctx) {
+		var group struct {
+			IDs []interface{} `bson:"ids"`
+		}
+		if err = cur.Decode(&group); err != nil {
+			return
+		}
+		duplicates = append(duplicates, group.IDs[1:]...)
+	}
+	if err = cur.Err(); err != nil {
+		return
+	}
+	if len(duplicates) == 0 {
+		return
+	}
+	_, err = col.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", duplicates}}}})
+	return
+}
+
+func (adapter *mongoAdapter) LoadPolicy(model model.Model) (err error) {
+	ctx := context.Background()
+	cur, err := adapter.collection.Find(ctx, bson.D{})
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var r rule
+		if err = cur.Decode(&r); err != nil {
+			return
+		}
+		persist.LoadPolicyLine(r.line(), model)
+	}
+	return cur.Err()
+}
+
+func (adapter *mongoAdapter) SavePolicy(model model.Model) (err error) {
+	ctx := context.Background()
+	var rules []interface{}
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range model[sec] {
+			for _, values := range ast.Policy {
+				rules = append(rules, newRule(ptype, values))
+			}
+		}
+	}
+	if _, err = adapter.collection.DeleteMany(ctx, bson.D{}); err != nil {
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+	_, err = adapter.collection.InsertMany(ctx, rules)
+	return
+}
+
+func (adapter *mongoAdapter) AddPolicy(sec string, ptype string, values []string) (err error) {
+	_, err = adapter.collection.InsertOne(context.Background(), newRule(ptype, values))
+	// another instance has stored the same rule
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return
+}
+
+func (adapter *mongoAdapter) RemovePolicy(sec string, ptype string, values []string) (err error) {
+	_, err = adapter.collection.DeleteOne(context.Background(), filterOf(ptype, 0, values...))
+	return
+}
+
+func (adapter *mongoAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) (err error) {
+	if fieldIndex < 0 || fieldIndex+len(fieldValues) > 6 {
+		return errors.New("casbin rule field is out of range")
+	}
+	_, err = adapter.collection.DeleteMany(context.Background(), filterOf(ptype, fieldIndex, fieldValues...))
+	return
+}
+
+// filterOf matches the rules of ptype whose fields from fieldIndex on equal the values, an empty value matches anything.
+func filterOf(ptype string, fieldIndex int, values ...string) bson.D {
+	filter := bson.D{{"ptype", ptype}}
+	keys := []string{"v0", "v1", "v2", "v3", "v4", "v5"}
+	for i, v := range values {
+		if len(v) > 0 && fieldIndex+i < len(keys) {
+			filter = append(filter, bson.E{Key: keys[fieldIndex+i], Value: v})
+		}
+	}
+	return filter
+}
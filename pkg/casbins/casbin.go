@@ -1,15 +1,17 @@
 package casbins
 
 import (
+	"fmt"
 	"github.com/casbin/casbin"
+	fileadapter "github.com/casbin/casbin/persist/file-adapter"
 	"nftshopping-store-api/pkg/config"
 )
 
 var (
-	enforcerInstance *casbin.Enforcer
+	enforcerInstance *casbin.SyncedEnforcer
 )
 
-func GetEnforcer() (instance *casbin.Enforcer, err error) {
+func GetEnforcer() (instance *casbin.SyncedEnforcer, err error) {
 	if enforcerInstance == nil {
 		instance, err = newEnforcer()
 		if err != nil {
@@ -20,15 +22,85 @@ func GetEnforcer() (instance *casbin.Enforcer, err error) {
 	return enforcerInstance, nil
 }
 
-func newEnforcer() (instance *casbin.Enforcer, err error) {
+func newEnforcer() (instance *casbin.SyncedEnforcer, err error) {
 	c, err := config.GetConfig()
 	if err != nil {
 		return nil, err
 	}
 	casbinConfig := c.Casbin
-	enforcer, err := casbin.NewEnforcerSafe(casbinConfig.Model, casbinConfig.Policy)
+	adapter, err := newMongoAdapter()
 	if err != nil {
 		return
 	}
+	enforcer, err := casbin.NewSyncedEnforcerSafe(casbinConfig.Model, adapter)
+	if err != nil {
+		return
+	}
+	if len(casbinConfig.Policy) > 0 {
+		if err = seed(enforcer, casbinConfig.Model, casbinConfig.Policy); err != nil {
+			return
+		}
+	}
+	watcher, err := newRedisWatcher(casbinConfig.Channel)
+	if err != nil {
+		return
+	}
+	enforcer.SetWatcher(watcher)
 	return enforcer, nil
 }
+
+// seed adds the rules of the policy file that are missing from the database on every start,
+// so rules shipped with a new release reach an existing database. Rules added through the
+// api are kept; a seeded rule has to leave the file before it can be removed for good.
+func seed(enforcer *casbin.SyncedEnforcer, model, policy string) (err error) {
+	file, err := casbin.NewEnforcerSafe(model, fileadapter.NewAdapter(policy))
+	if err != nil {
+		return
+	}
+	for _, rule := range file.GetPolicy() {
+		if _, err = Safe(enforcer, func() bool { return enforcer.AddPolicy(rule) }); err != nil {
+			return
+		}
+	}
+	for _, rule := range file.GetGroupingPolicy() {
+		if _, err = Safe(enforcer, func() bool { return enforcer.AddGroupingPolicy(rule) }); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Safe runs a policy change, casbin panics when the adapter fails to persist it. The change
+// is already in memory by then, so the policy is reloaded from the database to drop it.
+func Safe(enforcer *casbin.SyncedEnforcer, change func() bool) (isChanged bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+			if loadErr := enforcer.LoadPolicy(); loadErr != nil {
+				err = fmt.Errorf("%v, fail to reload policy: %v", r, loadErr)
+			}
+		}
+	}()
+	return change(), nil
+}
+
+// RolesOf returns the given subjects with every role they inherit through g rules.
+func RolesOf(enforcer *casbin.SyncedEnforcer, subjects ...string) (roles []string) {
+	visited := map[string]bool{}
+	queue := append([]string{}, subjects...)
+	for len(queue) > 0 {
+		subject := queue[0]
+		queue = queue[1:]
+		if visited[subject] {
+			continue
+		}
+		visited[subject] = true
+		roles = append(roles, subject)
+		inherited, err := enforcer.GetRolesForUser(subject)
+		if err != nil {
+			continue
+		}
+		queue = append(queue, inherited...)
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package casbins
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/go-redis/redis/v8"
+	"nftshopping-store-api/pkg/caches"
+	"sync"
+)
+
+// redisWatcher tells the other instances to reload the policy through redis pub/sub.
+type redisWatcher struct {
+	client   *redis.Client
+	pubsub   *redis.PubSub
+	channel  string
+	id       string
+	mutex    sync.Mutex
+	callback func(string)
+}
+
+func newRedisWatcher(channel string) (watcher *redisWatcher, err error) {
+	client, err := caches.GetRedis()
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err = rand.Read(id); err != nil {
+		return
+	}
+	pubsub := client.Subscribe(context.Background(), channel)
+	if _, err = pubsub.Receive(context.Background()); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	watcher = &redisWatcher{
+		client:  client,
+		pubsub:  pubsub,
+		channel: channel,
+		id:      hex.EncodeToString(id),
+	}
+	go watcher.listen()
+	return watcher, nil
+}
+
+func (watcher *redisWatcher) listen() {
+	for msg := range watcher.pubsub.Channel() {
+		// the instance that made the change is up to date already
+		if msg.Payload == watcher.id {
+			continue
+		}
+		watcher.mutex.Lock()
+		callback := watcher.callback
+		watcher.mutex.Unlock()
+		if callback != nil {
+			callback(msg.Payload)
+		}
+	}
+}
+
+func (watcher *redisWatcher) SetUpdateCallback(callback func(string)) error {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	watcher.callback = callback
+	return nil
+}
+
+func (watcher *redisWatcher) Update() error {
+	return watcher.client.Publish(context.Background(), watcher.channel, watcher.id).Err()
+}
+
+func (watcher *redisWatcher) Close() {
+	watcher.pubsub.Close()
+}
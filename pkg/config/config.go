@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	"nftshopping-store-api/pkg/flags"
+	"time"
 )
 
 var configInstance *Configuration
@@ -47,12 +48,24 @@ func newConfig() (*Configuration, error) {
 }
 
 type Configuration struct {
-	Server   *Server
-	Database *Database
-	Logger   *Logger
-	Amazon   *Amazon
-	Casbin   *Casbin
-	Item     *Item
+	Server         *Server
+	Database       *Database
+	Logger         *Logger
+	Amazon         *Amazon
+	Casbin         *Casbin
+	Item           *Item
+	Order          *Order
+	Creation       *Creation
+	Auction        *Auction
+	Market         *Market
+	Fee            *Fee
+	Login          *Login
+	Jwt            *Jwt
+	ServiceAccount *ServiceAccount
+	Holder         *Holder
+	PubSub         *PubSub
+	Outbox         *Outbox
+	Idempotency    *Idempotency
 }
 
 type Server struct {
@@ -93,10 +106,116 @@ type S3 struct {
 }
 
 type Casbin struct {
-	Model  string
-	Policy string
+	Model   string
+	Policy  string
+	Channel string
 }
 
 type Item struct {
 	Domain string
 }
+
+type Order struct {
+	Timeout       time.Duration
+	CheckInterval time.Duration
+}
+
+type Creation struct {
+	CheckInterval time.Duration
+}
+
+type Auction struct {
+	CheckInterval time.Duration
+}
+
+type Market struct {
+	OfferTimeout  time.Duration
+	CheckInterval time.Duration
+}
+
+type Jwt struct {
+	Issuer            string
+	ActiveKid         string
+	AccessExpiration  time.Duration
+	RefreshExpiration time.Duration
+	Keys              []JwtKey
+}
+
+type JwtKey struct {
+	Kid        string
+	Algorithm  string
+	Secret     string
+	PrivateKey string
+	PublicKey  string
+}
+
+type ServiceAccount struct {
+	RotateGrace time.Duration
+}
+
+type Holder struct {
+	CacheExpiration time.Duration
+}
+
+// PubSub picks the broker of the events by Backend, one of amqp, gochannel or mongo.
+type PubSub struct {
+	Backend   string
+	Amqp      *Amqp
+	GoChannel *GoChannel
+	Mongo     *MongoPubSub
+}
+
+// Amqp publishes to the default exchange with a queue per topic unless Exchange is set,
+// then the topic is the routing key of Exchange and queues are named QueuePrefix + topic.
+type Amqp struct {
+	Uri          string
+	Exchange     string
+	ExchangeType string
+	QueuePrefix  string
+	Durable      bool
+	Prefetch     int
+}
+
+type GoChannel struct {
+	OutputBuffer int64
+	Persistent   bool
+}
+
+type MongoPubSub struct {
+	Collection   string
+	PollInterval time.Duration
+	AckTimeout   time.Duration
+}
+
+// Outbox paces the relay, a failed event is retried after RetryInterval doubled
+// per attempt up to MaxRetryInterval and parked after MaxAttempts; published events
+// are kept for Retention.
+type Outbox struct {
+	PollInterval     time.Duration
+	BatchSize        int
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+	MaxAttempts      int
+	LeaseTimeout     time.Duration
+	Retention        time.Duration
+	CleanupInterval  time.Duration
+}
+
+// Idempotency keeps the keys of processed messages for TTL, it should outlast
+// the time a message can still be redelivered.
+type Idempotency struct {
+	TTL time.Duration
+}
+
+type Login struct {
+	Domain       string
+	Uri          string
+	ChainId      int
+	Statement    string
+	NonceTimeout time.Duration
+}
+
+type Fee struct {
+	PlatformBps     int
+	PlatformAccount string
+}
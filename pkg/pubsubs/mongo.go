@@ -0,0 +1,259 @@
+package pubsubs
+
+import (
+	"context"
+	"errors"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nftshopping-store-api/pkg/config"
+	"nftshopping-store-api/pkg/databases"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMongoCollection   = "pubsub_message"
+	defaultMongoPollInterval = time.Second
+	defaultMongoAckTimeout   = 30 * time.Second
+)
+
+var ErrSubscriberClosed = errors.New("subscriber is closed")
+
+type mongoMessageStatus string
+
+const (
+	mongoMessagePending    mongoMessageStatus = "pending"
+	mongoMessageProcessing mongoMessageStatus = "processing"
+)
+
+// mongoMessage is a message waiting in the collection, it is claimed by one subscriber
+// at a time and removed once acked; a claim not acked within the ack timeout is given up.
+type mongoMessage struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Topic     string             `bson:"topic"`
+	UUID      string             `bson:"uuid"`
+	Payload   []byte             `bson:"payload"`
+	Metadata  map[string]string  `bson:"metadata"`
+	Status    mongoMessageStatus `bson:"status"`
+	Attempts  int                `bson:"attempts"`
+	LockUntil time.Time          `bson:"lock_until"`
+	CreateAt  time.Time          `bson:"create_at"`
+}
+
+func mongoCollectionOf(c *config.MongoPubSub) (collection *mongo.Collection, err error) {
+	db, err := databases.GetMongoDB()
+	if err != nil {
+		return
+	}
+	name := defaultMongoCollection
+	if c != nil && len(c.Collection) > 0 {
+		name = c.Collection
+	}
+	collection = db.Collection(name)
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{"topic", 1}, {"status", 1}, {"_id", 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+type mongoPublisher struct {
+	collection *mongo.Collection
+}
+
+func newMongoPub(c *config.MongoPubSub) (instance *mongoPublisher, err error) {
+	collection, err := mongoCollectionOf(c)
+	if err != nil {
+		return
+	}
+	return &mongoPublisher{collection: collection}, nil
+}
+
+// Publish writes the messages with the context they carry,
+// so a message published inside a transaction is only seen once it commits.
+func (publisher *mongoPublisher) Publish(topic string, messages ...*message.Message) (err error) {
+	for _, msg := range messages {
+		now := time.Now()
+		_, err = publisher.collection.InsertOne(msg.Context(), mongoMessage{
+			Topic:     topic,
+			UUID:      msg.UUID,
+			Payload:   msg.Payload,
+			Metadata:  msg.Metadata,
+			Status:    mongoMessagePending,
+			LockUntil: now,
+			CreateAt:  now,
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (publisher *mongoPublisher) Close() error {
+	return nil
+}
+
+type mongoSubscriber struct {
+	collection   *mongo.Collection
+	pollInterval time.Duration
+	ackTimeout   time.Duration
+	closing      chan struct{}
+	closed       bool
+	mutex        sync.Mutex
+	wg           sync.WaitGroup
+}
+
+func newMongoSub(c *config.MongoPubSub) (instance *mongoSubscriber, err error) {
+	collection, err := mongoCollectionOf(c)
+	if err != nil {
+		return
+	}
+	instance = &mongoSubscriber{
+		collection:   collection,
+		pollInterval: defaultMongoPollInterval,
+		ackTimeout:   defaultMongoAckTimeout,
+		closing:      make(chan struct{}),
+	}
+	if c != nil && c.PollInterval > 0 {
+		instance.pollInterval = c.PollInterval
+	}
+	if c != nil && c.AckTimeout > 0 {
+		instance.ackTimeout = c.AckTimeout
+	}
+	return
+}
+
+// Subscribe hands the messages of topic out one by one, the subscribers of a topic compete
+// for its messages the same way the consumers of an amqp queue do.
+func (subscriber *mongoSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	if subscriber.closed {
+		return nil, ErrSubscriberClosed
+	}
+	output := make(chan *message.Message)
+	subscriber.wg.Add(1)
+	go func() {
+		defer subscriber.wg.Done()
+		defer close(output)
+		subscriber.consume(ctx, topic, output)
+	}()
+	return output, nil
+}
+
+func (subscriber *mongoSubscriber) consume(ctx context.Context, topic string, output chan<- *message.Message) {
+	for {
+		doc, err := subscriber.claim(ctx, topic)
+		if err != nil || doc == nil {
+			if err != nil {
+				logger.Error("fail to claim message", err, nil)
+			}
+			select {
+			case <-time.After(subscriber.pollInterval):
+				continue
+			case <-ctx.Done():
+				return
+			case <-subscriber.closing:
+				return
+			}
+		}
+		msg := message.NewMessage(doc.UUID, doc.Payload)
+		for key, value := range doc.Metadata {
+			msg.Metadata.Set(key, value)
+		}
+		msgCtx, cancel := context.WithCancel(ctx)
+		msg.SetContext(msgCtx)
+		select {
+		case output <- msg:
+		case <-ctx.Done():
+			cancel()
+			subscriber.release(doc.ID)
+			return
+		case <-subscriber.closing:
+			cancel()
+			subscriber.release(doc.ID)
+			return
+		}
+		select {
+		case <-msg.Acked():
+			_, err = subscriber.collection.DeleteOne(context.Background(), bson.D{{"_id", doc.ID}})
+		case <-msg.Nacked():
+			err = subscriber.release(doc.ID)
+		case <-ctx.Done():
+			err = subscriber.release(doc.ID)
+		case <-subscriber.closing:
+			err = subscriber.release(doc.ID)
+		}
+		cancel()
+		if err != nil {
+			logger.Error("fail to settle message", err, nil)
+		}
+		if ctx.Err() != nil || subscriber.isClosed() {
+			return
+		}
+	}
+}
+
+// claim takes the oldest message of topic that is pending or whose claim has timed out.
+func (subscriber *mongoSubscriber) claim(ctx context.Context, topic string) (doc *mongoMessage, err error) {
+	now := time.Now()
+	filter := bson.D{
+		{"topic", topic},
+		{"$or", bson.A{
+			bson.D{{"status", mongoMessagePending}},
+			bson.D{{"status", mongoMessageProcessing}, {"lock_until", bson.D{{"$lt", now}}}},
+		}},
+	}
+	update := bson.D{
+		{"$set", bson.D{
+			{"status", mongoMessageProcessing},
+			{"lock_until", now.Add(subscriber.ackTimeout)},
+		}},
+		{"$inc", bson.D{{"attempts", 1}}},
+	}
+	option := options.FindOneAndUpdate().
+		SetSort(bson.D{{"_id", 1}}).
+		SetReturnDocument(options.After)
+	doc = &mongoMessage{}
+	err = subscriber.collection.FindOneAndUpdate(ctx, filter, update, option).Decode(doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// release puts a message back to be delivered again.
+func (subscriber *mongoSubscriber) release(id primitive.ObjectID) (err error) {
+	_, err = subscriber.collection.UpdateOne(context.Background(), bson.D{{"_id", id}}, bson.D{
+		{"$set", bson.D{{"status", mongoMessagePending}}},
+	})
+	return
+}
+
+func (subscriber *mongoSubscriber) isClosed() bool {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	return subscriber.closed
+}
+
+func (subscriber *mongoSubscriber) Close() error {
+	subscriber.mutex.Lock()
+	if subscriber.closed {
+		subscriber.mutex.Unlock()
+		return nil
+	}
+	subscriber.closed = true
+	close(subscriber.closing)
+	subscriber.mutex.Unlock()
+	subscriber.wg.Wait()
+	return nil
+}
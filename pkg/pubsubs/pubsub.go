@@ -0,0 +1,105 @@
+package pubsubs
+
+import (
+	"errors"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"nftshopping-store-api/pkg/config"
+)
+
+const (
+	BackendAmqp      = "amqp"
+	BackendGoChannel = "gochannel"
+	BackendMongo     = "mongo"
+)
+
+var (
+	ErrBackendUnknown = errors.New("pubsub backend is unknown")
+	logger            = watermill.NewStdLogger(false, false)
+)
+
+var (
+	subInstance       message.Subscriber
+	pubInstance       message.Publisher
+	goChannelInstance *gochannel.GoChannel
+)
+
+func GetSub() (instance message.Subscriber, err error) {
+	if subInstance == nil {
+		instance, err = newSub()
+		if err != nil {
+			return nil, err
+		}
+		subInstance = instance
+	}
+	return subInstance, nil
+}
+
+func newSub() (instance message.Subscriber, err error) {
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	switch backendOf(c) {
+	case BackendAmqp:
+		return newAmqpSub(c.PubSub.Amqp)
+	case BackendGoChannel:
+		return getGoChannel(c.PubSub.GoChannel), nil
+	case BackendMongo:
+		return newMongoSub(c.PubSub.Mongo)
+	default:
+		return nil, ErrBackendUnknown
+	}
+}
+
+func GetPub() (instance message.Publisher, err error) {
+	if pubInstance == nil {
+		instance, err = newPub()
+		if err != nil {
+			return nil, err
+		}
+		pubInstance = instance
+	}
+	return pubInstance, nil
+}
+
+func newPub() (instance message.Publisher, err error) {
+	c, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	switch backendOf(c) {
+	case BackendAmqp:
+		return newAmqpPub(c.PubSub.Amqp)
+	case BackendGoChannel:
+		return getGoChannel(c.PubSub.GoChannel), nil
+	case BackendMongo:
+		return newMongoPub(c.PubSub.Mongo)
+	default:
+		return nil, ErrBackendUnknown
+	}
+}
+
+// backendOf falls back to amqp for the configurations written before the backend was selectable.
+func backendOf(c *config.Configuration) string {
+	if c.PubSub == nil || len(c.PubSub.Backend) == 0 {
+		c.PubSub = &config.PubSub{Backend: BackendAmqp}
+	}
+	return c.PubSub.Backend
+}
+
+// getGoChannel shares one channel between the publisher and the subscriber,
+// messages only travel inside the process.
+func getGoChannel(c *config.GoChannel) *gochannel.GoChannel {
+	if goChannelInstance == nil {
+		if c == nil {
+			c = &config.GoChannel{}
+		}
+		goChannelInstance = gochannel.NewGoChannel(gochannel.Config{
+			OutputChannelBuffer: c.OutputBuffer,
+			Persistent:          c.Persistent,
+		}, logger)
+	}
+	return goChannelInstance
+}
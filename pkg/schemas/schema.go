@@ -0,0 +1,196 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+
+	FormatDateTime = "date-time"
+)
+
+const draft = "http://json-schema.org/draft-07/schema#"
+
+var ErrSchemaMismatch = errors.New("json does not match schema")
+
+// Schema is the part of json schema the events are described with, it is generated
+// from a go type so the two can not drift apart.
+type Schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Title      string             `json:"title,omitempty"`
+	Type       []string           `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+}
+
+// Of generates the schema of v, a field is required unless it is a pointer or tagged omitempty.
+// Properties not in the schema are allowed, so the data of a newer producer still passes.
+func Of(v interface{}) (schema *Schema) {
+	t := reflect.TypeOf(v)
+	schema = schemaOf(t)
+	schema.Schema = draft
+	schema.Title = t.Name()
+	return
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func schemaOf(t reflect.Type) (schema *Schema) {
+	if t == timeType {
+		return &Schema{Type: []string{TypeString}, Format: FormatDateTime}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema = schemaOf(t.Elem())
+		if len(schema.Type) > 0 {
+			schema.Type = append(schema.Type, TypeNull)
+		}
+		return
+	case reflect.Struct:
+		schema = &Schema{Type: []string{TypeObject}, Properties: map[string]*Schema{}}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, omitempty := nameOf(field)
+			if name == "-" {
+				continue
+			}
+			schema.Properties[name] = schemaOf(field.Type)
+			if !omitempty && field.Type.Kind() != reflect.Ptr {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		return
+	case reflect.Slice:
+		// []byte is encoded as a base64 string
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: []string{TypeString, TypeNull}}
+		}
+		return &Schema{Type: []string{TypeArray, TypeNull}, Items: schemaOf(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: []string{TypeArray}, Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: []string{TypeObject, TypeNull}}
+	case reflect.String:
+		return &Schema{Type: []string{TypeString}}
+	case reflect.Bool:
+		return &Schema{Type: []string{TypeBoolean}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: []string{TypeInteger}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: []string{TypeNumber}}
+	default:
+		return &Schema{}
+	}
+}
+
+func nameOf(field reflect.StructField) (name string, omitempty bool) {
+	tag := strings.Split(field.Tag.Get("json"), ",")
+	name = tag[0]
+	if len(name) == 0 {
+		name = field.Name
+	}
+	for _, option := range tag[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return
+}
+
+// Validate checks data against the schema, the error tells the path that does not match.
+func (schema *Schema) Validate(data []byte) (err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err = decoder.Decode(&value); err != nil {
+		return
+	}
+	return schema.validate("$", value)
+}
+
+func (schema *Schema) validate(path string, value interface{}) (err error) {
+	if len(schema.Type) > 0 && !schema.allows(value) {
+		return fmt.Errorf("%w: %s should be %s", ErrSchemaMismatch, path, strings.Join(schema.Type, " or "))
+	}
+	switch v := value.(type) {
+	case string:
+		if schema.Format == FormatDateTime {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				return fmt.Errorf("%w: %s should be %s", ErrSchemaMismatch, path, FormatDateTime)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%w: %s.%s is required", ErrSchemaMismatch, path, name)
+			}
+		}
+		for name, property := range schema.Properties {
+			if field, ok := v[name]; ok {
+				if err = property.validate(path+"."+name, field); err != nil {
+					return
+				}
+			}
+		}
+	case []interface{}:
+		if schema.Items == nil {
+			return
+		}
+		for i, item := range v {
+			if err = schema.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (schema *Schema) allows(value interface{}) bool {
+	for _, t := range schema.Type {
+		if typeOf(value, t == TypeInteger) == t {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}, wantInteger bool) string {
+	switch v := value.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case string:
+		return TypeString
+	case json.Number:
+		if _, err := v.Int64(); err == nil && wantInteger {
+			return TypeInteger
+		}
+		return TypeNumber
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	default:
+		return ""
+	}
+}
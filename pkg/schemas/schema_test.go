@@ -0,0 +1,98 @@
+package schemas
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type line struct {
+	Contract string `json:"contract"`
+	Token    string `json:"token"`
+}
+
+type order struct {
+	OrderId  string            `json:"orderId"`
+	Amount   int               `json:"amount"`
+	Price    float64           `json:"price"`
+	Paid     bool              `json:"paid"`
+	Note     *string           `json:"note"`
+	Memo     string            `json:"memo,omitempty"`
+	Lines    []line            `json:"lines"`
+	Buyer    line              `json:"buyer"`
+	Labels   map[string]string `json:"labels"`
+	OrderAt  time.Time         `json:"orderAt"`
+	internal string
+}
+
+const validOrder = `{
+	"orderId": "o1", "amount": 2, "price": 1.5, "paid": true, "note": null,
+	"lines": [{"contract": "c", "token": "1"}], "buyer": {"contract": "c", "token": "2"},
+	"labels": null, "orderAt": "2022-01-02T03:04:05Z", "extra": "newer producers may add fields"
+}`
+
+func TestOf(t *testing.T) {
+	schema := Of(order{})
+	if schema.Schema != draft || schema.Title != "order" {
+		t.Fatalf("schema = %s, title = %s", schema.Schema, schema.Title)
+	}
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, name := range []string{"orderId", "amount", "price", "paid", "lines", "buyer", "labels", "orderAt"} {
+		if !required[name] {
+			t.Errorf("%s should be required", name)
+		}
+	}
+	for _, name := range []string{"note", "memo", "internal"} {
+		if required[name] {
+			t.Errorf("%s should not be required", name)
+		}
+	}
+	if _, ok := schema.Properties["internal"]; ok {
+		t.Errorf("unexported field should be left out")
+	}
+	if format := schema.Properties["orderAt"].Format; format != FormatDateTime {
+		t.Errorf("orderAt format = %s, want %s", format, FormatDateTime)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Of(order{}).Validate([]byte(validOrder)); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateMismatch(t *testing.T) {
+	for name, data := range map[string]string{
+		"required":          `{"orderId": "o1"}`,
+		"string":            replace(`"orderId": "o1"`, `"orderId": 1`),
+		"integer":           replace(`"amount": 2`, `"amount": 2.5`),
+		"boolean":           replace(`"paid": true`, `"paid": "yes"`),
+		"not nullable":      replace(`"orderId": "o1"`, `"orderId": null`),
+		"date-time":         replace(`"2022-01-02T03:04:05Z"`, `"yesterday"`),
+		"nested type":       replace(`"buyer": {"contract": "c", "token": "2"}`, `"buyer": {"contract": "c", "token": 2}`),
+		"nested required":   replace(`"buyer": {"contract": "c", "token": "2"}`, `"buyer": {"contract": "c"}`),
+		"array item":        replace(`[{"contract": "c", "token": "1"}]`, `[{"contract": "c", "token": "1"}, "c:2"]`),
+		"array item nested": replace(`[{"contract": "c", "token": "1"}]`, `[{"contract": true, "token": "1"}]`),
+		"object":            `[]`,
+	} {
+		if err := Of(order{}).Validate([]byte(data)); !errors.Is(err, ErrSchemaMismatch) {
+			t.Errorf("%s: error = %v, want %v", name, err, ErrSchemaMismatch)
+		}
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	err := Of(order{}).Validate([]byte(replace(`"token": "1"`, `"token": 1`)))
+	want := ErrSchemaMismatch.Error() + ": $.lines[0].token should be string"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %s", err, want)
+	}
+}
+
+func replace(old, new string) string {
+	return strings.Replace(validOrder, old, new, 1)
+}
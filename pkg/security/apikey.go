@@ -0,0 +1,38 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// An api key is "<key id>.<secret>", the key id is used to find the stored hash of the secret.
+
+func GenerateApiKey(keyId string) (key string, hash string, err error) {
+	random := make([]byte, 32)
+	if _, err = rand.Read(random); err != nil {
+		return
+	}
+	secret := base64.RawURLEncoding.EncodeToString(random)
+	return keyId + "." + secret, HashApiKeySecret(secret), nil
+}
+
+func SplitApiKey(key string) (keyId string, secret string, ok bool) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func HashApiKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func MatchApiKeySecret(secret string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashApiKeySecret(secret)), []byte(hash)) == 1
+}
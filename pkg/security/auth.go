@@ -1,5 +1,11 @@
 package security
 
+const (
+	RoleUser   string = "user"
+	RoleAdmin  string = "admin"
+	RoleMinter string = "minter"
+)
+
 type Authentication interface {
 	GetAuthorities() []string
 	GetName() string
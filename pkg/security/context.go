@@ -0,0 +1,25 @@
+package security
+
+import "context"
+
+type authenticationKey struct{}
+
+// WithAuthentication carries the caller down to the services through the context.
+func WithAuthentication(ctx context.Context, auth Authentication) context.Context {
+	return context.WithValue(ctx, authenticationKey{}, auth)
+}
+
+func AuthenticationFrom(ctx context.Context) (auth Authentication, ok bool) {
+	auth, ok = ctx.Value(authenticationKey{}).(Authentication)
+	return
+}
+
+// HasAuthority tells whether the caller holds the authority.
+func HasAuthority(auth Authentication, authority string) bool {
+	for _, a := range auth.GetAuthorities() {
+		if a == authority {
+			return true
+		}
+	}
+	return false
+}
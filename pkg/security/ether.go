@@ -0,0 +1,149 @@
+package security
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"math/big"
+	"strings"
+)
+
+// secp256k1 domain parameters
+var (
+	curveP, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	curveN, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	curveB     = big.NewInt(7)
+	curveGx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	curveGy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+)
+
+var (
+	ErrSignatureInvalid = errors.New("signature is invalid")
+)
+
+// point is an affine point on secp256k1, a nil x stands for the point at infinity.
+type point struct {
+	x, y *big.Int
+}
+
+func (a point) isInfinity() bool {
+	return a.x == nil
+}
+
+func (a point) add(b point) point {
+	if a.isInfinity() {
+		return b
+	}
+	if b.isInfinity() {
+		return a
+	}
+	if a.x.Cmp(b.x) == 0 {
+		if a.y.Cmp(b.y) == 0 && a.y.Sign() != 0 {
+			return a.double()
+		}
+		return point{}
+	}
+	dx := new(big.Int).Sub(b.x, a.x)
+	dx.Mod(dx, curveP)
+	lambda := new(big.Int).Sub(b.y, a.y)
+	lambda.Mul(lambda, dx.ModInverse(dx, curveP))
+	lambda.Mod(lambda, curveP)
+	return a.line(b.x, lambda)
+}
+
+func (a point) double() point {
+	if a.isInfinity() || a.y.Sign() == 0 {
+		return point{}
+	}
+	lambda := new(big.Int).Mul(a.x, a.x)
+	lambda.Mul(lambda, big.NewInt(3))
+	lambda.Mul(lambda, new(big.Int).ModInverse(new(big.Int).Lsh(a.y, 1), curveP))
+	lambda.Mod(lambda, curveP)
+	return a.line(a.x, lambda)
+}
+
+// line returns the third intersection of the line through a with slope lambda, mirrored over the x-axis.
+func (a point) line(bx, lambda *big.Int) point {
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, a.x)
+	x.Sub(x, bx)
+	x.Mod(x, curveP)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, lambda)
+	y.Sub(y, a.y)
+	y.Mod(y, curveP)
+	return point{x, y}
+}
+
+func (a point) mul(k *big.Int) (result point) {
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.double()
+		if k.Bit(i) == 1 {
+			result = result.add(a)
+		}
+	}
+	return
+}
+
+// HashPersonalMessage hashes a message the way EIP-191 personal_sign does.
+func HashPersonalMessage(message string) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+	return hash.Sum(nil)
+}
+
+// RecoverAddress returns the ether account whose key produced the personal_sign signature of message.
+// The signature is the 65 bytes r || s || v in hex, v being 0/1 or 27/28.
+func RecoverAddress(message string, signature string) (address string, err error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return "", ErrSignatureInvalid
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", ErrSignatureInvalid
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(curveN) >= 0 || s.Cmp(curveN) >= 0 {
+		return "", ErrSignatureInvalid
+	}
+
+	// R = (r, y) where y^2 = r^3 + 7 and the parity of y is given by v
+	y := new(big.Int).Exp(r, big.NewInt(3), curveP)
+	y.Add(y, curveB)
+	y.Mod(y, curveP)
+	if y.ModSqrt(y, curveP) == nil {
+		return "", ErrSignatureInvalid
+	}
+	if y.Bit(0) != uint(v) {
+		y.Sub(curveP, y)
+	}
+
+	// Q = r^-1 (sR - eG)
+	e := new(big.Int).SetBytes(HashPersonalMessage(message))
+	rInv := new(big.Int).ModInverse(r, curveN)
+	u1 := new(big.Int).Mul(e, rInv)
+	u1.Neg(u1)
+	u1.Mod(u1, curveN)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, curveN)
+	q := point{curveGx, curveGy}.mul(u1).add(point{r, y}.mul(u2))
+	if q.isInfinity() {
+		return "", ErrSignatureInvalid
+	}
+	return addressOf(q), nil
+}
+
+func addressOf(q point) string {
+	pub := make([]byte, 64)
+	q.x.FillBytes(pub[:32])
+	q.y.FillBytes(pub[32:])
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(pub)
+	return "0x" + hex.EncodeToString(hash.Sum(nil)[12:])
+}
@@ -0,0 +1,68 @@
+package security
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// the accounts.sign example of web3.js, signed by the key
+// 0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318
+const (
+	vectorMessage   = "Some data"
+	vectorHash      = "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"
+	vectorSignature = "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd" +
+		"6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+	vectorAddress = "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23"
+)
+
+func TestHashPersonalMessage(t *testing.T) {
+	if hash := hex.EncodeToString(HashPersonalMessage(vectorMessage)); hash != vectorHash {
+		t.Fatalf("hash = %s, want %s", hash, vectorHash)
+	}
+}
+
+func TestRecoverAddress(t *testing.T) {
+	// v given as 0/1 instead of 27/28
+	rawV := strings.TrimSuffix(vectorSignature, "1c") + "01"
+	for _, signature := range []string{vectorSignature, strings.TrimPrefix(vectorSignature, "0x"), rawV} {
+		address, err := RecoverAddress(vectorMessage, signature)
+		if err != nil {
+			t.Fatalf("RecoverAddress(%s) error = %v", signature, err)
+		}
+		if address != vectorAddress {
+			t.Fatalf("RecoverAddress(%s) = %s, want %s", signature, address, vectorAddress)
+		}
+	}
+}
+
+func TestRecoverAddressOfOtherMessage(t *testing.T) {
+	address, err := RecoverAddress("Some other data", vectorSignature)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if address == vectorAddress {
+		t.Fatalf("a signature of another message recovered the signer")
+	}
+}
+
+func TestRecoverAddressInvalid(t *testing.T) {
+	r := vectorSignature[2:66]
+	s := vectorSignature[66:130]
+	zero := strings.Repeat("0", 64)
+	// no point on the curve has x = 5
+	offCurve := strings.Repeat("0", 63) + "5"
+	for name, signature := range map[string]string{
+		"not hex":   "0xzz",
+		"too short": vectorSignature[:128],
+		"v":         "0x" + r + s + "1d",
+		"zero r":    "0x" + zero + s + "1c",
+		"zero s":    "0x" + r + zero + "1c",
+		"r >= n":    "0x" + strings.ToLower(curveN.Text(16)) + s + "1c",
+		"off curve": "0x" + offCurve + s + "1c",
+	} {
+		if _, err := RecoverAddress(vectorMessage, signature); err != ErrSignatureInvalid {
+			t.Errorf("%s: error = %v, want %v", name, err, ErrSignatureInvalid)
+		}
+	}
+}
@@ -1,86 +1,116 @@
 package security
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"github.com/dgrijalva/jwt-go"
 	"time"
 )
 
+type TokenType string
+
 const (
-	secretKey      string = "secret"
-	issuer         string = "markets"
-	ExpirationDays int64  = 7
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
 )
 
 type Claim struct {
 	jwt.StandardClaims
+	Type TokenType `json:"typ"`
 }
 
-func ExtractUserName(tokenString string) (name string, err error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claim{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
+// ParseToken verifies the token with the key named by its kid header and returns its claim.
+func ParseToken(tokenString string) (claim *Claim, err error) {
+	ring, err := getKeyring()
 	if err != nil {
 		return
 	}
-	claims := token.Claims.(*Claim)
-	name = claims.Subject
-	return
-}
-
-func ExtractExpiration(tokenString string) (expiration time.Time, err error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claim{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
+	claim = &Claim{}
+	_, err = jwt.ParseWithClaims(tokenString, claim, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key := ring.keys[kid]
+		if key == nil || key.method.Alg() != token.Method.Alg() {
+			return nil, ErrTokenInvalid
+		}
+		return key.verifyKey, nil
 	})
 	if err != nil {
-		return
+		return nil, err
+	}
+	if !claim.VerifyIssuer(ring.issuer, true) {
+		return nil, ErrTokenInvalid
 	}
-	claims := token.Claims.(*Claim)
-	expiration = time.Unix(claims.ExpiresAt, 0)
 	return
 }
 
-func IsTokenExpired(tokenString string) (isExpired bool, err error) {
-	expiration, err := ExtractExpiration(tokenString)
+func ExtractUserName(tokenString string) (name string, err error) {
+	claim, err := ParseToken(tokenString)
 	if err != nil {
 		return
 	}
-	if expiration.Before(time.Now()) {
-		isExpired = true
-	} else {
-		isExpired = false
-	}
+	name = claim.Subject
 	return
 }
 
 func GenerateToken(auth Authentication) (tokenString string, err error) {
+	return generateToken(auth, AccessToken)
+}
+
+func GenerateRefreshToken(auth Authentication) (tokenString string, err error) {
+	return generateToken(auth, RefreshToken)
+}
+
+func generateToken(auth Authentication, tokenType TokenType) (tokenString string, err error) {
+	ring, err := getKeyring()
+	if err != nil {
+		return
+	}
+	expiration := ring.accessExpiration
+	if tokenType == RefreshToken {
+		expiration = ring.refreshExpiration
+	}
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return
+	}
+	now := time.Now()
 	claims := &Claim{
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Local().Add(time.Hour * time.Duration(ExpirationDays)).Unix(),
-			Issuer:    issuer,
+			Id:        hex.EncodeToString(id),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(expiration).Unix(),
+			Issuer:    ring.issuer,
 			Subject:   auth.GetName(),
 		},
+		Type: tokenType,
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err = token.SignedString([]byte(secretKey))
+	token := jwt.NewWithClaims(ring.active.method, claims)
+	token.Header["kid"] = ring.active.kid
+	tokenString, err = token.SignedString(ring.active.signKey)
 	if err != nil {
 		return
 	}
 	return
 }
 
-func ValidateToken(tokenString string, auth Authentication) (isValid bool, err error) {
-	name, err := ExtractUserName(tokenString)
+// ValidateToken tells whether the token is an unrevoked access token of auth.
+func ValidateToken(ctx context.Context, tokenString string, auth Authentication) (isValid bool, err error) {
+	claim, err := ParseToken(tokenString)
 	if err != nil {
 		return
 	}
-	isExpired, err := IsTokenExpired(tokenString)
+	if claim.Type != AccessToken || claim.Subject != auth.GetName() {
+		return false, nil
+	}
+	isRevoked, err := IsTokenRevoked(ctx, claim)
 	if err != nil {
 		return
 	}
-	if name != auth.GetName() || isExpired {
-		isValid = false
-	} else {
-		isValid = true
-	}
-	return
+	return !isRevoked, nil
 }
+
+var (
+	ErrTokenInvalid = errors.New("token is invalid")
+)
@@ -0,0 +1,141 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"io/ioutil"
+	"nftshopping-store-api/pkg/config"
+	"time"
+)
+
+var (
+	keyringInstance *keyring
+)
+
+func getKeyring() (instance *keyring, err error) {
+	if keyringInstance == nil {
+		instance, err = newKeyring()
+		if err != nil {
+			return nil, err
+		}
+		keyringInstance = instance
+	}
+	return keyringInstance, nil
+}
+
+// keyring holds every configured key by kid. Tokens are signed with the active key only,
+// the others stay here so tokens signed before a rotation can still be verified.
+type keyring struct {
+	issuer            string
+	accessExpiration  time.Duration
+	refreshExpiration time.Duration
+	active            *signingKey
+	keys              map[string]*signingKey
+}
+
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func newKeyring() (instance *keyring, err error) {
+	c, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	jwtConfig := c.Jwt
+	instance = &keyring{
+		issuer:            jwtConfig.Issuer,
+		accessExpiration:  jwtConfig.AccessExpiration,
+		refreshExpiration: jwtConfig.RefreshExpiration,
+		keys:              map[string]*signingKey{},
+	}
+	for _, keyConfig := range jwtConfig.Keys {
+		key, err := newSigningKey(keyConfig)
+		if err != nil {
+			return nil, err
+		}
+		instance.keys[key.kid] = key
+	}
+	instance.active = instance.keys[jwtConfig.ActiveKid]
+	if instance.active == nil || instance.active.signKey == nil {
+		return nil, fmt.Errorf("active jwt key %q can not sign", jwtConfig.ActiveKid)
+	}
+	return instance, nil
+}
+
+// newSigningKey loads a key, RS256/ES256 keys are PEM files and the private one may be
+// left out for keys that are only kept for verification.
+func newSigningKey(keyConfig config.JwtKey) (key *signingKey, err error) {
+	key = &signingKey{
+		kid:    keyConfig.Kid,
+		method: jwt.GetSigningMethod(keyConfig.Algorithm),
+	}
+	switch key.method {
+	case jwt.SigningMethodHS256:
+		if len(keyConfig.Secret) == 0 {
+			return nil, ErrKeyInvalid
+		}
+		key.signKey = []byte(keyConfig.Secret)
+		key.verifyKey = key.signKey
+	case jwt.SigningMethodRS256:
+		if len(keyConfig.PrivateKey) > 0 {
+			pem, err := ioutil.ReadFile(keyConfig.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		}
+		if len(keyConfig.PublicKey) > 0 {
+			pem, err := ioutil.ReadFile(keyConfig.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case jwt.SigningMethodES256:
+		if len(keyConfig.PrivateKey) > 0 {
+			pem, err := ioutil.ReadFile(keyConfig.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseECPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		}
+		if len(keyConfig.PublicKey) > 0 {
+			pem, err := ioutil.ReadFile(keyConfig.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey, err = jwt.ParseECPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("jwt algorithm %q of key %q is not supported", keyConfig.Algorithm, keyConfig.Kid)
+	}
+	if key.verifyKey == nil {
+		return nil, ErrKeyInvalid
+	}
+	return key, nil
+}
+
+var (
+	ErrKeyInvalid = errors.New("jwt key is invalid")
+)
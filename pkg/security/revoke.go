@@ -0,0 +1,36 @@
+package security
+
+import (
+	"context"
+	"nftshopping-store-api/pkg/caches"
+	"time"
+)
+
+const revokedTokenPrefix = "jwt:revoked:"
+
+// RevokeToken puts the token on the denylist until it would have expired anyway. isRevoked
+// tells whether this call revoked it, false when the token was already revoked or expired,
+// so only one of the callers racing on the same token gets to use it.
+func RevokeToken(ctx context.Context, claim *Claim) (isRevoked bool, err error) {
+	ttl := time.Until(time.Unix(claim.ExpiresAt, 0))
+	if ttl <= 0 {
+		return
+	}
+	client, err := caches.GetRedis()
+	if err != nil {
+		return
+	}
+	return client.SetNX(ctx, revokedTokenPrefix+claim.Id, string(claim.Type), ttl).Result()
+}
+
+func IsTokenRevoked(ctx context.Context, claim *Claim) (isRevoked bool, err error) {
+	client, err := caches.GetRedis()
+	if err != nil {
+		return
+	}
+	count, err := client.Exists(ctx, revokedTokenPrefix+claim.Id).Result()
+	if err != nil {
+		return
+	}
+	return count > 0, nil
+}
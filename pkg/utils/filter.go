@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrFilterInvalid = errors.New("filter is invalid")
+
+type Operator string
+
+const (
+	OpEq     Operator = "="
+	OpNe     Operator = "!="
+	OpGt     Operator = ">"
+	OpGte    Operator = ">="
+	OpLt     Operator = "<"
+	OpLte    Operator = "<="
+	OpIn     Operator = "~in~"
+	OpNin    Operator = "~nin~"
+	OpExists Operator = "~exists~"
+)
+
+// Condition is a validated term of a filter on a stored field, Values holds one value
+// except for OpIn and OpNin.
+type Condition struct {
+	Key      string        `json:"key"`
+	Operator Operator      `json:"operator"`
+	Values   []interface{} `json:"values"`
+}
+
+// FilterField is a field of a resource that can be filtered on, Value converts the text of the
+// query to the stored type; only Ordered fields accept the range operators.
+type FilterField struct {
+	Key     string
+	Ordered bool
+	Value   func(text string) (interface{}, error)
+}
+
+// FilterFields is the schema of a resource, mapping the field names of its api to the stored ones.
+type FilterFields map[string]FilterField
+
+func StringField(key string) FilterField {
+	return FilterField{Key: key, Value: func(text string) (interface{}, error) {
+		return text, nil
+	}}
+}
+
+func IntField(key string) FilterField {
+	return FilterField{Key: key, Ordered: true, Value: func(text string) (interface{}, error) {
+		return strconv.Atoi(text)
+	}}
+}
+
+func TimeField(key string) FilterField {
+	return FilterField{Key: key, Ordered: true, Value: func(text string) (interface{}, error) {
+		return time.Parse(time.RFC3339, text)
+	}}
+}
+
+var termOfFilter = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9.]*)\s*(~[a-z]+~|>=|<=|!=|=|>|<)\s*(.*)$`)
+
+// ParseFilter reads an expression like "price>=100;properties~in~(fire,water);brandId!=x",
+// the terms are separated by semicolons and must all match.
+func ParseFilter(expression string, fields FilterFields) (conditions []Condition, err error) {
+	for _, term := range strings.Split(expression, ";") {
+		term = strings.TrimSpace(term)
+		if len(term) == 0 {
+			continue
+		}
+		matches := termOfFilter.FindStringSubmatch(term)
+		if matches == nil {
+			return nil, fmt.Errorf("%w: term %s can not be read", ErrFilterInvalid, term)
+		}
+		name, operator, text := matches[1], Operator(matches[2]), strings.TrimSpace(matches[3])
+		field, isExist := fields[name]
+		if !isExist {
+			return nil, fmt.Errorf("%w: field %s can not be filtered", ErrFilterInvalid, name)
+		}
+		condition := Condition{Key: field.Key, Operator: operator}
+		switch operator {
+		case OpEq, OpNe:
+			value, err := valueOfFilter(field, name, text)
+			if err != nil {
+				return nil, err
+			}
+			condition.Values = []interface{}{value}
+		case OpGt, OpGte, OpLt, OpLte:
+			if !field.Ordered {
+				return nil, fmt.Errorf("%w: field %s has no range", ErrFilterInvalid, name)
+			}
+			value, err := valueOfFilter(field, name, text)
+			if err != nil {
+				return nil, err
+			}
+			condition.Values = []interface{}{value}
+		case OpIn, OpNin:
+			if !strings.HasPrefix(text, "(") || !strings.HasSuffix(text, ")") {
+				return nil, fmt.Errorf("%w: values of %s must be in parentheses", ErrFilterInvalid, name)
+			}
+			for _, item := range strings.Split(text[1:len(text)-1], ",") {
+				value, err := valueOfFilter(field, name, strings.TrimSpace(item))
+				if err != nil {
+					return nil, err
+				}
+				condition.Values = append(condition.Values, value)
+			}
+		case OpExists:
+			exists, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, fmt.Errorf("%w: exists of %s must be true or false", ErrFilterInvalid, name)
+			}
+			condition.Values = []interface{}{exists}
+		default:
+			return nil, fmt.Errorf("%w: operator %s is unknown", ErrFilterInvalid, operator)
+		}
+		conditions = append(conditions, condition)
+	}
+	return
+}
+
+func valueOfFilter(field FilterField, name string, text string) (value interface{}, err error) {
+	value, err = field.Value(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: value %s of %s is invalid", ErrFilterInvalid, text, name)
+	}
+	return
+}
@@ -1,19 +1,81 @@
 package utils
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
-var ErrCovertContent = errors.New("fail to covert content")
+var (
+	ErrCovertContent = errors.New("fail to covert content")
+	ErrSortInvalid   = errors.New("sort is invalid")
+	ErrPageInvalid   = errors.New("page is invalid")
+)
 
+// Page is a page of either pagination mode, NextCursor is only set in cursor mode
+// and is empty on the last page; Total and TotalPage are only counted in offset mode.
 type Page struct {
-	Size      int         `json:"size"`
-	Page      int         `json:"page"`
-	Total     int64       `json:"total"`
-	TotalPage int64       `json:"totalPage"`
-	Content   interface{} `json:"content"`
+	Size       int         `json:"size"`
+	Page       int         `json:"page,omitempty"`
+	Total      int64       `json:"total,omitempty"`
+	TotalPage  int64       `json:"totalPage,omitempty"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+	Content    interface{} `json:"content"`
 }
 
+// Pageable selects offset mode by Page, or cursor mode when Cursor is set;
+// an empty Cursor asks for the first page.
 type Pageable struct {
-	Size int            `json:"size"`
-	Page int            `json:"page"`
-	Sort map[string]int `json:"sorts"`
+	Size   int     `json:"size"`
+	Page   int     `json:"page"`
+	Sort   []Sort  `json:"sorts"`
+	Cursor *string `json:"cursor"`
+}
+
+func (pageable Pageable) IsCursor() bool {
+	return pageable.Cursor != nil
+}
+
+// Sort is a stored field to sort by, Order is 1 for ascending and -1 for descending.
+type Sort struct {
+	Key   string `json:"key"`
+	Order int    `json:"order"`
+}
+
+// SortFields is the whitelist of a resource, mapping the field names of its api to the stored ones.
+type SortFields map[string]string
+
+// ParseSort reads an expression like "price:desc,createAt:asc" in its order,
+// a field without an order is ascending.
+func ParseSort(expression string, fields SortFields) (sorts []Sort, err error) {
+	seen := map[string]bool{}
+	for _, term := range strings.Split(expression, ",") {
+		term = strings.TrimSpace(term)
+		if len(term) == 0 {
+			continue
+		}
+		name, direction := term, "asc"
+		if i := strings.Index(term, ":"); i >= 0 {
+			name, direction = strings.TrimSpace(term[:i]), strings.ToLower(strings.TrimSpace(term[i+1:]))
+		}
+		key, isExist := fields[name]
+		if !isExist {
+			return nil, fmt.Errorf("%w: field %s can not be sorted", ErrSortInvalid, name)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: field %s is duplicate", ErrSortInvalid, name)
+		}
+		seen[key] = true
+		var order int
+		switch direction {
+		case "asc", "1":
+			order = 1
+		case "desc", "-1":
+			order = -1
+		default:
+			return nil, fmt.Errorf("%w: order %s is unknown", ErrSortInvalid, direction)
+		}
+		sorts = append(sorts, Sort{Key: key, Order: order})
+	}
+	return
 }